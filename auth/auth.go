@@ -3,6 +3,7 @@ package auth
 import (
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"time"
 
@@ -10,11 +11,12 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// The environment may also come from the process itself, as in containers
+// and tests, so a missing .env file is not fatal
 func init() {
 	err := godotenv.Load()
 	if err != nil {
-		fmt.Printf("Error loading .env file: %v\n", err)
-		os.Exit(1)
+		log.Printf("Warning: Error loading .env file: %v", err)
 	}
 }
 
@@ -29,6 +31,9 @@ type JWTClaim struct {
 
 // GenerateJWT creates a token with an additional role claim.
 func GenerateJWT(email string, username string, role string) (tokenString string, err error) {
+	if !IsValidRole(role) {
+		return "", ErrInvalidRole{Role: role}
+	}
 	expirationTime := time.Now().Add(1000 * time.Hour)
 	claims := &JWTClaim{
 		Email:    email,
@@ -54,6 +59,11 @@ func ValidateToken(signedToken string) (claims *JWTClaim, err error) {
 		signedToken,
 		&JWTClaim{},
 		func(token *jwt.Token) (interface{}, error) {
+			// Only accept the method GenerateJWT signs with, so "none" or an
+			// asymmetric algorithm can't be used to forge a token
+			if token.Method != jwt.SigningMethodHS256 {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
 			return jwtKey, nil
 		},
 	)
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func testClaims() *JWTClaim {
+	return &JWTClaim{
+		Username: "alice",
+		Role:     RoleStudent,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+}
+
+func TestValidateTokenAcceptsGeneratedToken(t *testing.T) {
+	token, err := GenerateJWT("alice@example.com", "alice", RoleStudent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ValidateToken("Bearer " + token)
+	if err != nil {
+		t.Fatalf("ValidateToken() error = %v", err)
+	}
+	if claims.Username != "alice" || claims.Role != RoleStudent {
+		t.Errorf("claims = %+v, want student alice", claims)
+	}
+}
+
+func TestValidateTokenRejectsAlgNone(t *testing.T) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateToken(token); err == nil {
+		t.Error("a token with alg none was accepted")
+	}
+}
+
+func TestValidateTokenRejectsOtherMethods(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		key    interface{}
+	}{
+		{"HS512 with the same key", jwt.SigningMethodHS512, jwtKey},
+		{"RS256", jwt.SigningMethodRS256, rsaKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwt.NewWithClaims(tt.method, testClaims()).SignedString(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ValidateToken(token); err == nil {
+				t.Errorf("a token signed with %s was accepted", tt.method.Alg())
+			}
+		})
+	}
+}
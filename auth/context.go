@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// ClaimsKey is the gin context key AuthMiddleware stores the parsed claims under
+const ClaimsKey = "claims"
+
+// ClaimsFromContext returns the claims of the token the request was
+// authenticated with, ok is false on routes without AuthMiddleware
+func ClaimsFromContext(c *gin.Context) (*JWTClaim, bool) {
+	value, exists := c.Get(ClaimsKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*JWTClaim)
+	return claims, ok
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Roles a token can carry
+const (
+	RoleStudent = "student"
+	RoleTeacher = "teacher"
+	RoleAdmin   = "admin"
+)
+
+// allowedRoles is the single source of truth for the roles accepted by token
+// generation and by role checks on routes
+var allowedRoles = map[string]bool{
+	RoleStudent: true,
+	RoleTeacher: true,
+	RoleAdmin:   true,
+}
+
+// IsValidRole reports whether role is one of the allowed roles
+func IsValidRole(role string) bool {
+	return allowedRoles[role]
+}
+
+// Roles lists the allowed roles in alphabetical order
+func Roles() []string {
+	roles := make([]string, 0, len(allowedRoles))
+	for role := range allowedRoles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// ErrInvalidRole is returned for roles outside the allowed set
+type ErrInvalidRole struct {
+	Role string
+}
+
+func (e ErrInvalidRole) Error() string {
+	return fmt.Sprintf("invalid role specified: %q", e.Role)
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGenerateJWTRejectsUnknownRole(t *testing.T) {
+	_, err := GenerateJWT("alice@example.com", "alice", "root")
+	var invalid ErrInvalidRole
+	if !errors.As(err, &invalid) || invalid.Role != "root" {
+		t.Errorf("GenerateJWT(root) error = %v, want ErrInvalidRole", err)
+	}
+}
+
+func TestGenerateJWTAcceptsEveryRole(t *testing.T) {
+	for _, role := range Roles() {
+		token, err := GenerateJWT("alice@example.com", "alice", role)
+		if err != nil {
+			t.Fatalf("GenerateJWT(%s): %v", role, err)
+		}
+		claims, err := ValidateToken(token)
+		if err != nil || claims.Role != role {
+			t.Errorf("token for %s validated as %+v, %v", role, claims, err)
+		}
+	}
+}
+
+func TestAddedRoleIsAcceptedEverywhere(t *testing.T) {
+	allowedRoles["reviewer"] = true
+	t.Cleanup(func() { delete(allowedRoles, "reviewer") })
+
+	if !IsValidRole("reviewer") {
+		t.Error("IsValidRole does not accept the added role")
+	}
+	if _, err := GenerateJWT("alice@example.com", "alice", "reviewer"); err != nil {
+		t.Errorf("GenerateJWT does not accept the added role: %v", err)
+	}
+	if want := []string{"admin", "reviewer", "student", "teacher"}; !reflect.DeepEqual(Roles(), want) {
+		t.Errorf("Roles() = %v, want %v", Roles(), want)
+	}
+}
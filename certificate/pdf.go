@@ -0,0 +1,110 @@
+// Package certificate renders course completion certificates
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Certificate holds what is printed on a course completion certificate
+type Certificate struct {
+	StudentName string
+	CourseName  string
+	Grade       string
+	Text        string
+	Date        time.Time
+}
+
+// line is a line of text placed on the page, Y counted from the bottom
+type line struct {
+	size int
+	y    int
+	text string
+}
+
+// layout places the title and body of the configured template on the page,
+// spreading the body lines evenly between the title and the bottom margin
+func layout(cert Certificate) []line {
+	tmpl := currentTemplate()
+	lines := []line{{size: 32, y: 460, text: fill(tmpl.Title, cert)}}
+	if len(tmpl.Body) == 0 {
+		return lines
+	}
+
+	step := 280 / len(tmpl.Body)
+	if step > 48 {
+		step = 48
+	}
+	for i, l := range tmpl.Body {
+		size := 16
+		if strings.HasPrefix(l, emphasisPrefix) {
+			size = 24
+			l = strings.TrimPrefix(l, emphasisPrefix)
+		}
+		lines = append(lines, line{size: size, y: 400 - i*step, text: fill(l, cert)})
+	}
+	return lines
+}
+
+// RenderPDF lays the certificate out on a single landscape A4 page. It writes
+// the PDF by hand with the built-in Helvetica font, so no font files or
+// third-party libraries are needed.
+func RenderPDF(cert Certificate) []byte {
+	lines := layout(cert)
+
+	var content bytes.Buffer
+	for _, l := range lines {
+		// Helvetica glyphs average about half the font size, close enough to center
+		x := (842 - len(l.text)*l.size/2) / 2
+		if x < 36 {
+			x = 36
+		}
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d %d Td (%s) Tj ET\n", l.size, x, l.y, escape(l.text))
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 842 595] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes()
+}
+
+// escape makes text safe inside a PDF string literal. Characters outside
+// Latin-1 can't be shown with the standard font and are replaced.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteRune(' ')
+		case r < 0x20 || r > 0xFF:
+			b.WriteRune('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// maxBodyLines is how many body lines fit on the page under the title
+const maxBodyLines = 10
+
+// emphasisPrefix marks a body line printed in a larger font, such as the
+// student or course name
+const emphasisPrefix = "# "
+
+// placeholderPattern matches a {{name}} placeholder of a template
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z_]*)\s*\}\}`)
+
+// placeholders lists what a template may refer to and how each is filled in
+var placeholders = map[string]func(Certificate) string{
+	"student": func(cert Certificate) string { return cert.StudentName },
+	"course":  func(cert Certificate) string { return cert.CourseName },
+	"grade":   func(cert Certificate) string { return cert.Grade },
+	"date":    func(cert Certificate) string { return cert.Date.Format("January 2, 2006") },
+	"note":    func(cert Certificate) string { return cert.Text },
+}
+
+// Template is the wording of a certificate. Title is printed at the top, then
+// each body line below it, a line starting with "# " in a larger font. Both
+// may use the {{student}}, {{course}}, {{grade}}, {{date}} and {{note}}
+// placeholders.
+type Template struct {
+	Title string
+	Body  []string
+}
+
+// DefaultTemplate is the certificate wording used unless one is configured
+var DefaultTemplate = Template{
+	Title: "Certificate of Completion",
+	Body: []string{
+		"This certifies that",
+		"# {{student}}",
+		"has successfully completed the course",
+		"# {{course}}",
+		"Grade: {{grade}}",
+		"{{note}}",
+	},
+}
+
+var (
+	templateMu sync.RWMutex
+	current    = DefaultTemplate
+)
+
+// SetTemplate validates tmpl and uses it for every certificate rendered from
+// now on
+func SetTemplate(tmpl Template) error {
+	if err := tmpl.Validate(); err != nil {
+		return err
+	}
+	templateMu.Lock()
+	current = tmpl
+	templateMu.Unlock()
+	return nil
+}
+
+func currentTemplate() Template {
+	templateMu.RLock()
+	defer templateMu.RUnlock()
+	return current
+}
+
+// ParseTemplate reads a template from text, the first non-blank line is the
+// title and every following line is a body line
+func ParseTemplate(text string) (Template, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return Template{}, errors.New("certificate template is empty")
+	}
+
+	tmpl := Template{Title: strings.TrimSpace(lines[0])}
+	for _, l := range lines[1:] {
+		tmpl.Body = append(tmpl.Body, strings.TrimRight(l, " \t"))
+	}
+	return tmpl, tmpl.Validate()
+}
+
+// Validate checks that the template has a title, fits on the page and only
+// refers to known placeholders
+func (t Template) Validate() error {
+	if strings.TrimSpace(t.Title) == "" {
+		return errors.New("certificate template needs a title")
+	}
+	if len(t.Body) > maxBodyLines {
+		return fmt.Errorf("certificate template has %d body lines, at most %d fit on the page", len(t.Body), maxBodyLines)
+	}
+	for i, l := range append([]string{t.Title}, t.Body...) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(l, -1) {
+			if _, ok := placeholders[match[1]]; !ok {
+				return fmt.Errorf("certificate template line %d: unknown placeholder %s", i+1, match[0])
+			}
+		}
+		if rest := placeholderPattern.ReplaceAllString(l, ""); strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+			return fmt.Errorf("certificate template line %d: malformed placeholder", i+1)
+		}
+	}
+	return nil
+}
+
+// fill substitutes the placeholders of text with the certificate's values
+func fill(text string, cert Certificate) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if value, ok := placeholders[name]; ok {
+			return value(cert)
+		}
+		return match
+	})
+}
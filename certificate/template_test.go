@@ -0,0 +1,101 @@
+package certificate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useTemplate makes tmpl the configured template for the test
+func useTemplate(t *testing.T, tmpl Template) {
+	t.Helper()
+	if err := SetTemplate(tmpl); err != nil {
+		t.Fatalf("SetTemplate: %v", err)
+	}
+	t.Cleanup(func() { SetTemplate(DefaultTemplate) })
+}
+
+var testCertificate = Certificate{
+	StudentName: "Alice Student",
+	CourseName:  "Learn Go",
+	Grade:       "9/10",
+	Text:        "With honors",
+	Date:        time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+}
+
+func TestRenderPDFSubstitutesPlaceholders(t *testing.T) {
+	useTemplate(t, Template{
+		Title: "DZ Skills Academy",
+		Body: []string{
+			"# {{ student }}",
+			"completed {{course}} with {{grade}} on {{date}}",
+			"{{note}}",
+		},
+	})
+
+	pdf := RenderPDF(testCertificate)
+	for _, want := range []string{
+		"(DZ Skills Academy)",
+		"(Alice Student)",
+		"(completed Learn Go with 9/10 on March 1, 2026)",
+		"(With honors)",
+	} {
+		if !bytes.Contains(pdf, []byte(want)) {
+			t.Errorf("PDF does not contain %s", want)
+		}
+	}
+	if bytes.Contains(pdf, []byte("{{")) {
+		t.Error("PDF still contains a placeholder")
+	}
+}
+
+func TestRenderPDFEmphasizesMarkedLines(t *testing.T) {
+	useTemplate(t, Template{Title: "Certificate", Body: []string{"# {{student}}", "{{course}}"}})
+
+	lines := layout(testCertificate)
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want the title and 2 body lines", len(lines))
+	}
+	if lines[1].text != "Alice Student" || lines[1].size <= lines[2].size {
+		t.Errorf("lines = %+v, want the student name without its marker in a larger font", lines)
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate("\r\n  Academy Award  \r\nAwarded to {{student}}\r\n\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Title != "Academy Award" {
+		t.Errorf("title = %q, want Academy Award", tmpl.Title)
+	}
+	if len(tmpl.Body) != 1 || tmpl.Body[0] != "Awarded to {{student}}" {
+		t.Errorf("body = %q, want the one line after the title", tmpl.Body)
+	}
+}
+
+func TestParseTemplateRejectsInvalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":               " \n\n",
+		"unknown placeholder": "Title\nAwarded to {{teacher}}",
+		"malformed":           "Title\nAwarded to {{student}",
+		"too long":            "Title" + strings.Repeat("\nline", maxBodyLines+1),
+	}
+	for name, text := range tests {
+		if _, err := ParseTemplate(text); err == nil {
+			t.Errorf("%s template was accepted", name)
+		}
+	}
+}
+
+func TestSetTemplateKeepsCurrentOnError(t *testing.T) {
+	useTemplate(t, Template{Title: "Academy"})
+
+	if err := SetTemplate(Template{Title: "{{nope}}"}); err == nil {
+		t.Fatal("invalid template was accepted")
+	}
+	if got := currentTemplate().Title; got != "Academy" {
+		t.Errorf("title = %q, want the previous template kept", got)
+	}
+}
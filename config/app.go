@@ -0,0 +1,24 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// defaultBaseURL is where the API is reached when API_BASE_URL is not set,
+// matching the port main listens on
+const defaultBaseURL = "http://localhost:8080"
+
+// BaseURL reads the public URL of the API from API_BASE_URL, without a trailing slash
+func BaseURL() string {
+	raw := strings.TrimSpace(os.Getenv("API_BASE_URL"))
+	if raw == "" {
+		return defaultBaseURL
+	}
+	return strings.TrimRight(raw, "/")
+}
+
+// DocsURL is the address of the Swagger UI served by this deployment
+func DocsURL() string {
+	return BaseURL() + "/docs/index.html#/"
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestIdentity is who every request is treated as when authentication is
+// disabled for local testing
+type TestIdentity struct {
+	Username string
+	Email    string
+	Role     string
+}
+
+// LoadAuthBypass reads the AUTH_DISABLED dev flag. It returns nil unless the
+// flag is true, in which case the identity comes from AUTH_TEST_USERNAME,
+// AUTH_TEST_EMAIL and AUTH_TEST_ROLE (teacher by default). It refuses to
+// disable authentication when GIN_MODE=release.
+func LoadAuthBypass() (*TestIdentity, error) {
+	raw := os.Getenv("AUTH_DISABLED")
+	if raw == "" {
+		return nil, nil
+	}
+	disabled, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTH_DISABLED value %q: %v", raw, err)
+	}
+	if !disabled {
+		return nil, nil
+	}
+	if os.Getenv(gin.EnvGinMode) == gin.ReleaseMode {
+		return nil, fmt.Errorf("AUTH_DISABLED cannot be used when %s=%s", gin.EnvGinMode, gin.ReleaseMode)
+	}
+
+	identity := &TestIdentity{
+		Username: os.Getenv("AUTH_TEST_USERNAME"),
+		Email:    os.Getenv("AUTH_TEST_EMAIL"),
+		Role:     os.Getenv("AUTH_TEST_ROLE"),
+	}
+	if identity.Username == "" {
+		identity.Username = "test"
+	}
+	if identity.Role == "" {
+		identity.Role = "teacher"
+	}
+	return identity, nil
+}
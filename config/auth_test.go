@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLoadAuthBypass(t *testing.T) {
+	tests := []struct {
+		name     string
+		disabled string
+		mode     string
+		role     string
+		want     *TestIdentity
+		wantErr  bool
+	}{
+		{"unset", "", "", "", nil, false},
+		{"off", "false", "", "", nil, false},
+		{"dev", "true", gin.DebugMode, "", &TestIdentity{Username: "test", Role: "teacher"}, false},
+		{"dev with role", "1", "", "admin", &TestIdentity{Username: "test", Role: "admin"}, false},
+		{"release", "true", gin.ReleaseMode, "", nil, true},
+		{"off in release", "false", gin.ReleaseMode, "", nil, false},
+		{"invalid", "yes please", "", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AUTH_DISABLED", tt.disabled)
+			t.Setenv(gin.EnvGinMode, tt.mode)
+			t.Setenv("AUTH_TEST_USERNAME", "")
+			t.Setenv("AUTH_TEST_EMAIL", "")
+			t.Setenv("AUTH_TEST_ROLE", tt.role)
+
+			got, err := LoadAuthBypass()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadAuthBypass() error = %v, want error %t", err, tt.wantErr)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("LoadAuthBypass() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cuddest/dz-skills/certificate"
+)
+
+// LoadCertificateTemplate reads the certificate wording from the file named by
+// CERTIFICATE_TEMPLATE_FILE, falling back to the default wording when unset.
+// The first line of the file is the title, the following ones the body.
+func LoadCertificateTemplate() (certificate.Template, error) {
+	path := os.Getenv("CERTIFICATE_TEMPLATE_FILE")
+	if path == "" {
+		return certificate.DefaultTemplate, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return certificate.Template{}, fmt.Errorf("failed to read CERTIFICATE_TEMPLATE_FILE: %v", err)
+	}
+	tmpl, err := certificate.ParseTemplate(string(raw))
+	if err != nil {
+		return certificate.Template{}, fmt.Errorf("invalid CERTIFICATE_TEMPLATE_FILE %s: %v", path, err)
+	}
+	return tmpl, nil
+}
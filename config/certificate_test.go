@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuddest/dz-skills/certificate"
+)
+
+func TestLoadCertificateTemplateDefault(t *testing.T) {
+	t.Setenv("CERTIFICATE_TEMPLATE_FILE", "")
+
+	tmpl, err := LoadCertificateTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Title != certificate.DefaultTemplate.Title {
+		t.Errorf("title = %q, want the default", tmpl.Title)
+	}
+}
+
+func TestLoadCertificateTemplateFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "certificate.txt")
+	if err := os.WriteFile(path, []byte("Academy\nAwarded to {{student}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CERTIFICATE_TEMPLATE_FILE", path)
+
+	tmpl, err := LoadCertificateTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Title != "Academy" || len(tmpl.Body) != 1 {
+		t.Errorf("template = %+v, want the file's title and body", tmpl)
+	}
+}
+
+func TestLoadCertificateTemplateRejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "certificate.txt")
+	if err := os.WriteFile(path, []byte("Academy\nAwarded to {{teacher}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CERTIFICATE_TEMPLATE_FILE", path)
+
+	if _, err := LoadCertificateTemplate(); err == nil {
+		t.Error("template with an unknown placeholder was accepted")
+	}
+
+	t.Setenv("CERTIFICATE_TEMPLATE_FILE", filepath.Join(t.TempDir(), "missing.txt"))
+	if _, err := LoadCertificateTemplate(); err == nil {
+		t.Error("missing template file was accepted")
+	}
+}
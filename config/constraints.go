@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// checkConstraint is a CHECK constraint the database enforces on top of the
+// validators, so direct writes and imports can't break the invariant either
+type checkConstraint struct {
+	table string
+	name  string
+	check string
+}
+
+var checkConstraints = []checkConstraint{
+	{table: "cratings", name: "chk_cratings_rating", check: "rating BETWEEN 1 AND 5"},
+	{table: "feedbacks", name: "chk_feedbacks_review", check: "review BETWEEN 1 AND 5"},
+}
+
+// addCheckConstraints creates the missing check constraints. They are added
+// NOT VALID: rows written before the constraint existed are left as they are
+// instead of failing startup, while every new insert or update is checked.
+func addCheckConstraints(db *gorm.DB) error {
+	for _, cc := range checkConstraints {
+		if db.Migrator().HasConstraint(cc.table, cc.name) {
+			continue
+		}
+		stmt := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s) NOT VALID", cc.table, cc.name, cc.check)
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to add constraint %s: %v", cc.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-contrib/cors"
+)
+
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	defaultCORSHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+)
+
+// LoadCORSConfig builds the CORS settings for the given origins. Methods, headers
+// and credentials can be overridden with CORS_ALLOW_METHODS, CORS_ALLOW_HEADERS
+// (comma separated) and CORS_ALLOW_CREDENTIALS.
+func LoadCORSConfig(origins []string) (cors.Config, error) {
+	allowCredentials := true
+	if raw := os.Getenv("CORS_ALLOW_CREDENTIALS"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return cors.Config{}, fmt.Errorf("invalid CORS_ALLOW_CREDENTIALS value %q: %v", raw, err)
+		}
+		allowCredentials = parsed
+	}
+
+	// Browsers refuse credentialed responses for a wildcard origin, see the CORS spec
+	if allowCredentials {
+		for _, origin := range origins {
+			if origin == "*" {
+				return cors.Config{}, fmt.Errorf("CORS credentials cannot be allowed together with a wildcard origin")
+			}
+		}
+	}
+
+	return cors.Config{
+		AllowOrigins:     origins,
+		AllowMethods:     envList("CORS_ALLOW_METHODS", defaultCORSMethods),
+		AllowHeaders:     envList("CORS_ALLOW_HEADERS", defaultCORSHeaders),
+		AllowCredentials: allowCredentials,
+	}, nil
+}
+
+// envList reads a comma separated list from the environment, or returns fallback when unset
+func envList(key string, fallback []string) []string {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadCORSConfigDefaults(t *testing.T) {
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "")
+	t.Setenv("CORS_ALLOW_METHODS", "")
+	t.Setenv("CORS_ALLOW_HEADERS", "")
+
+	cfg, err := LoadCORSConfig([]string{"https://dzskills.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.AllowCredentials {
+		t.Error("credentials are not allowed by default")
+	}
+	if !reflect.DeepEqual(cfg.AllowMethods, defaultCORSMethods) {
+		t.Errorf("methods = %v, want %v", cfg.AllowMethods, defaultCORSMethods)
+	}
+	if !reflect.DeepEqual(cfg.AllowHeaders, defaultCORSHeaders) {
+		t.Errorf("headers = %v, want %v", cfg.AllowHeaders, defaultCORSHeaders)
+	}
+}
+
+func TestLoadCORSConfigFromEnv(t *testing.T) {
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "false")
+	t.Setenv("CORS_ALLOW_METHODS", " GET, POST ,,")
+	t.Setenv("CORS_ALLOW_HEADERS", "Authorization")
+
+	cfg, err := LoadCORSConfig([]string{"https://dzskills.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.AllowCredentials {
+		t.Error("credentials are allowed despite CORS_ALLOW_CREDENTIALS=false")
+	}
+	if want := []string{"GET", "POST"}; !reflect.DeepEqual(cfg.AllowMethods, want) {
+		t.Errorf("methods = %v, want %v", cfg.AllowMethods, want)
+	}
+	if want := []string{"Authorization"}; !reflect.DeepEqual(cfg.AllowHeaders, want) {
+		t.Errorf("headers = %v, want %v", cfg.AllowHeaders, want)
+	}
+}
+
+func TestLoadCORSConfigRejectsWildcardWithCredentials(t *testing.T) {
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	if _, err := LoadCORSConfig([]string{"https://dzskills.com", "*"}); err == nil {
+		t.Error("wildcard origin with credentials was accepted")
+	}
+
+	// Without credentials a wildcard is fine
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "false")
+	if _, err := LoadCORSConfig([]string{"*"}); err != nil {
+		t.Errorf("wildcard origin without credentials: %v", err)
+	}
+}
+
+func TestLoadCORSConfigRejectsInvalidCredentials(t *testing.T) {
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "maybe")
+	if _, err := LoadCORSConfig([]string{"https://dzskills.com"}); err == nil {
+		t.Error("invalid CORS_ALLOW_CREDENTIALS was accepted")
+	}
+}
@@ -30,9 +30,27 @@ func ConnectDB() (*gorm.DB, error) {
 	}
 	log.Println("Connected to Database!")
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to access the connection pool: %v", err)
+	}
+	pool := LoadPoolSettings()
+	pool.Apply(sqlDB)
+	log.Printf("Connection pool: max open %d, max idle %d, max lifetime %s",
+		pool.MaxOpenConns, pool.MaxIdleConns, pool.ConnMaxLifetime)
+
+	if err := dedupeCratings(db); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %v", err)
+	}
 	if err := runMigrations(db); err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %v", err)
 	}
+	if err := addCheckConstraints(db); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %v", err)
+	}
+	if err := recordSchemaVersion(db); err != nil {
+		return nil, fmt.Errorf("failed to record schema version: %v", err)
+	}
 	log.Println("Database Migration Completed!")
 	DB = db
 	return db, nil
@@ -49,11 +67,16 @@ func runMigrations(db *gorm.DB) error {
 		&models.Teacher{},
 		&models.Article{},
 		&models.Video{},
+		&models.VideoProgress{},
 		&models.StudentCourse{},
 		&models.Crating{},
 		&models.Exam{},
 		&models.Feedback{},
 		&models.Question{},
 		&models.ExamQuizz{},
+		&models.AuditLog{},
+		&models.StudentExamAnswer{},
+		&models.ExamAttempt{},
+		&models.SchemaMigration{},
 	)
 }
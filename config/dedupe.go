@@ -0,0 +1,109 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cuddest/dz-skills/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// cratingsUniqueIndex is the index AutoMigrate creates on (course_id, student_id)
+	cratingsUniqueIndex = "idx_cratings_course_student"
+
+	// Ratings have no timestamp, so the newest of a pair is the row written
+	// by the latest transaction, xmin, or the later tuple within one
+	deleteDuplicateCratingsQuery = `
+		DELETE FROM cratings a
+		USING cratings b
+		WHERE a.course_id = b.course_id AND a.student_id = b.student_id
+			AND (a.xmin::text::bigint, a.ctid) < (b.xmin::text::bigint, b.ctid)
+		RETURNING a.course_id`
+
+	// Same as refreshCourseRating in the controllers
+	refreshCourseRatingQuery = `
+		UPDATE courses
+		SET average_rating = r.average_rating, ratings_count = r.total_ratings
+		FROM (
+			SELECT COALESCE(AVG(rating), 0) AS average_rating, COUNT(*) AS total_ratings
+			FROM cratings WHERE course_id = $1
+		) r
+		WHERE courses.id = $1`
+)
+
+// dedupeCratings removes duplicate ratings so AutoMigrate can create the
+// unique index on (course_id, student_id). It only runs until the index
+// exists, a fresh database has nothing to clean up.
+func dedupeCratings(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.Crating{}) || migrator.HasIndex(&models.Crating{}, cratingsUniqueIndex) {
+		return nil
+	}
+	// A database older than the denormalized ratings gets the columns from
+	// AutoMigrate, there is nothing to refresh yet
+	refresh := migrator.HasColumn(&models.Course{}, "average_rating")
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	removed, err := removeDuplicateCratings(ctx, sqlDB, refresh)
+	if err != nil {
+		return fmt.Errorf("failed to remove duplicate ratings: %v", err)
+	}
+	if removed > 0 {
+		log.Printf("Removed %d duplicate course ratings", removed)
+	}
+	return nil
+}
+
+// removeDuplicateCratings keeps the newest rating of every course and student
+// pair and, when refresh is set, recomputes the rating of the courses that
+// lost one, all in one transaction. It returns how many ratings were removed.
+func removeDuplicateCratings(ctx context.Context, db *sql.DB, refresh bool) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, deleteDuplicateCratingsQuery)
+	if err != nil {
+		return 0, err
+	}
+	var removed int
+	var courses []uint
+	seen := make(map[uint]bool)
+	for rows.Next() {
+		var courseID uint
+		if err := rows.Scan(&courseID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		removed++
+		if !seen[courseID] {
+			seen[courseID] = true
+			courses = append(courses, courseID)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	if refresh {
+		for _, courseID := range courses {
+			if _, err := tx.ExecContext(ctx, refreshCourseRatingQuery, courseID); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return removed, tx.Commit()
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// scriptedDB is a database/sql connector answering statements in the order
+// they are scripted, so migration steps can run without PostgreSQL
+type scriptedDB struct {
+	t         *testing.T
+	steps     []scriptedStep
+	commits   int
+	rollbacks int
+}
+
+// scriptedStep is a statement containing query, with its arguments and the
+// course IDs or error it answers
+type scriptedStep struct {
+	query string
+	args  []driver.Value
+	rows  []driver.Value
+	err   error
+}
+
+func newScriptedDB(t *testing.T, steps ...scriptedStep) (*sql.DB, *scriptedDB) {
+	t.Helper()
+	s := &scriptedDB{t: t, steps: steps}
+	db := sql.OpenDB(s)
+	t.Cleanup(func() {
+		db.Close()
+		for _, step := range s.steps {
+			t.Errorf("expected statement was not run: %s %v", strings.TrimSpace(step.query), step.args)
+		}
+	})
+	return db, s
+}
+
+func (s *scriptedDB) Connect(context.Context) (driver.Conn, error) { return s, nil }
+func (s *scriptedDB) Driver() driver.Driver                        { return nil }
+
+func (s *scriptedDB) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("scripted: prepared statements are not supported")
+}
+func (s *scriptedDB) Close() error              { return nil }
+func (s *scriptedDB) Begin() (driver.Tx, error) { return s, nil }
+func (s *scriptedDB) Commit() error             { s.commits++; return nil }
+func (s *scriptedDB) Rollback() error           { s.rollbacks++; return nil }
+
+func (s *scriptedDB) next(query string, args []driver.NamedValue) (scriptedStep, error) {
+	got := make([]driver.Value, len(args))
+	for i, arg := range args {
+		got[i] = arg.Value
+	}
+	if len(s.steps) == 0 || !strings.Contains(query, strings.TrimSpace(s.steps[0].query)) ||
+		fmt.Sprint(s.steps[0].args) != fmt.Sprint(got) {
+		s.t.Errorf("unexpected statement: %s %v", strings.TrimSpace(query), got)
+		return scriptedStep{}, errors.New("scripted: unexpected statement")
+	}
+	step := s.steps[0]
+	s.steps = s.steps[1:]
+	return step, step.err
+}
+
+func (s *scriptedDB) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	step, err := s.next(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &courseIDRows{ids: step.rows}, nil
+}
+
+func (s *scriptedDB) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if _, err := s.next(query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+// courseIDRows answers a single course_id column
+type courseIDRows struct{ ids []driver.Value }
+
+func (r *courseIDRows) Columns() []string { return []string{"course_id"} }
+func (r *courseIDRows) Close() error      { return nil }
+
+func (r *courseIDRows) Next(dest []driver.Value) error {
+	if len(r.ids) == 0 {
+		return io.EOF
+	}
+	dest[0], r.ids = r.ids[0], r.ids[1:]
+	return nil
+}
+
+func TestDeleteDuplicateCratingsKeepsNewest(t *testing.T) {
+	// The row deleted is the older of a pair, never the newer one
+	if !strings.Contains(deleteDuplicateCratingsQuery, "(a.xmin::text::bigint, a.ctid) < (b.xmin::text::bigint, b.ctid)") {
+		t.Errorf("deleteDuplicateCratingsQuery does not keep the newest rating: %s", deleteDuplicateCratingsQuery)
+	}
+}
+
+func TestRemoveDuplicateCratingsRefreshesCourses(t *testing.T) {
+	// Course 3 had two extra ratings and course 4 one, each is refreshed once
+	db, s := newScriptedDB(t,
+		scriptedStep{query: deleteDuplicateCratingsQuery, rows: []driver.Value{int64(3), int64(4), int64(3)}},
+		scriptedStep{query: refreshCourseRatingQuery, args: []driver.Value{int64(3)}},
+		scriptedStep{query: refreshCourseRatingQuery, args: []driver.Value{int64(4)}},
+	)
+
+	removed, err := removeDuplicateCratings(context.Background(), db, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 3 {
+		t.Errorf("removed = %d, want 3", removed)
+	}
+	if s.commits != 1 {
+		t.Errorf("commits = %d, want 1", s.commits)
+	}
+}
+
+func TestRemoveDuplicateCratingsWithoutDuplicates(t *testing.T) {
+	db, s := newScriptedDB(t, scriptedStep{query: deleteDuplicateCratingsQuery})
+
+	removed, err := removeDuplicateCratings(context.Background(), db, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 0 || s.commits != 1 {
+		t.Errorf("removed = %d, commits = %d, want nothing removed and a commit", removed, s.commits)
+	}
+}
+
+func TestRemoveDuplicateCratingsWithoutRatingColumns(t *testing.T) {
+	// Nothing else is scripted, so refreshing a course fails the test
+	db, _ := newScriptedDB(t, scriptedStep{query: deleteDuplicateCratingsQuery, rows: []driver.Value{int64(3)}})
+
+	if _, err := removeDuplicateCratings(context.Background(), db, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoveDuplicateCratingsRollsBack(t *testing.T) {
+	db, s := newScriptedDB(t,
+		scriptedStep{query: deleteDuplicateCratingsQuery, rows: []driver.Value{int64(3)}},
+		scriptedStep{query: refreshCourseRatingQuery, args: []driver.Value{int64(3)}, err: errors.New("connection reset")},
+	)
+
+	if _, err := removeDuplicateCratings(context.Background(), db, true); err == nil {
+		t.Fatal("refresh failure was not reported")
+	}
+	if s.commits != 0 || s.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want the deletion rolled back", s.commits, s.rollbacks)
+	}
+}
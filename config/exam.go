@@ -0,0 +1,25 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// defaultExamMaxAttempts is how many times a student may submit a course exam
+// when EXAM_MAX_ATTEMPTS is not set
+const defaultExamMaxAttempts = 3
+
+// ExamMaxAttempts reads the exam attempt limit from EXAM_MAX_ATTEMPTS
+func ExamMaxAttempts() int {
+	raw := os.Getenv("EXAM_MAX_ATTEMPTS")
+	if raw == "" {
+		return defaultExamMaxAttempts
+	}
+	attempts, err := strconv.Atoi(raw)
+	if err != nil || attempts < 1 {
+		log.Printf("Warning: invalid EXAM_MAX_ATTEMPTS %q, using %d", raw, defaultExamMaxAttempts)
+		return defaultExamMaxAttempts
+	}
+	return attempts
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// Login rate limit defaults, used when the matching env var is not set
+const (
+	defaultLoginRateLimitIP         = 20
+	defaultLoginRateLimitIdentifier = 5
+	defaultLoginRateWindow          = time.Minute
+)
+
+// LoginRateLimits caps login attempts per client IP and per account identifier
+type LoginRateLimits struct {
+	PerIP         int
+	PerIdentifier int
+	Window        time.Duration
+}
+
+// LoadLoginRateLimits reads the login limits from LOGIN_RATE_LIMIT_IP,
+// LOGIN_RATE_LIMIT_IDENTIFIER and LOGIN_RATE_WINDOW (a duration such as 1m)
+func LoadLoginRateLimits() LoginRateLimits {
+	limits := LoginRateLimits{
+		PerIP:         envPositiveInt("LOGIN_RATE_LIMIT_IP", defaultLoginRateLimitIP),
+		PerIdentifier: envPositiveInt("LOGIN_RATE_LIMIT_IDENTIFIER", defaultLoginRateLimitIdentifier),
+		Window:        defaultLoginRateWindow,
+	}
+	if raw := os.Getenv("LOGIN_RATE_WINDOW"); raw != "" {
+		window, err := time.ParseDuration(raw)
+		if err != nil || window <= 0 {
+			log.Printf("Warning: invalid LOGIN_RATE_WINDOW %q, using %s", raw, defaultLoginRateWindow)
+		} else {
+			limits.Window = window
+		}
+	}
+	return limits
+}
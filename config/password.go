@@ -0,0 +1,25 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+)
+
+// defaultPasswordMinLength is the shortest password accepted on signup when
+// PASSWORD_MIN_LENGTH is not set
+const defaultPasswordMinLength = 8
+
+// PasswordMinLength reads the minimum password length from PASSWORD_MIN_LENGTH
+func PasswordMinLength() int {
+	raw := os.Getenv("PASSWORD_MIN_LENGTH")
+	if raw == "" {
+		return defaultPasswordMinLength
+	}
+	length, err := strconv.Atoi(raw)
+	if err != nil || length < 1 {
+		log.Printf("Warning: invalid PASSWORD_MIN_LENGTH %q, using %d", raw, defaultPasswordMinLength)
+		return defaultPasswordMinLength
+	}
+	return length
+}
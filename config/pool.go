@@ -0,0 +1,68 @@
+package config
+
+import (
+	"database/sql"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Connection pool defaults, used when the matching env var is not set
+const (
+	defaultMaxOpenConns    = 25
+	defaultMaxIdleConns    = 10
+	defaultConnMaxLifetime = 30 * time.Minute
+)
+
+// PoolSettings tunes the database connection pool
+type PoolSettings struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// LoadPoolSettings reads the pool settings from DB_MAX_OPEN_CONNS,
+// DB_MAX_IDLE_CONNS and DB_CONN_MAX_LIFETIME (a duration such as 30m)
+func LoadPoolSettings() PoolSettings {
+	settings := PoolSettings{
+		MaxOpenConns:    envPositiveInt("DB_MAX_OPEN_CONNS", defaultMaxOpenConns),
+		MaxIdleConns:    envPositiveInt("DB_MAX_IDLE_CONNS", defaultMaxIdleConns),
+		ConnMaxLifetime: defaultConnMaxLifetime,
+	}
+	if raw := os.Getenv("DB_CONN_MAX_LIFETIME"); raw != "" {
+		lifetime, err := time.ParseDuration(raw)
+		if err != nil || lifetime <= 0 {
+			log.Printf("Warning: invalid DB_CONN_MAX_LIFETIME %q, using %s", raw, defaultConnMaxLifetime)
+		} else {
+			settings.ConnMaxLifetime = lifetime
+		}
+	}
+	// Idle connections above the open limit would be closed right away
+	if settings.MaxIdleConns > settings.MaxOpenConns {
+		settings.MaxIdleConns = settings.MaxOpenConns
+	}
+	return settings
+}
+
+// Apply configures db with the settings
+func (s PoolSettings) Apply(db *sql.DB) {
+	db.SetMaxOpenConns(s.MaxOpenConns)
+	db.SetMaxIdleConns(s.MaxIdleConns)
+	db.SetConnMaxLifetime(s.ConnMaxLifetime)
+}
+
+// envPositiveInt reads a positive integer from the env var key, falling back
+// to def when it is unset or invalid
+func envPositiveInt(key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 {
+		log.Printf("Warning: invalid %s %q, using %d", key, raw, def)
+		return def
+	}
+	return value
+}
@@ -0,0 +1,38 @@
+package config
+
+import (
+	"log"
+	"time"
+
+	"github.com/cuddest/dz-skills/models"
+	"gorm.io/gorm"
+)
+
+// SchemaVersion is the schema version this build migrates the database to,
+// bump it with every model change that alters the schema
+const SchemaVersion = 14
+
+// CurrentSchemaVersion returns the latest schema version recorded in the database, 0 if none
+func CurrentSchemaVersion(db *gorm.DB) (uint, error) {
+	var version uint
+	err := db.Raw("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version).Error
+	return version, err
+}
+
+// recordSchemaVersion stores SchemaVersion once migrations succeeded. A
+// database already on a newer version is left alone and reported, as this
+// build is older than the schema it runs against.
+func recordSchemaVersion(db *gorm.DB) error {
+	current, err := CurrentSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+	if current > SchemaVersion {
+		log.Printf("Warning: database schema version %d is newer than the expected version %d", current, SchemaVersion)
+		return nil
+	}
+	if current == SchemaVersion {
+		return nil
+	}
+	return db.Create(&models.SchemaMigration{Version: SchemaVersion, AppliedAt: time.Now()}).Error
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// defaultPort is the port the server listens on when PORT is not set
+const defaultPort = "8080"
+
+// Port reads the port to listen on from PORT
+func Port() string {
+	if port := strings.TrimSpace(os.Getenv("PORT")); port != "" {
+		return port
+	}
+	return defaultPort
+}
+
+// CORSOrigins reads the allowed origins from the comma separated CORS_ORIGINS,
+// falling back to defaults when it is unset or lists no origin
+func CORSOrigins(defaults []string) []string {
+	var origins []string
+	for _, origin := range strings.Split(os.Getenv("CORS_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	if len(origins) == 0 {
+		return defaults
+	}
+	return origins
+}
@@ -0,0 +1,16 @@
+package config
+
+import (
+	"os"
+)
+
+// defaultUploadDir is where uploaded files live when UPLOAD_DIR is not set
+const defaultUploadDir = "uploads"
+
+// UploadDir reads the directory uploaded files are stored in from UPLOAD_DIR
+func UploadDir() string {
+	if dir := os.Getenv("UPLOAD_DIR"); dir != "" {
+		return dir
+	}
+	return defaultUploadDir
+}
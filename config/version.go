@@ -0,0 +1,9 @@
+package config
+
+// Build information, injected at build time with
+// -ldflags "-X github.com/cuddest/dz-skills/config.Version=..."
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildTime = "dev"
+)
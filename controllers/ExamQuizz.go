@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -54,12 +55,31 @@ const (
 
 	deleteExamQuizzQuery = `
 		DELETE FROM exam_quizzes WHERE id = $1`
+
+	deleteExamQuizzesBulkQuery = `
+		DELETE FROM exam_quizzes WHERE id = ANY($1)`
+
+	getExamQuizzesNotOwnedQuery = `
+		SELECT eq.id 
+		FROM exam_quizzes eq 
+		LEFT JOIN exams e ON e.id = eq.exam_id 
+		LEFT JOIN courses c ON c.id = e.course_id 
+		WHERE eq.id = ANY($1) AND c.teacher_id IS DISTINCT FROM $2`
 )
 
 type ExamQuizzController struct {
 	db *sql.DB
 }
 
+// maxBulkExamQuizzes caps how many quizzes a single bulk request may create
+const maxBulkExamQuizzes = 100
+
+// BulkExamQuizzesRequest holds quizzes to add to one exam at once
+type BulkExamQuizzesRequest struct {
+	ExamID  uint               `json:"exam_id"`
+	Quizzes []models.ExamQuizz `json:"quizzes"`
+}
+
 func NewExamQuizzController(db *sql.DB) *ExamQuizzController {
 	return &ExamQuizzController{db: db}
 }
@@ -131,12 +151,90 @@ func (h *ExamQuizzController) CreateExamQuizz(c *gin.Context) {
 	c.JSON(http.StatusCreated, quizz)
 }
 
+// @Summary Create exam quizzes in bulk
+// @Description Create several quizzes for the same exam in a single transaction, either all of them are created or none
+// @Tags examquizzes
+// @Accept json
+// @Produce json
+// @Param request body BulkExamQuizzesRequest true "Exam ID and quizzes"
+// @Success 201 {array} models.ExamQuizz
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /examquizzes/createExamQuizzesBulk [post]
+func (h *ExamQuizzController) CreateExamQuizzesBulk(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var req BulkExamQuizzesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ExamID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid exam ID is required"})
+		return
+	}
+	if len(req.Quizzes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one quiz is required"})
+		return
+	}
+	if len(req.Quizzes) > maxBulkExamQuizzes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d quizzes can be created at once", maxBulkExamQuizzes)})
+		return
+	}
+
+	for i := range req.Quizzes {
+		req.Quizzes[i].ExamID = req.ExamID
+		if err := h.validateExamQuizz(&req.Quizzes[i]); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("quiz %d: %s", i+1, err.Error())})
+			return
+		}
+	}
+
+	// Verify exam exists
+	var exists bool
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM exams WHERE id = $1)", req.ExamID).Scan(&exists)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify exam"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam not found"})
+		return
+	}
+
+	if err := h.createExamQuizzes(ctx, req.Quizzes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create exam quizzes"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, req.Quizzes)
+}
+
+// createExamQuizzes inserts already validated quizzes in one transaction,
+// filling in their generated IDs
+func (h *ExamQuizzController) createExamQuizzes(ctx context.Context, quizzes []models.ExamQuizz) error {
+	return withTx(ctx, h.db, func(tx *sql.Tx) error {
+		for i := range quizzes {
+			quizz := &quizzes[i]
+			err := tx.QueryRowContext(ctx, createExamQuizzQuery,
+				quizz.Question, quizz.Option1, quizz.Option2, quizz.Option3,
+				quizz.Option4, quizz.Answer, quizz.ExamID).Scan(&quizz.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // @Summary Get exam quiz by ID
 // @Description Get a specific exam quiz by its ID
 // @Tags examquizzes
 // @Accept json
 // @Produce json
-// @Param id body int true "Exam Quiz ID"
+// @Param request body IDRequest true "Exam Quiz ID"
 // @Success 200 {object} models.ExamQuizz
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -147,15 +245,14 @@ func (h *ExamQuizzController) GetExamQuizz(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var quizz models.ExamQuizz
 	var examJSON []byte
-	err = h.db.QueryRowContext(ctx, getExamQuizzQuery, id).Scan(
+	err := h.db.QueryRowContext(ctx, getExamQuizzQuery, id).Scan(
 		&quizz.ID, &quizz.Question, &quizz.Option1, &quizz.Option2,
 		&quizz.Option3, &quizz.Option4, &quizz.Answer, &quizz.ExamID, &examJSON,
 	)
@@ -169,6 +266,11 @@ func (h *ExamQuizzController) GetExamQuizz(c *gin.Context) {
 		return
 	}
 
+	if err := decodeJoined(examJSON, &quizz.Exam); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exam quiz"})
+		return
+	}
+
 	c.JSON(http.StatusOK, quizz)
 }
 
@@ -204,6 +306,10 @@ func (h *ExamQuizzController) GetAllExamQuizzes(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exam quizzes"})
 			return
 		}
+		if err := decodeJoined(examJSON, &quizz.Exam); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process quizzes"})
+			return
+		}
 		quizzes = append(quizzes, quizz)
 	}
 
@@ -267,6 +373,10 @@ func (h *ExamQuizzController) GetExamQuizzesByExam(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exam quizzes"})
 			return
 		}
+		if err := decodeJoined(examJSON, &quizz.Exam); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process quizzes"})
+			return
+		}
 		quizzes = append(quizzes, quizz)
 	}
 
@@ -294,17 +404,16 @@ func (h *ExamQuizzController) UpdateExamQuizz(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var quizz models.ExamQuizz
 	if err := c.ShouldBindJSON(&quizz); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if quizz.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := quizz.ID
 
 	if err := h.validateExamQuizz(&quizz); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -313,7 +422,7 @@ func (h *ExamQuizzController) UpdateExamQuizz(c *gin.Context) {
 
 	// Verify exam exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM exams WHERE id = $1)", quizz.ExamID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM exams WHERE id = $1)", quizz.ExamID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify exam"})
 		return
@@ -323,6 +432,24 @@ func (h *ExamQuizzController) UpdateExamQuizz(c *gin.Context) {
 		return
 	}
 
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.ExamQuizz
+	var examJSON []byte
+	if wantsDiff(c) {
+		err = h.db.QueryRowContext(ctx, getExamQuizzQuery, id).Scan(
+			&before.ID, &before.Question, &before.Option1, &before.Option2,
+			&before.Option3, &before.Option4, &before.Answer, &before.ExamID, &examJSON,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Exam quiz not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exam quiz"})
+			return
+		}
+	}
+
 	result, err := h.db.ExecContext(ctx, updateExamQuizzQuery,
 		quizz.Question, quizz.Option1, quizz.Option2, quizz.Option3,
 		quizz.Option4, quizz.Answer, quizz.ExamID, id)
@@ -342,8 +469,7 @@ func (h *ExamQuizzController) UpdateExamQuizz(c *gin.Context) {
 		return
 	}
 
-	quizz.ID = uint(id)
-	c.JSON(http.StatusOK, quizz)
+	respondUpdated(c, before, quizz)
 }
 
 // @Summary Delete exam quiz
@@ -387,3 +513,37 @@ func (h *ExamQuizzController) DeleteExamQuizz(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Exam quiz deleted successfully"})
 }
+
+// @Summary Delete exam quizzes in bulk
+// @Description Delete several exam quizzes of the authenticated teacher's courses in one transaction. If any ID belongs to another teacher's exam nothing is deleted. The number of deleted quizzes is returned so IDs that did not exist can be detected.
+// @Tags examquizzes
+// @Accept json
+// @Produce json
+// @Param request body IDsRequest true "Exam quiz IDs"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /examquizzes/bulk [delete]
+func (h *ExamQuizzController) DeleteExamQuizzesBulk(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	ids, ok := bindIDs(c)
+	if !ok {
+		return
+	}
+
+	teacherID, ok, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only teachers can delete exam quizzes in bulk"})
+		return
+	}
+
+	deleted, notOwned, err := deleteOwned(ctx, h.db, ids, teacherID, getExamQuizzesNotOwnedQuery, deleteExamQuizzesBulkQuery)
+	respondBulkDelete(c, len(ids), deleted, notOwned, err)
+}
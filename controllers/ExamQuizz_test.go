@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDeleteExamQuizzesBulk(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(getExamQuizzesNotOwnedQuery, []int64{4, 5}, 3)
+	f.expect(deleteExamQuizzesBulkQuery, []int64{4, 5}).affects(2)
+
+	w := serve(t, NewExamQuizzController(db).DeleteExamQuizzesBulk, testRequest{
+		method: http.MethodDelete,
+		body:   `{"ids": [4, 5]}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusOK)
+	if f.commits != 1 {
+		t.Errorf("commits = %d, want 1", f.commits)
+	}
+}
+
+func TestDeleteExamQuizzesBulkRejectsUnownedIDs(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(getExamQuizzesNotOwnedQuery, []int64{4, 9}, 3).returns(row(9))
+
+	w := serve(t, NewExamQuizzController(db).DeleteExamQuizzesBulk, testRequest{
+		method: http.MethodDelete,
+		body:   `{"ids": [4, 9]}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusForbidden)
+	if f.commits != 0 || f.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want the transaction rolled back", f.commits, f.rollbacks)
+	}
+}
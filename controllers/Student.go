@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -9,6 +10,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	checkStudentUsernameQuery = `
+		SELECT EXISTS(SELECT 1 FROM students WHERE username = $1 AND id != $2)`
+
+	checkStudentEmailQuery = `
+		SELECT EXISTS(SELECT 1 FROM students WHERE email = $1 AND id != $2)`
+)
+
 type StudentController struct {
 	db *sql.DB
 }
@@ -17,6 +26,31 @@ func NewStudentController(db *sql.DB) *StudentController {
 	return &StudentController{db: db}
 }
 
+// checkUniqueness verifies username and email uniqueness, ignoring the student itself
+func (h *StudentController) checkUniqueness(student *models.Student) error {
+	var exists bool
+
+	// Check username uniqueness
+	if err := h.db.QueryRow(checkStudentUsernameQuery,
+		student.Username, student.ID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("username already exists")
+	}
+
+	// Check email uniqueness
+	if err := h.db.QueryRow(checkStudentEmailQuery,
+		student.Email, student.ID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("email already exists")
+	}
+
+	return nil
+}
+
 // @Summary Create a new student
 // @Description Register a new student in the system
 // @Tags students
@@ -36,6 +70,23 @@ func (h *StudentController) CreateStudent(c *gin.Context) {
 		return
 	}
 
+	if student.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+	if err := validateEmail(student.Email); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validatePassword(student.Password); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.checkUniqueness(&student); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Hash the password before saving
 	if err := models.HashPassword(&student, student.Password); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
@@ -54,6 +105,7 @@ func (h *StudentController) CreateStudent(c *gin.Context) {
 
 	// Set the ID for the student object
 	student.ID = id
+	student.Password = "" // Clear sensitive data
 
 	// Return the created student
 	c.JSON(http.StatusCreated, student)
@@ -64,7 +116,7 @@ func (h *StudentController) CreateStudent(c *gin.Context) {
 // @Tags students
 // @Accept json
 // @Produce json
-// @Param id path int true "Student ID"
+// @Param request body IDRequest true "Student ID"
 // @Success 200 {object} models.Student
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -72,17 +124,17 @@ func (h *StudentController) CreateStudent(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Router /students/GetStudent [post]
 func (h *StudentController) GetStudent(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var student models.Student
-	query := `SELECT id, full_name, email, password, picture FROM students WHERE id = $1`
-	err = h.db.QueryRow(query, id).Scan(
+	query := `SELECT id, full_name, username, email, password, picture FROM students WHERE id = $1`
+	err := h.db.QueryRow(query, id).Scan(
 		&student.ID,
 		&student.FullName,
+		&student.Username,
 		&student.Email,
 		&student.Password,
 		&student.Picture,
@@ -98,6 +150,7 @@ func (h *StudentController) GetStudent(c *gin.Context) {
 		return
 	}
 
+	student.Password = "" // Clear sensitive data
 	c.JSON(http.StatusOK, student)
 }
 
@@ -111,7 +164,7 @@ func (h *StudentController) GetStudent(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Router /students/all [get]
 func (h *StudentController) GetAllStudents(c *gin.Context) {
-	rows, err := h.db.Query(`SELECT id, full_name, email, password, picture FROM students`)
+	rows, err := h.db.Query(`SELECT id, full_name, username, email, password, picture FROM students`)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -121,10 +174,11 @@ func (h *StudentController) GetAllStudents(c *gin.Context) {
 	var students []models.Student
 	for rows.Next() {
 		var student models.Student
-		if err := rows.Scan(&student.ID, &student.FullName, &student.Email, &student.Password, &student.Picture); err != nil {
+		if err := rows.Scan(&student.ID, &student.FullName, &student.Username, &student.Email, &student.Password, &student.Picture); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		student.Password = "" // Clear sensitive data
 		students = append(students, student)
 	}
 
@@ -141,7 +195,6 @@ func (h *StudentController) GetAllStudents(c *gin.Context) {
 // @Tags students
 // @Accept json
 // @Produce json
-// @Param id path int true "Student ID"
 // @Param student body models.Student true "Updated student information"
 // @Success 200 {object} models.Student
 // @Failure 400 {object} map[string]interface{}
@@ -149,28 +202,57 @@ func (h *StudentController) GetAllStudents(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Router /students/UpdateUser [put]
 func (h *StudentController) UpdateStudent(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+	var student models.Student
+	if err := c.ShouldBindJSON(&student); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if student.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
 		return
 	}
+	id := student.ID
 
-	var student models.Student
-	if err := c.ShouldBindJSON(&student); err != nil {
+	if student.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+	if err := validateEmail(student.Email); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.checkUniqueness(&student); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	query := `UPDATE students SET full_name = $1, email = $2, password = $3, picture = $4 WHERE id = $5`
-	_, err = h.db.Exec(query, student.FullName, student.Email, student.Password, student.Picture, id)
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Student
+	if wantsDiff(c) {
+		err := h.db.QueryRow(`SELECT id, full_name, username, email, password, picture FROM students WHERE id = $1`, id).Scan(
+			&before.ID, &before.FullName, &before.Username, &before.Email, &before.Password, &before.Picture,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve student"})
+			return
+		}
+		before.Password = ""
+	}
+
+	query := `UPDATE students SET full_name = $1, username = $2, email = $3, password = $4, picture = $5 WHERE id = $6`
+	_, err := h.db.Exec(query, student.FullName, student.Username, student.Email, student.Password, student.Picture, id)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	student.ID = uint(id)
-	c.JSON(http.StatusOK, student)
+	student.Password = "" // Clear sensitive data
+	respondUpdated(c, before, student)
 }
 
 // @Summary Delete student
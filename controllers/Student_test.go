@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+)
+
+const (
+	studentByIDInlineQuery   = "SELECT id, full_name, username, email, password, picture FROM students WHERE id = $1"
+	allStudentsInlineQuery   = "SELECT id, full_name, username, email, password, picture FROM students"
+	updateStudentInlineQuery = "UPDATE students SET full_name = $1"
+	studentPasswordHash      = "$2a$10$abcdefghijklmnopqrstuv"
+)
+
+// expectNoPassword fails the test when a student in the response carries a password
+func expectNoPassword(t *testing.T, student map[string]interface{}) {
+	t.Helper()
+	if password, ok := student["Password"]; ok && password != "" {
+		t.Errorf("student %v leaks its password %q", student["ID"], password)
+	}
+}
+
+func TestGetStudentHidesPassword(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(studentByIDInlineQuery, 5).returns(row(5, "Alice", "alice", "alice@example.com", studentPasswordHash, ""))
+
+	w := serve(t, NewStudentController(db).GetStudent, testRequest{body: `{"id": 5}`})
+	expectStatus(t, w, http.StatusOK)
+	expectNoPassword(t, responseKeys(t, w.Body.Bytes()))
+}
+
+func TestGetAllStudentsHidesPasswords(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(allStudentsInlineQuery).returns(
+		row(5, "Alice", "alice", "alice@example.com", studentPasswordHash, ""),
+		row(6, "Carol", "carol", "carol@example.com", studentPasswordHash, ""),
+	)
+
+	w := serve(t, NewStudentController(db).GetAllStudents, testRequest{method: http.MethodGet})
+	expectStatus(t, w, http.StatusOK)
+
+	var students []map[string]interface{}
+	decodeBody(t, w, &students)
+	if len(students) != 2 {
+		t.Fatalf("got %d students, want 2", len(students))
+	}
+	for _, student := range students {
+		expectNoPassword(t, student)
+	}
+}
+
+func TestUpdateStudentHidesPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+	}{
+		{"full", nil},
+		{"diff", map[string]string{"Prefer": "return=diff"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(checkStudentUsernameQuery, "alice", 5).returns(row(false))
+			f.expect(checkStudentEmailQuery, "alice@example.com", 5).returns(row(false))
+			if tt.headers != nil {
+				f.expect(studentByIDInlineQuery, 5).returns(row(5, "Al", "alice", "alice@example.com", studentPasswordHash, ""))
+			}
+			f.expect(updateStudentInlineQuery).affects(1)
+
+			w := serve(t, NewStudentController(db).UpdateStudent, testRequest{
+				method:  http.MethodPut,
+				body:    `{"ID": 5, "FullName": "Alice", "username": "alice", "email": "alice@example.com", "Password": "secret"}`,
+				headers: tt.headers,
+			})
+			expectStatus(t, w, http.StatusOK)
+			expectNoPassword(t, responseKeys(t, w.Body.Bytes()))
+		})
+	}
+}
+
+func TestCreateStudentRejectsTakenUsername(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(checkStudentUsernameQuery, "alice", 0).returns(row(true))
+
+	w := serve(t, NewStudentController(db).CreateStudent, testRequest{
+		body: `{"FullName": "Alice", "username": "alice", "email": "alice@example.com", "Password": "secret123"}`,
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	got := responseKeys(t, w.Body.Bytes())
+	if got["error"] != "username already exists" {
+		t.Errorf("error = %v, want the username conflict", got["error"])
+	}
+}
+
+func TestCreateStudentStoresUsername(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(checkStudentUsernameQuery, "alice", 0).returns(row(false))
+	f.expect(checkStudentEmailQuery, "alice@example.com", 0).returns(row(false))
+	f.expect("INSERT INTO students (full_name, username, email, password, picture)").returns(row(5))
+
+	w := serve(t, NewStudentController(db).CreateStudent, testRequest{
+		body: `{"FullName": "Alice", "username": "alice", "email": "alice@example.com", "Password": "secret123"}`,
+	})
+	expectStatus(t, w, http.StatusCreated)
+
+	got := responseKeys(t, w.Body.Bytes())
+	if got["username"] != "alice" || got["ID"] != float64(5) {
+		t.Errorf("student = %v, want alice with ID 5", got)
+	}
+	expectNoPassword(t, got)
+}
+
+func TestCreateStudentRequiresUsername(t *testing.T) {
+	db, _ := newFakeDB(t)
+	w := serve(t, NewStudentController(db).CreateStudent, testRequest{
+		body: `{"FullName": "Alice", "email": "alice@example.com", "Password": "secret123"}`,
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestUpdateStudentRejectsTakenUsername(t *testing.T) {
+	db, f := newFakeDB(t)
+	// The student's own row is excluded from the check
+	f.expect(checkStudentUsernameQuery, "carol", 5).returns(row(true))
+
+	w := serve(t, NewStudentController(db).UpdateStudent, testRequest{
+		method: http.MethodPut,
+		body:   `{"ID": 5, "username": "carol", "email": "alice@example.com"}`,
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	getCourseAccessDaysQuery = `
+		SELECT access_days FROM courses WHERE id = $1 AND deleted_at IS NULL`
+
+	getAccessExpiryQuery = `
+		SELECT access_expires_at 
+		FROM student_courses 
+		WHERE student_id = $1 AND course_id = $2`
+
+	getStudentIDByUsernameQuery = `
+		SELECT id FROM students WHERE username = $1`
+
+	getTeacherIDByUsernameQuery = `
+		SELECT id FROM teachers WHERE username = $1`
+)
+
+var errAccessExpired = errors.New("course access has expired")
+
+// now is the clock used for enrollment and access checks, tests can replace it
+var now = time.Now
+
+// accessExpiry computes when access granted at enrollment ends, nil for unlimited access
+func accessExpiry(enrollment time.Time, accessDays uint) *time.Time {
+	if accessDays == 0 {
+		return nil
+	}
+	expiresAt := enrollment.AddDate(0, 0, int(accessDays))
+	return &expiresAt
+}
+
+// checkCourseAccess returns sql.ErrNoRows when the student is not enrolled in
+// the course and errAccessExpired when the enrollment's access window is over
+func checkCourseAccess(ctx context.Context, db *sql.DB, studentID, courseID uint) error {
+	var expiresAt sql.NullTime
+	if err := db.QueryRowContext(ctx, getAccessExpiryQuery, studentID, courseID).Scan(&expiresAt); err != nil {
+		return err
+	}
+	if expiresAt.Valid && !now().Before(expiresAt.Time) {
+		return errAccessExpired
+	}
+	return nil
+}
+
+// authenticatedStudentID resolves the student behind the request's token,
+// ok is false when the caller is not a known student
+func authenticatedStudentID(ctx context.Context, db *sql.DB, c *gin.Context) (id uint, ok bool, err error) {
+	return authenticatedUserID(ctx, db, c, auth.RoleStudent, getStudentIDByUsernameQuery)
+}
+
+// authenticatedTeacherID resolves the teacher behind the request's token,
+// ok is false when the caller is not a known teacher
+func authenticatedTeacherID(ctx context.Context, db *sql.DB, c *gin.Context) (id uint, ok bool, err error) {
+	return authenticatedUserID(ctx, db, c, auth.RoleTeacher, getTeacherIDByUsernameQuery)
+}
+
+func authenticatedUserID(ctx context.Context, db *sql.DB, c *gin.Context, role, query string) (id uint, ok bool, err error) {
+	claims, isClaims := auth.ClaimsFromContext(c)
+	if !isClaims || claims.Role != role {
+		return 0, false, nil
+	}
+
+	err = db.QueryRowContext(ctx, query, claims.Username).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return id, true, nil
+}
+
+// denyExpiredAccess writes a 403 and returns true when the authenticated
+// student's access to the course has expired. Other callers are let through.
+func denyExpiredAccess(ctx context.Context, db *sql.DB, c *gin.Context, courseID uint) bool {
+	studentID, ok, err := authenticatedStudentID(ctx, db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
+		return true
+	}
+	if !ok {
+		return false
+	}
+
+	err = checkCourseAccess(ctx, db, studentID, courseID)
+	if err == nil || err == sql.ErrNoRows {
+		return false
+	}
+	if err == errAccessExpired {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Course access has expired"})
+		return true
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course access"})
+	return true
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setClock makes now return at for the rest of the test
+func setClock(t *testing.T, at time.Time) {
+	t.Helper()
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = time.Now })
+}
+
+func TestAccessExpiry(t *testing.T) {
+	enrollment := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	if got := accessExpiry(enrollment, 0); got != nil {
+		t.Errorf("accessExpiry(0 days) = %v, want unlimited", got)
+	}
+	want := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	if got := accessExpiry(enrollment, 30); got == nil || !got.Equal(want) {
+		t.Errorf("accessExpiry(30 days) = %v, want %v", got, want)
+	}
+}
+
+func TestCheckCourseAccess(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		clock    time.Time
+		expiry   interface{}
+		enrolled bool
+		want     error
+	}{
+		{"unlimited access", expiresAt.AddDate(1, 0, 0), nil, true, nil},
+		{"before expiry", expiresAt.Add(-time.Second), expiresAt, true, nil},
+		{"at expiry", expiresAt, expiresAt, true, errAccessExpired},
+		{"after expiry", expiresAt.Add(time.Hour), expiresAt, true, errAccessExpired},
+		{"not enrolled", expiresAt, nil, false, sql.ErrNoRows},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setClock(t, tt.clock)
+			db, f := newFakeDB(t)
+			e := f.expect(getAccessExpiryQuery, 5, 7)
+			if tt.enrolled {
+				e.returns(row(tt.expiry))
+			}
+
+			if err := checkCourseAccess(context.Background(), db, 5, 7); err != tt.want {
+				t.Errorf("checkCourseAccess() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDenyExpiredAccess(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	handler := func(db *sql.DB) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			if denyExpiredAccess(c.Request.Context(), db, c, 7) {
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"message": "allowed"})
+		}
+	}
+
+	t.Run("expired student", func(t *testing.T) {
+		setClock(t, expiresAt.Add(time.Minute))
+		db, f := newFakeDB(t)
+		f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+		f.expect(getAccessExpiryQuery, 5, 7).returns(row(expiresAt))
+
+		w := serve(t, handler(db), testRequest{claims: studentClaims("alice")})
+		expectStatus(t, w, http.StatusForbidden)
+	})
+
+	t.Run("student within access", func(t *testing.T) {
+		setClock(t, expiresAt.Add(-time.Minute))
+		db, f := newFakeDB(t)
+		f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+		f.expect(getAccessExpiryQuery, 5, 7).returns(row(expiresAt))
+
+		w := serve(t, handler(db), testRequest{claims: studentClaims("alice")})
+		expectStatus(t, w, http.StatusOK)
+	})
+
+	t.Run("teacher", func(t *testing.T) {
+		db, _ := newFakeDB(t)
+
+		w := serve(t, handler(db), testRequest{claims: teacherClaims("bob")})
+		expectStatus(t, w, http.StatusOK)
+	})
+}
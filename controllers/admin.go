@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cuddest/dz-skills/config"
+	"github.com/cuddest/dz-skills/middlewares"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	getCourseIDBatchQuery = `
+		SELECT id FROM courses WHERE id > $1 ORDER BY id ASC LIMIT $2`
+
+	recomputeCourseRatingsQuery = `
+		UPDATE courses c 
+		SET average_rating = agg.average_rating, ratings_count = agg.total_ratings 
+		FROM (
+			SELECT co.id, COALESCE(AVG(r.rating), 0) AS average_rating, COUNT(r.rating) AS total_ratings 
+			FROM courses co 
+			LEFT JOIN cratings r ON r.course_id = co.id 
+			WHERE co.id BETWEEN $1 AND $2 
+			GROUP BY co.id
+		) agg 
+		WHERE c.id = agg.id 
+			AND (c.average_rating IS DISTINCT FROM agg.average_rating OR c.ratings_count IS DISTINCT FROM agg.total_ratings)`
+
+	getSchemaVersionQuery = `
+		SELECT COALESCE(MAX(version), 0) FROM schema_migrations`
+
+	countStudentActivityQuery = `
+		SELECT COUNT(*) FROM students`
+
+	// Students never seen come first, then the ones inactive the longest
+	getStudentActivityQuery = `
+		SELECT id, full_name, username, email, last_active_at 
+		FROM students 
+		ORDER BY last_active_at ASC NULLS FIRST, id ASC 
+		LIMIT $1 OFFSET $2`
+)
+
+// recomputeBatchSize is how many courses are refreshed per transaction
+const recomputeBatchSize = 500
+
+// AdminController handles maintenance operations reserved to admins
+type AdminController struct {
+	db *sql.DB
+}
+
+// NewAdminController creates a new AdminController instance
+func NewAdminController(db *sql.DB) *AdminController {
+	return &AdminController{db: db}
+}
+
+// ContentFreeze is the state of the content freeze
+type ContentFreeze struct {
+	Enabled bool `json:"enabled"`
+}
+
+// StudentActivity is when a student last made an authenticated request,
+// null if they haven't since activity is tracked
+type StudentActivity struct {
+	ID           uint       `json:"ID"`
+	FullName     string     `json:"FullName"`
+	Username     string     `json:"username"`
+	Email        string     `json:"email"`
+	LastActiveAt *time.Time `json:"last_active_at"`
+}
+
+// RecomputeRatingsResult reports what RecomputeRatings changed
+type RecomputeRatingsResult struct {
+	CoursesChecked int   `json:"courses_checked"`
+	CoursesUpdated int64 `json:"courses_updated"`
+}
+
+// @Summary Recompute course ratings
+// @Description Recalculate the denormalized average rating and rating count of every course from its ratings, in batches. Admin only.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} RecomputeRatingsResult
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /admin/recomputeRatings [post]
+func (h *AdminController) RecomputeRatings(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	defer cancel()
+
+	var result RecomputeRatingsResult
+	var lastID uint
+	for {
+		var checked int
+		var updated int64
+		err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+			ids, err := courseIDBatch(ctx, tx, lastID)
+			if err != nil || len(ids) == 0 {
+				return err
+			}
+
+			res, err := tx.ExecContext(ctx, recomputeCourseRatingsQuery, ids[0], ids[len(ids)-1])
+			if err != nil {
+				return err
+			}
+			if updated, err = res.RowsAffected(); err != nil {
+				return err
+			}
+			checked = len(ids)
+			lastID = ids[len(ids)-1]
+			return nil
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recompute ratings"})
+			return
+		}
+		if checked == 0 {
+			break
+		}
+		result.CoursesChecked += checked
+		result.CoursesUpdated += updated
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SchemaStatus compares the database schema version with the one this build expects
+type SchemaStatus struct {
+	CurrentVersion  uint `json:"current_version"`
+	ExpectedVersion uint `json:"expected_version"`
+	UpToDate        bool `json:"up_to_date"`
+}
+
+// @Summary Get schema status
+// @Description Report the schema version recorded in the database and whether it matches the version this build expects. Admin only.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} SchemaStatus
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /admin/schema [get]
+func (h *AdminController) GetSchema(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var current uint
+	if err := h.db.QueryRowContext(ctx, getSchemaVersionQuery).Scan(&current); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve schema version"})
+		return
+	}
+
+	c.JSON(http.StatusOK, SchemaStatus{
+		CurrentVersion:  current,
+		ExpectedVersion: config.SchemaVersion,
+		UpToDate:        current == config.SchemaVersion,
+	})
+}
+
+// courseIDBatch returns the next recomputeBatchSize course IDs after afterID
+func courseIDBatch(ctx context.Context, tx *sql.Tx, afterID uint) ([]uint, error) {
+	rows, err := tx.QueryContext(ctx, getCourseIDBatchQuery, afterID, recomputeBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// @Summary Get the content freeze
+// @Description Report whether teacher content edits are currently frozen. Admin only.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} ContentFreeze
+// @Failure 403 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /admin/contentFreeze [get]
+func (h *AdminController) GetContentFreeze(c *gin.Context) {
+	c.JSON(http.StatusOK, ContentFreeze{Enabled: middlewares.ContentFrozen()})
+}
+
+// @Summary Set the content freeze
+// @Description Freeze or unfreeze teacher content edits, e.g. during exam grading windows. While frozen, teachers get 423 on content writes but students keep reading and submitting exams. The setting is kept in memory by each server instance. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param freeze body ContentFreeze true "Whether content edits are frozen"
+// @Success 200 {object} ContentFreeze
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /admin/contentFreeze [put]
+func (h *AdminController) SetContentFreeze(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req ContentFreeze
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+		return recordAudit(ctx, tx, c, "admin.content_freeze", fmt.Sprintf("enabled: %t", req.Enabled))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record the change"})
+		return
+	}
+	middlewares.SetContentFreeze(req.Enabled)
+
+	c.JSON(http.StatusOK, req)
+}
+
+// @Summary List student activity
+// @Description Page through students with the time of their last authenticated request, least recently active first, to spot inactive learners. Admin only.
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of students per page (max 100)"
+// @Success 200 {object} PagedResponse{data=[]StudentActivity}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /admin/studentActivity [get]
+func (h *AdminController) GetStudentActivity(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countStudentActivityQuery).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count students"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getStudentActivityQuery, page.PageSize, page.Offset())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve student activity"})
+		return
+	}
+	defer rows.Close()
+
+	students := []StudentActivity{}
+	for rows.Next() {
+		var student StudentActivity
+		var lastActiveAt sql.NullTime
+		if err := rows.Scan(&student.ID, &student.FullName, &student.Username, &student.Email, &lastActiveAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process student activity"})
+			return
+		}
+		if lastActiveAt.Valid {
+			student.LastActiveAt = &lastActiveAt.Time
+		}
+		students = append(students, student)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing student activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, PagedResponse{Data: students, Pagination: page.Meta(total)})
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cuddest/dz-skills/config"
+	"github.com/cuddest/dz-skills/middlewares"
+)
+
+func TestRecomputeRatingsWalksCoursesInBatches(t *testing.T) {
+	db, f := newFakeDB(t)
+	// Ratings inserted directly left courses 3 and 9 out of date
+	f.expect(getCourseIDBatchQuery, 0, recomputeBatchSize).returns(row(1), row(3), row(9))
+	f.expect(recomputeCourseRatingsQuery, 1, 9).affects(2)
+	f.expect(getCourseIDBatchQuery, 9, recomputeBatchSize).returns(row(12))
+	f.expect(recomputeCourseRatingsQuery, 12, 12).affects(0)
+	f.expect(getCourseIDBatchQuery, 12, recomputeBatchSize)
+
+	w := serve(t, NewAdminController(db).RecomputeRatings, testRequest{claims: adminClaims("root")})
+	expectStatus(t, w, http.StatusOK)
+
+	var got RecomputeRatingsResult
+	decodeBody(t, w, &got)
+	if got.CoursesChecked != 4 || got.CoursesUpdated != 2 {
+		t.Errorf("result = %+v, want 4 checked and 2 updated", got)
+	}
+	if f.commits != 3 {
+		t.Errorf("commits = %d, want one per batch", f.commits)
+	}
+}
+
+func TestRecomputeRatingsWithoutCourses(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getCourseIDBatchQuery, 0, recomputeBatchSize)
+
+	w := serve(t, NewAdminController(db).RecomputeRatings, testRequest{claims: adminClaims("root")})
+	expectStatus(t, w, http.StatusOK)
+
+	var got RecomputeRatingsResult
+	decodeBody(t, w, &got)
+	if got.CoursesChecked != 0 || got.CoursesUpdated != 0 {
+		t.Errorf("result = %+v, want nothing checked", got)
+	}
+}
+
+func TestGetSchemaReportsVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  uint
+		upToDate bool
+	}{
+		{"up to date", config.SchemaVersion, true},
+		{"behind", config.SchemaVersion - 1, false},
+		{"ahead", config.SchemaVersion + 1, false},
+		{"never migrated", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(getSchemaVersionQuery).returns(row(tt.current))
+
+			w := serve(t, NewAdminController(db).GetSchema, testRequest{
+				method: http.MethodGet,
+				claims: adminClaims("root"),
+			})
+			expectStatus(t, w, http.StatusOK)
+
+			var got SchemaStatus
+			decodeBody(t, w, &got)
+			want := SchemaStatus{CurrentVersion: tt.current, ExpectedVersion: config.SchemaVersion, UpToDate: tt.upToDate}
+			if got != want {
+				t.Errorf("status = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetContentFreeze(t *testing.T) {
+	at := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	setClock(t, at)
+	t.Cleanup(func() { middlewares.SetContentFreeze(false) })
+
+	db, f := newFakeDB(t)
+	f.expect(createAuditLogQuery, "root", "admin.content_freeze", "enabled: true", at).affects(1)
+
+	w := serve(t, NewAdminController(db).SetContentFreeze, testRequest{
+		method: http.MethodPut,
+		body:   `{"enabled": true}`,
+		claims: adminClaims("root"),
+	})
+	expectStatus(t, w, http.StatusOK)
+	if !middlewares.ContentFrozen() {
+		t.Error("content freeze is still off")
+	}
+}
+
+func TestSetContentFreezeKeepsStateWhenAuditFails(t *testing.T) {
+	t.Cleanup(func() { middlewares.SetContentFreeze(false) })
+
+	db, f := newFakeDB(t)
+	f.expect(createAuditLogQuery).fails(errors.New("connection reset"))
+
+	w := serve(t, NewAdminController(db).SetContentFreeze, testRequest{
+		method: http.MethodPut,
+		body:   `{"enabled": true}`,
+		claims: adminClaims("root"),
+	})
+	expectStatus(t, w, http.StatusInternalServerError)
+	if middlewares.ContentFrozen() {
+		t.Error("content freeze was turned on without an audit record")
+	}
+}
+
+func TestGetStudentActivity(t *testing.T) {
+	seen := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	db, f := newFakeDB(t)
+	f.expect(countStudentActivityQuery).returns(row(2))
+	f.expect(getStudentActivityQuery, defaultPageSize, 0).returns(
+		row(6, "Carol", "carol", "carol@example.com", nil),
+		row(5, "Alice", "alice", "alice@example.com", seen),
+	)
+
+	w := serve(t, NewAdminController(db).GetStudentActivity, testRequest{method: http.MethodGet, claims: adminClaims("root")})
+	expectStatus(t, w, http.StatusOK)
+
+	var page struct {
+		Data []StudentActivity `json:"data"`
+	}
+	decodeBody(t, w, &page)
+	if len(page.Data) != 2 {
+		t.Fatalf("got %d students, want 2", len(page.Data))
+	}
+	if page.Data[0].LastActiveAt != nil {
+		t.Errorf("carol last active at %v, want never", page.Data[0].LastActiveAt)
+	}
+	if page.Data[1].LastActiveAt == nil || !page.Data[1].LastActiveAt.Equal(seen) {
+		t.Errorf("alice last active at %v, want %v", page.Data[1].LastActiveAt, seen)
+	}
+}
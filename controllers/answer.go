@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"log"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/cuddest/dz-skills/mailer"
 	"github.com/cuddest/dz-skills/models"
 	"github.com/gin-gonic/gin"
 )
@@ -15,32 +17,48 @@ import (
 // SQL queries for Answer
 const (
 	createAnswerQuery = `
-		INSERT INTO answers (answer, question_id) 
-		VALUES ($1, $2) RETURNING id`
+		INSERT INTO answers (answer, question_id, is_instructor, author_teacher_id) 
+		VALUES ($1, $2, $3, $4) RETURNING id`
 
 	getAnswerQuery = `
-		SELECT id, answer, question_id 
+		SELECT id, answer, question_id, is_instructor, author_teacher_id 
 		FROM answers WHERE id = $1`
 
 	getAllAnswersQuery = `
-		SELECT id, answer, question_id 
+		SELECT id, answer, question_id, is_instructor, author_teacher_id 
 		FROM answers`
 
 	getAnswersByQuestionQuery = `
-		SELECT id, answer, question_id 
-		FROM answers WHERE question_id = $1`
+		SELECT a.id, a.answer, a.question_id, a.is_instructor, a.author_teacher_id, t.full_name 
+		FROM answers a 
+		LEFT JOIN teachers t ON t.id = a.author_teacher_id 
+		WHERE a.question_id = $1 
+		ORDER BY a.id ASC`
 
 	updateAnswerQuery = `
 		UPDATE answers 
 		SET answer = $1, question_id = $2 
-		WHERE id = $3`
+		WHERE id = $3 
+		RETURNING is_instructor, author_teacher_id`
 
 	deleteAnswerQuery = `
 		DELETE FROM answers WHERE id = $1`
+
+	getQuestionAskerQuery = `
+		SELECT s.email, s.full_name, q.question 
+		FROM questions q 
+		JOIN students s ON s.id = q.student_id 
+		WHERE q.id = $1`
 )
 
+// answerSortFields maps the accepted sort values to their columns
+var answerSortFields = map[string]string{
+	"id":          "id",
+	"question_id": "question_id",
+}
+
 func NewAnswerController(db *sql.DB) *AnswerController {
-	return &AnswerController{db: db}
+	return &AnswerController{db: db, mailer: mailer.FromEnv()}
 }
 
 func (h *AnswerController) validateAnswer(answer *models.Answer) error {
@@ -53,16 +71,29 @@ func (h *AnswerController) validateAnswer(answer *models.Answer) error {
 	return nil
 }
 
+// AuthoredAnswer is an answer along with the name of the teacher who wrote
+// it, null for answers from students
+type AuthoredAnswer struct {
+	models.Answer
+	AuthorName *string `json:"author_name"`
+}
+
+// QuestionAnswersRequest selects the question whose answers to list
+type QuestionAnswersRequest struct {
+	QuestionID uint `json:"question_id"`
+}
+
 // AnswerController handles operations on answers
 // @title Answer API
 // @description CRUD operations for managing answers
 type AnswerController struct {
-	db *sql.DB
+	db     *sql.DB
+	mailer mailer.Mailer
 }
 
 // CreateAnswer godoc
 // @Summary Create a new answer
-// @Description Create a new answer for a specific question
+// @Description Create a new answer for a specific question. Answers written by a teacher are flagged as instructor answers and the asking student is notified by email.
 // @Tags answers
 // @Accept json
 // @Produce json
@@ -101,23 +132,59 @@ func (h *AnswerController) CreateAnswer(c *gin.Context) {
 		return
 	}
 
+	// The instructor flag and author follow the caller's role, clients can't set them
+	teacherID, isTeacher, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify author"})
+		return
+	}
+	answer.IsInstructor = isTeacher
+	answer.AuthorTeacherID = nil
+	if isTeacher {
+		answer.AuthorTeacherID = &teacherID
+	}
+
 	// Create answer
-	err = h.db.QueryRowContext(ctx, createAnswerQuery, answer.Answer, answer.QuestionID).Scan(&answer.ID)
+	err = h.db.QueryRowContext(ctx, createAnswerQuery, answer.Answer, answer.QuestionID, answer.IsInstructor, answer.AuthorTeacherID).Scan(&answer.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create answer"})
 		return
 	}
 
+	if answer.IsInstructor {
+		h.notifyAsker(ctx, answer)
+	}
+
 	c.JSON(http.StatusCreated, answer)
 }
 
+// notifyAsker emails the student who asked the question about an instructor
+// answer. The answer is already saved, so failures are only logged.
+func (h *AnswerController) notifyAsker(ctx context.Context, answer models.Answer) {
+	var email, fullName, question string
+	err := h.db.QueryRowContext(ctx, getQuestionAskerQuery, answer.QuestionID).Scan(&email, &fullName, &question)
+	if err != nil {
+		log.Printf("Failed to find the student who asked question %d: %v", answer.QuestionID, err)
+		return
+	}
+
+	err = h.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Your instructor answered your question",
+		Body:    "Hello " + fullName + ",\n\nYour question:\n\n" + question + "\n\nwas answered by the instructor:\n\n" + answer.Answer + "\n",
+	})
+	if err != nil {
+		log.Printf("Failed to notify the student who asked question %d: %v", answer.QuestionID, err)
+	}
+}
+
 // GetAnswer godoc
 // @Summary Get a specific answer
 // @Description Get an answer by its ID
 // @Tags answers
 // @Accept json
 // @Produce json
-// @Param id path int true "Answer ID"
+// @Param request body IDRequest true "Answer ID"
 // @Success 200 {object} models.Answer
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -128,14 +195,13 @@ func (h *AnswerController) GetAnswer(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var answer models.Answer
-	err = h.db.QueryRowContext(ctx, getAnswerQuery, id).Scan(&answer.ID, &answer.Answer, &answer.QuestionID)
+	err := h.db.QueryRowContext(ctx, getAnswerQuery, id).Scan(&answer.ID, &answer.Answer, &answer.QuestionID, &answer.IsInstructor, &answer.AuthorTeacherID)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
 		return
@@ -150,11 +216,15 @@ func (h *AnswerController) GetAnswer(c *gin.Context) {
 
 // GetAllAnswers godoc
 // @Summary Get all answers
-// @Description Retrieve all answers from the database
+// @Description Retrieve a page of answers from the database
 // @Tags answers
 // @Accept json
 // @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of answers per page (max 100)"
+// @Param sort query string false "Sort field (id, question_id), prefix with - for descending"
 // @Success 200 {array} models.Answer
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /answers/GetAllAnswer [get]
 // GetAllAnswers retrieves all answers
@@ -162,7 +232,20 @@ func (h *AnswerController) GetAllAnswers(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	rows, err := h.db.QueryContext(ctx, getAllAnswersQuery)
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := parseSort(c, answerSortFields, "id ASC")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := getAllAnswersQuery + " ORDER BY " + order + " LIMIT $1 OFFSET $2"
+	rows, err := h.db.QueryContext(ctx, query, page.PageSize, page.Offset())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve answers"})
 		return
@@ -172,7 +255,7 @@ func (h *AnswerController) GetAllAnswers(c *gin.Context) {
 	var answers []models.Answer
 	for rows.Next() {
 		var answer models.Answer
-		if err := rows.Scan(&answer.ID, &answer.Answer, &answer.QuestionID); err != nil {
+		if err := rows.Scan(&answer.ID, &answer.Answer, &answer.QuestionID, &answer.IsInstructor, &answer.AuthorTeacherID); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process answers"})
 			return
 		}
@@ -189,30 +272,35 @@ func (h *AnswerController) GetAllAnswers(c *gin.Context) {
 
 // GetAnswersByQuestion godoc
 // @Summary Get answers by question
-// @Description Get all answers for a specific question
+// @Description Get all answers for a specific question, oldest first, with the name of the teacher who wrote each instructor answer
 // @Tags answers
 // @Accept json
 // @Produce json
-// @Param questionId path int true "Question ID"
-// @Success 200 {array} models.Answer
+// @Param request body QuestionAnswersRequest true "Question ID"
+// @Success 200 {array} AuthoredAnswer
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
-// @Router /questions/GetAnswersByQuestion [post]
+// @Router /answers/GetAnswersByQuestion [post]
 // GetAnswersByQuestion retrieves all answers for a specific question
 func (h *AnswerController) GetAnswersByQuestion(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	questionID, err := strconv.Atoi(c.Param("questionId"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID format"})
+	var req QuestionAnswersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.QuestionID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid question ID is required"})
 		return
 	}
+	questionID := req.QuestionID
 
 	// Verify question exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM questions WHERE id = $1)", questionID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM questions WHERE id = $1)", questionID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify question"})
 		return
@@ -229,10 +317,13 @@ func (h *AnswerController) GetAnswersByQuestion(c *gin.Context) {
 	}
 	defer rows.Close()
 
-	var answers []models.Answer
+	answers := []AuthoredAnswer{}
 	for rows.Next() {
-		var answer models.Answer
-		if err := rows.Scan(&answer.ID, &answer.Answer, &answer.QuestionID); err != nil {
+		var answer AuthoredAnswer
+		if err := rows.Scan(
+			&answer.ID, &answer.Answer.Answer, &answer.QuestionID, &answer.IsInstructor,
+			&answer.AuthorTeacherID, &answer.AuthorName,
+		); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process answers"})
 			return
 		}
@@ -252,7 +343,6 @@ func (h *AnswerController) GetAnswersByQuestion(c *gin.Context) {
 // @Tags answers
 // @Accept json
 // @Produce json
-// @Param id path int true "Answer ID"
 // @Param answer body models.Answer true "Updated answer object"
 // @Success 200 {object} models.Answer
 // @Failure 400 {object} map[string]interface{}
@@ -264,17 +354,16 @@ func (h *AnswerController) UpdateAnswer(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var answer models.Answer
 	if err := c.ShouldBindJSON(&answer); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if answer.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := answer.ID
 
 	if err := h.validateAnswer(&answer); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -283,7 +372,7 @@ func (h *AnswerController) UpdateAnswer(c *gin.Context) {
 
 	// Verify question exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM questions WHERE id = $1)", answer.QuestionID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM questions WHERE id = $1)", answer.QuestionID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify question"})
 		return
@@ -293,14 +382,34 @@ func (h *AnswerController) UpdateAnswer(c *gin.Context) {
 		return
 	}
 
-	_, err = h.db.ExecContext(ctx, updateAnswerQuery, answer.Answer, answer.QuestionID, id)
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Answer
+	if wantsDiff(c) {
+		err = h.db.QueryRowContext(ctx, getAnswerQuery, id).Scan(
+			&before.ID, &before.Answer, &before.QuestionID, &before.IsInstructor, &before.AuthorTeacherID,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve answer"})
+			return
+		}
+	}
+
+	// Editing an answer keeps its instructor flag and author, whatever the body says
+	err = h.db.QueryRowContext(ctx, updateAnswerQuery, answer.Answer, answer.QuestionID, id).Scan(&answer.IsInstructor, &answer.AuthorTeacherID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Answer not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update answer"})
 		return
 	}
 
-	answer.ID = uint(id)
-	c.JSON(http.StatusOK, answer)
+	respondUpdated(c, before, answer)
 }
 
 // DeleteAnswer godoc
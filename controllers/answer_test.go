@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const questionExistsInlineQuery = "SELECT EXISTS(SELECT 1 FROM questions WHERE id = $1)"
+
+func TestGetAnswersByQuestionIncludesAuthorName(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(questionExistsInlineQuery, 4).returns(row(true))
+	f.expect(getAnswersByQuestionQuery, 4).returns(
+		row(1, "Use a map", 4, true, 9, "Ada Lovelace"),
+		row(2, "Same question here", 4, false, nil, nil),
+	)
+
+	w := serve(t, NewAnswerController(db).GetAnswersByQuestion, testRequest{body: `{"question_id": 4}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var answers []AuthoredAnswer
+	decodeBody(t, w, &answers)
+	if len(answers) != 2 {
+		t.Fatalf("got %d answers, want 2", len(answers))
+	}
+	if answers[0].AuthorName == nil || *answers[0].AuthorName != "Ada Lovelace" {
+		t.Errorf("instructor answer author = %v, want Ada Lovelace", answers[0].AuthorName)
+	}
+	if answers[1].AuthorName != nil {
+		t.Errorf("student answer author = %q, want null", *answers[1].AuthorName)
+	}
+}
+
+func TestGetAnswersByQuestionMissingQuestion(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(questionExistsInlineQuery, 4).returns(row(false))
+
+	w := serve(t, NewAnswerController(db).GetAnswersByQuestion, testRequest{body: `{"question_id": 4}`})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestGetAnswersByQuestionRejectsBadBodies(t *testing.T) {
+	for _, body := range []string{``, `{}`, `{"question_id": "four"}`} {
+		db, _ := newFakeDB(t)
+		w := serve(t, NewAnswerController(db).GetAnswersByQuestion, testRequest{body: body})
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+}
+
+func TestGetAllAnswersPaginatesAndSorts(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect("FROM answers ORDER BY question_id DESC LIMIT $1 OFFSET $2", 5, 10).returns(
+		row(11, "Use a map", 4, true, 9),
+	)
+
+	w := serve(t, NewAnswerController(db).GetAllAnswers, testRequest{
+		method: http.MethodGet,
+		query:  "page=3&page_size=5&sort=-question_id",
+	})
+	expectStatus(t, w, http.StatusOK)
+}
+
+func TestGetAllAnswersRejectsUnknownSort(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	w := serve(t, NewAnswerController(db).GetAllAnswers, testRequest{
+		method: http.MethodGet,
+		query:  "sort=answer",
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestCreateAnswerByTeacherIsFlaggedAndNotifies(t *testing.T) {
+	db, f := newFakeDB(t)
+	h := NewAnswerController(db)
+	m := &recordingMailer{}
+	h.mailer = m
+	f.expect(questionExistsInlineQuery, 4).returns(row(true))
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(9))
+	f.expect(createAnswerQuery, "Use a map", 4, true, 9).returns(row(12))
+	f.expect(getQuestionAskerQuery, 4).returns(row("alice@example.com", "Alice", "How do I count words?"))
+
+	w := serve(t, h.CreateAnswer, testRequest{
+		body:   `{"Answer": "Use a map", "question_id": 4, "is_instructor": false}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusCreated)
+
+	var answer AuthoredAnswer
+	decodeBody(t, w, &answer)
+	if !answer.IsInstructor || answer.AuthorTeacherID == nil || *answer.AuthorTeacherID != 9 {
+		t.Errorf("answer = %+v, want an instructor answer by teacher 9", answer.Answer)
+	}
+	if len(m.sent) != 1 || m.sent[0].To != "alice@example.com" {
+		t.Fatalf("sent = %+v, want one notification to alice@example.com", m.sent)
+	}
+	if !strings.Contains(m.sent[0].Body, "Use a map") {
+		t.Errorf("notification body %q does not quote the answer", m.sent[0].Body)
+	}
+}
+
+func TestCreateAnswerByStudentIsNotFlagged(t *testing.T) {
+	db, f := newFakeDB(t)
+	h := NewAnswerController(db)
+	m := &recordingMailer{}
+	h.mailer = m
+	f.expect(questionExistsInlineQuery, 4).returns(row(true))
+	f.expect(createAnswerQuery, "Same question here", 4, false, nil).returns(row(13))
+
+	// A client can't claim the flag for itself
+	w := serve(t, h.CreateAnswer, testRequest{
+		body:   `{"Answer": "Same question here", "question_id": 4, "is_instructor": true}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusCreated)
+
+	var answer AuthoredAnswer
+	decodeBody(t, w, &answer)
+	if answer.IsInstructor || answer.AuthorTeacherID != nil {
+		t.Errorf("answer = %+v, want a student answer", answer.Answer)
+	}
+	if len(m.sent) != 0 {
+		t.Errorf("sent = %+v, want no notification", m.sent)
+	}
+}
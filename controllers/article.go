@@ -15,22 +15,34 @@ import (
 // SQL queries as constants
 const (
 	// Article queries
+	// New articles go after the course's existing ones
 	createArticleQuery = `
-		INSERT INTO articles (title, link, description, course_id) 
-		VALUES ($1, $2, $3, $4) RETURNING id`
+		INSERT INTO articles (title, link, description, course_id, position) 
+		SELECT $1, $2, $3, $4, COALESCE(MAX(position), 0) + 1 
+		FROM articles WHERE course_id = $4 
+		RETURNING id, position`
 	getArticleQuery = `
-		SELECT id, title, link, description, course_id 
+		SELECT id, title, link, description, course_id, position 
 		FROM articles WHERE id = $1`
 	getAllArticlesQuery = `
-		SELECT id, title, link, description, course_id 
+		SELECT id, title, link, description, course_id, position 
 		FROM articles`
 	getArticlesByCourseQuery = `
-		SELECT id, title, link, description, course_id 
-		FROM articles WHERE course_id = $1`
+		SELECT id, title, link, description, course_id, position 
+		FROM articles WHERE course_id = $1 
+		ORDER BY position ASC, id ASC`
+	// Position is only changed through ReorderArticles
 	updateArticleQuery = `
 		UPDATE articles 
 		SET title = $1, link = $2, description = $3, course_id = $4 
-		WHERE id = $5`
+		WHERE id = $5 
+		RETURNING position`
+	getCourseArticleIDsQuery = `
+		SELECT id FROM articles WHERE course_id = $1`
+	reorderArticlesQuery = `
+		UPDATE articles SET position = v.position 
+		FROM unnest($1::bigint[]) WITH ORDINALITY AS v(id, position) 
+		WHERE articles.id = v.id AND articles.course_id = $2`
 	deleteArticleQuery = `
 		DELETE FROM articles WHERE id = $1`
 )
@@ -42,6 +54,20 @@ type ArticleController struct {
 	db *sql.DB
 }
 
+// errArticleOrderMismatch rejects a reorder that doesn't list exactly the course's articles
+var errArticleOrderMismatch = errors.New("article_ids must list every article of the course exactly once")
+
+// CourseArticlesRequest selects the course whose articles to list
+type CourseArticlesRequest struct {
+	CourseID uint `json:"course_id"`
+}
+
+// ReorderArticlesRequest lists every article of a course in reading order
+type ReorderArticlesRequest struct {
+	CourseID   uint   `json:"course_id"`
+	ArticleIDs []uint `json:"article_ids"`
+}
+
 func NewArticleController(db *sql.DB) *ArticleController {
 	return &ArticleController{db: db}
 }
@@ -88,7 +114,7 @@ func (h *ArticleController) CreateArticle(c *gin.Context) {
 
 	// Verify course exists
 	var exists bool
-	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", article.CourseID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", article.CourseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -99,7 +125,7 @@ func (h *ArticleController) CreateArticle(c *gin.Context) {
 	}
 
 	err = h.db.QueryRowContext(ctx, createArticleQuery,
-		article.Title, article.Link, article.Description, article.CourseID).Scan(&article.ID)
+		article.Title, article.Link, article.Description, article.CourseID).Scan(&article.ID, &article.Position)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create article"})
 		return
@@ -114,7 +140,7 @@ func (h *ArticleController) CreateArticle(c *gin.Context) {
 // @Tags articles
 // @Accept json
 // @Produce json
-// @Param id path int true "Article ID"
+// @Param request body IDRequest true "Article ID"
 // @Success 200 {object} models.Article
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -124,16 +150,15 @@ func (h *ArticleController) GetArticle(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var article models.Article
-	err = h.db.QueryRowContext(ctx, getArticleQuery, id).Scan(
+	err := h.db.QueryRowContext(ctx, getArticleQuery, id).Scan(
 		&article.ID, &article.Title, &article.Link,
-		&article.Description, &article.CourseID,
+		&article.Description, &article.CourseID, &article.Position,
 	)
 
 	if err == sql.ErrNoRows {
@@ -173,7 +198,7 @@ func (h *ArticleController) GetAllArticles(c *gin.Context) {
 		var article models.Article
 		if err := rows.Scan(
 			&article.ID, &article.Title, &article.Link,
-			&article.Description, &article.CourseID,
+			&article.Description, &article.CourseID, &article.Position,
 		); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process articles"})
 			return
@@ -190,9 +215,10 @@ func (h *ArticleController) GetAllArticles(c *gin.Context) {
 // @Tags articles
 // @Accept json
 // @Produce json
-// @Param courseId path int true "Course ID"
+// @Param request body CourseArticlesRequest true "Course ID"
 // @Success 200 {array} models.Article
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /articles/GetArticlesByCourse [post]
@@ -200,9 +226,31 @@ func (h *ArticleController) GetArticlesByCourse(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	courseID, err := strconv.Atoi(c.Param("courseId"))
+	var req CourseArticlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+	courseID := req.CourseID
+
+	// Students lose access to course material once their enrollment expires
+	if denyExpiredAccess(ctx, h.db, c, courseID) {
+		return
+	}
+
+	// Verify course exists
+	var exists bool
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", courseID).Scan(&exists)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
 		return
 	}
 
@@ -213,12 +261,12 @@ func (h *ArticleController) GetArticlesByCourse(c *gin.Context) {
 	}
 	defer rows.Close()
 
-	var articles []models.Article
+	articles := []models.Article{}
 	for rows.Next() {
 		var article models.Article
 		if err := rows.Scan(
 			&article.ID, &article.Title, &article.Link,
-			&article.Description, &article.CourseID,
+			&article.Description, &article.CourseID, &article.Position,
 		); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process articles"})
 			return
@@ -235,7 +283,6 @@ func (h *ArticleController) GetArticlesByCourse(c *gin.Context) {
 // @Tags articles
 // @Accept json
 // @Produce json
-// @Param id path int true "Article ID"
 // @Param article body models.Article true "Updated article object"
 // @Success 200 {object} models.Article
 // @Failure 400 {object} map[string]interface{}
@@ -246,43 +293,52 @@ func (h *ArticleController) UpdateArticle(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var article models.Article
 	if err := c.ShouldBindJSON(&article); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if article.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := article.ID
 
 	if err := h.validateArticle(&article); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	result, err := h.db.ExecContext(ctx, updateArticleQuery,
-		article.Title, article.Link, article.Description,
-		article.CourseID, id)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update article"})
-		return
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Article
+	if wantsDiff(c) {
+		err := h.db.QueryRowContext(ctx, getArticleQuery, id).Scan(
+			&before.ID, &before.Title, &before.Link,
+			&before.Description, &before.CourseID, &before.Position,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve article"})
+			return
+		}
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to confirm update"})
+	err := h.db.QueryRowContext(ctx, updateArticleQuery,
+		article.Title, article.Link, article.Description,
+		article.CourseID, id).Scan(&article.Position)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
 		return
 	}
-	if rowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update article"})
 		return
 	}
 
-	article.ID = uint(id)
-	c.JSON(http.StatusOK, article)
+	respondUpdated(c, before, article)
 }
 
 // DeleteArticle godoc
@@ -325,3 +381,133 @@ func (h *ArticleController) DeleteArticle(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Article deleted successfully"})
 }
+
+// ReorderArticles godoc
+// @Summary Reorder the articles of a course
+// @Description Set the reading order of a course's articles. article_ids must list every article of the course exactly once, first to last. Only the course teacher may reorder.
+// @Tags articles
+// @Accept json
+// @Produce json
+// @Param request body ReorderArticlesRequest true "Course ID and ordered article IDs"
+// @Success 200 {array} models.Article
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /articles/reorder [put]
+func (h *ArticleController) ReorderArticles(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req ReorderArticlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course ID is required"})
+		return
+	}
+
+	teacherID, ok, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only teachers can reorder articles"})
+		return
+	}
+
+	var ownerID uint
+	err = h.db.QueryRowContext(ctx, getCourseTeacherQuery, req.CourseID).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if ownerID != teacherID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the course teacher can reorder its articles"})
+		return
+	}
+
+	ids := make([]int64, len(req.ArticleIDs))
+	for i, id := range req.ArticleIDs {
+		ids[i] = int64(id)
+	}
+
+	err = withTx(ctx, h.db, func(tx *sql.Tx) error {
+		if err := checkArticleSet(ctx, tx, req.CourseID, req.ArticleIDs); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, reorderArticlesQuery, ids, req.CourseID)
+		return err
+	})
+	if err == errArticleOrderMismatch {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder articles"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getArticlesByCourseQuery, req.CourseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve articles"})
+		return
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+	for rows.Next() {
+		var article models.Article
+		if err := rows.Scan(
+			&article.ID, &article.Title, &article.Link,
+			&article.Description, &article.CourseID, &article.Position,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process articles"})
+			return
+		}
+		articles = append(articles, article)
+	}
+
+	c.JSON(http.StatusOK, articles)
+}
+
+// checkArticleSet makes sure ids holds every article of the course exactly
+// once, returning errArticleOrderMismatch otherwise
+func checkArticleSet(ctx context.Context, tx *sql.Tx, courseID uint, ids []uint) error {
+	rows, err := tx.QueryContext(ctx, getCourseArticleIDsQuery, courseID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	remaining := make(map[uint]bool)
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+		remaining[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(ids) != len(remaining) {
+		return errArticleOrderMismatch
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return errArticleOrderMismatch
+		}
+		delete(remaining, id)
+	}
+	return nil
+}
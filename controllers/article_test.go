@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cuddest/dz-skills/models"
+)
+
+func TestGetArticlesByCourseInPositionOrder(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsInlineQuery, 7).returns(row(true))
+	f.expect(getArticlesByCourseQuery, 7).returns(
+		row(12, "Intro", "https://example.com/intro", "", 7, 1),
+		row(10, "Setup", "https://example.com/setup", "", 7, 2),
+	)
+
+	w := serve(t, NewArticleController(db).GetArticlesByCourse, testRequest{body: `{"course_id": 7}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var articles []models.Article
+	decodeBody(t, w, &articles)
+	if len(articles) != 2 || articles[0].ID != 12 || articles[1].ID != 10 {
+		t.Errorf("articles = %+v, want 12 then 10", articles)
+	}
+}
+
+func TestGetArticlesByCourseMissingCourse(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsInlineQuery, 7).returns(row(false))
+
+	w := serve(t, NewArticleController(db).GetArticlesByCourse, testRequest{body: `{"course_id": 7}`})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestGetArticlesByCourseRejectsBadBodies(t *testing.T) {
+	for _, body := range []string{``, `{}`, `{"course_id": "seven"}`} {
+		db, _ := newFakeDB(t)
+		w := serve(t, NewArticleController(db).GetArticlesByCourse, testRequest{body: body})
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+}
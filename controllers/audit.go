@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	createAuditLogQuery = `
+		INSERT INTO audit_logs (actor, action, details, created_at) 
+		VALUES ($1, $2, $3, $4)`
+)
+
+// recordAudit writes an audit entry for an action performed by the caller,
+// inside tx so the entry only exists if the action is committed
+func recordAudit(ctx context.Context, tx *sql.Tx, c *gin.Context, action, details string) error {
+	actor := "unknown"
+	if claims, ok := auth.ClaimsFromContext(c); ok {
+		actor = claims.Username
+	}
+	_, err := tx.ExecContext(ctx, createAuditLogQuery, actor, action, details, now())
+	return err
+}
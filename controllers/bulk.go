@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errNotOwned = errors.New("resources are not owned by the teacher")
+
+// deleteOwned deletes ids in one transaction after checking, with
+// notOwnedQuery, that none of them belongs to a course teacherID doesn't own.
+// notOwnedQuery takes the ids and the teacher ID and returns the offending ids,
+// deleteQuery takes the ids. When some are not owned nothing is deleted and
+// errNotOwned is returned along with them.
+func deleteOwned(ctx context.Context, db *sql.DB, ids []int64, teacherID uint, notOwnedQuery, deleteQuery string) (deleted int64, notOwned []int64, err error) {
+	err = withTx(ctx, db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, notOwnedQuery, ids, teacherID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			notOwned = append(notOwned, id)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(notOwned) > 0 {
+			return errNotOwned
+		}
+
+		result, err := tx.ExecContext(ctx, deleteQuery, ids)
+		if err != nil {
+			return err
+		}
+		deleted, err = result.RowsAffected()
+		return err
+	})
+	return deleted, notOwned, err
+}
+
+// respondBulkDelete writes the outcome of deleteOwned
+func respondBulkDelete(c *gin.Context, requested int, deleted int64, notOwned []int64, err error) {
+	if err == errNotOwned {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":     "Some IDs belong to courses you don't own, nothing was deleted",
+			"not_owned": notOwned,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete quizzes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"requested": requested,
+		"deleted":   deleted,
+	})
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cuddest/dz-skills/models"
@@ -52,8 +54,22 @@ const (
 
 	deleteCategoryQuery = `
 		DELETE FROM categories WHERE id = $1`
+
+	categoryNameTakenQuery = `
+		SELECT EXISTS(SELECT 1 FROM categories WHERE LOWER(name) = LOWER($1))`
 )
 
+// maxBulkCategories caps how many categories one bulk request may create
+const maxBulkCategories = 100
+
+// errCategoryExists rejects a bulk import naming a category that already exists
+var errCategoryExists = errors.New("a category with this name already exists")
+
+// BulkCategoriesRequest lists categories to create, each with its subcategories
+type BulkCategoriesRequest struct {
+	Categories []models.Category `json:"categories"`
+}
+
 // CategoryController handles operations on categories
 // @title Category API
 // @description CRUD operations for managing categories
@@ -110,6 +126,107 @@ func (h *CategoryController) CreateCategory(c *gin.Context) {
 	c.JSON(http.StatusCreated, category)
 }
 
+// validateBulkCategories checks names are given and not repeated within the
+// request, ignoring case. Category names are unique, subcategory names are
+// unique within their category.
+func (h *CategoryController) validateBulkCategories(categories []models.Category) error {
+	if len(categories) == 0 || len(categories) > maxBulkCategories {
+		return fmt.Errorf("between 1 and %d categories are required", maxBulkCategories)
+	}
+	categoryNames := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		if err := h.validateCategory(&category); err != nil {
+			return err
+		}
+		key := strings.ToLower(category.Name)
+		if categoryNames[key] {
+			return fmt.Errorf("category %q is listed more than once", category.Name)
+		}
+		categoryNames[key] = true
+
+		subCatNames := make(map[string]bool, len(category.SubCats))
+		for _, subcat := range category.SubCats {
+			if subcat.Name == "" {
+				return fmt.Errorf("subcategory name is required in category %q", category.Name)
+			}
+			key := strings.ToLower(subcat.Name)
+			if subCatNames[key] {
+				return fmt.Errorf("subcategory %q is listed more than once in category %q", subcat.Name, category.Name)
+			}
+			subCatNames[key] = true
+		}
+	}
+	return nil
+}
+
+// CreateCategoriesBulk godoc
+// @Summary Create categories in bulk
+// @Description Create several categories along with their subcategories in one transaction. Nothing is created when any category name already exists or a name is repeated. Admin only.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param request body BulkCategoriesRequest true "Categories with their subcategories"
+// @Success 201 {array} models.Category
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /categories/bulk [post]
+func (h *CategoryController) CreateCategoriesBulk(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	var req BulkCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validateBulkCategories(req.Categories); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	categories := req.Categories
+	err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+		for i := range categories {
+			category := &categories[i]
+			var taken bool
+			if err := tx.QueryRowContext(ctx, categoryNameTakenQuery, category.Name).Scan(&taken); err != nil {
+				return err
+			}
+			if taken {
+				return fmt.Errorf("%w: %s", errCategoryExists, category.Name)
+			}
+			if err := tx.QueryRowContext(ctx, createCategoryQuery, category.Name).Scan(&category.ID); err != nil {
+				return err
+			}
+
+			if category.SubCats == nil {
+				category.SubCats = []models.SubCat{}
+			}
+			for j := range category.SubCats {
+				subcat := &category.SubCats[j]
+				subcat.CategoryID = category.ID
+				if err := tx.QueryRowContext(ctx, createSubCatQuery, subcat.Name, subcat.CategoryID).Scan(&subcat.ID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errCategoryExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create categories"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, categories)
+}
+
 // GetCategory godoc
 // @Summary Get a specific category
 // @Description Get a category by its ID, including its subcategories
@@ -195,7 +312,6 @@ func (h *CategoryController) GetAllCategories(c *gin.Context) {
 // @Tags categories
 // @Accept json
 // @Produce json
-// @Param id path int true "Category ID"
 // @Param category body models.Category true "Updated category object"
 // @Success 200 {object} models.Category
 // @Failure 400 {object} map[string]interface{}
@@ -206,23 +322,39 @@ func (h *CategoryController) UpdateCategory(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var category models.Category
 	if err := c.ShouldBindJSON(&category); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if category.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := category.ID
 
 	if err := h.validateCategory(&category); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Category
+	var subcatsJSON []byte
+	if wantsDiff(c) {
+		err := h.db.QueryRowContext(ctx, getCategoryQuery, id).Scan(
+			&before.ID, &before.Name, &subcatsJSON,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve category"})
+			return
+		}
+	}
+
 	result, err := h.db.ExecContext(ctx, updateCategoryQuery, category.Name, id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category"})
@@ -239,8 +371,7 @@ func (h *CategoryController) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	category.ID = uint(id)
-	c.JSON(http.StatusOK, category)
+	respondUpdated(c, before, category)
 }
 
 // DeleteCategory godoc
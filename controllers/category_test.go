@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cuddest/dz-skills/models"
+)
+
+func TestUpdateCategoryTakesIDFromBody(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(updateCategoryQuery, "Design", 4).affects(1)
+
+	w := serve(t, NewCategoryController(db).UpdateCategory, testRequest{
+		method: http.MethodPut,
+		body:   `{"ID": 4, "Name": "Design"}`,
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var got map[string]interface{}
+	decodeBody(t, w, &got)
+	if got["ID"] != float64(4) || got["Name"] != "Design" {
+		t.Errorf("response = %v, want category 4 named Design", got)
+	}
+}
+
+func TestUpdateCategoryRequiresID(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	w := serve(t, NewCategoryController(db).UpdateCategory, testRequest{
+		method: http.MethodPut,
+		body:   `{"Name": "Design"}`,
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestUpdateCategoryDiff(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getCategoryQuery, 4).returns(row(4, "Art", []byte("[]")))
+	f.expect(updateCategoryQuery, "Design", 4).affects(1)
+
+	w := serve(t, NewCategoryController(db).UpdateCategory, testRequest{
+		method:  http.MethodPut,
+		body:    `{"ID": 4, "Name": "Design"}`,
+		headers: map[string]string{"Prefer": "return=diff"},
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var got map[string]interface{}
+	decodeBody(t, w, &got)
+	if len(got) != 1 || got["Name"] != "Design" {
+		t.Errorf("diff = %v, want only the new Name", got)
+	}
+}
+
+func TestUpdateCategoryNotFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(updateCategoryQuery, "Design", 4).affects(0)
+
+	w := serve(t, NewCategoryController(db).UpdateCategory, testRequest{
+		method: http.MethodPut,
+		body:   `{"ID": 4, "Name": "Design"}`,
+	})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestCreateCategoriesBulkImportsTree(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(categoryNameTakenQuery, "Programming").returns(row(false))
+	f.expect(createCategoryQuery, "Programming").returns(row(1))
+	f.expect(createSubCatQuery, "Go", 1).returns(row(10))
+	f.expect(createSubCatQuery, "Rust", 1).returns(row(11))
+	f.expect(categoryNameTakenQuery, "Design").returns(row(false))
+	f.expect(createCategoryQuery, "Design").returns(row(2))
+
+	w := serve(t, NewCategoryController(db).CreateCategoriesBulk, testRequest{body: `{"categories": [
+		{"Name": "Programming", "SubCats": [{"Name": "Go"}, {"Name": "Rust"}]},
+		{"Name": "Design"}
+	]}`})
+	expectStatus(t, w, http.StatusCreated)
+
+	var got []models.Category
+	decodeBody(t, w, &got)
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 2 {
+		t.Fatalf("categories = %+v, want Programming 1 and Design 2", got)
+	}
+	if len(got[0].SubCats) != 2 || got[0].SubCats[1].ID != 11 || got[0].SubCats[1].CategoryID != 1 {
+		t.Errorf("subcategories = %+v, want Go 10 and Rust 11 under category 1", got[0].SubCats)
+	}
+	if got[1].SubCats == nil {
+		t.Errorf("Design subcategories are null, want an empty list")
+	}
+	if f.commits != 1 {
+		t.Errorf("commits = %d, want 1", f.commits)
+	}
+}
+
+func TestCreateCategoriesBulkRollsBackOnExistingName(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(categoryNameTakenQuery, "Programming").returns(row(false))
+	f.expect(createCategoryQuery, "Programming").returns(row(1))
+	f.expect(createSubCatQuery, "Go", 1).returns(row(10))
+	f.expect(categoryNameTakenQuery, "Design").returns(row(true))
+
+	w := serve(t, NewCategoryController(db).CreateCategoriesBulk, testRequest{body: `{"categories": [
+		{"Name": "Programming", "SubCats": [{"Name": "Go"}]},
+		{"Name": "Design"}
+	]}`})
+	expectStatus(t, w, http.StatusConflict)
+
+	if f.commits != 0 || f.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want the import rolled back", f.commits, f.rollbacks)
+	}
+}
+
+func TestCreateCategoriesBulkRejectsRepeatedNames(t *testing.T) {
+	tests := map[string]string{
+		"no categories":        `{"categories": []}`,
+		"repeated category":    `{"categories": [{"Name": "Design"}, {"Name": "design"}]}`,
+		"repeated subcategory": `{"categories": [{"Name": "Design", "SubCats": [{"Name": "UX"}, {"Name": "ux"}]}]}`,
+		"unnamed subcategory":  `{"categories": [{"Name": "Design", "SubCats": [{"Name": ""}]}]}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Nothing is scripted, so reaching the database fails the test
+			db, _ := newFakeDB(t)
+			w := serve(t, NewCategoryController(db).CreateCategoriesBulk, testRequest{body: body})
+			expectStatus(t, w, http.StatusBadRequest)
+		})
+	}
+}
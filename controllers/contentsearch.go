@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cuddest/dz-skills/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	searchCourseVideosQuery = `
+		SELECT id, title, link, course_id 
+		FROM videos 
+		WHERE course_id = $1 AND title ILIKE '%' || $2 || '%' 
+		ORDER BY id`
+
+	searchCourseArticlesQuery = `
+		SELECT id, title, link, description, course_id, position 
+		FROM articles 
+		WHERE course_id = $1 AND (title ILIKE '%' || $2 || '%' OR description ILIKE '%' || $2 || '%') 
+		ORDER BY position ASC, id ASC`
+)
+
+// ContentSearchRequest searches the content of one course
+type ContentSearchRequest struct {
+	CourseID uint   `json:"course_id"`
+	Query    string `json:"q"`
+}
+
+// ContentSearchResult is the content of a course matching a search, by type
+type ContentSearchResult struct {
+	Videos   []models.Video   `json:"videos"`
+	Articles []models.Article `json:"articles"`
+}
+
+// @Summary Search a course's content
+// @Description Find the videos whose title and the articles whose title or description contain q, within one course
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param request body ContentSearchRequest true "Course ID and search text"
+// @Success 200 {object} ContentSearchResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/searchContent [post]
+func (h *CourseController) SearchCourseContent(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req ContentSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course ID is required"})
+		return
+	}
+	q := strings.TrimSpace(req.Query)
+	if utf8.RuneCountInString(q) < minSearchLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("q must be at least %d characters", minSearchLength)})
+		return
+	}
+
+	exists, err := h.courseExists(ctx, req.CourseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check course existence"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	// Students lose access to course material once their enrollment expires
+	if denyExpiredAccess(ctx, h.db, c, req.CourseID) {
+		return
+	}
+
+	videos, err := h.searchVideos(ctx, req.CourseID, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search videos"})
+		return
+	}
+	articles, err := h.searchArticles(ctx, req.CourseID, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search articles"})
+		return
+	}
+
+	respond(c, http.StatusOK, ContentSearchResult{Videos: videos, Articles: articles})
+}
+
+// searchVideos lists the videos of a course whose title contains q
+func (h *CourseController) searchVideos(ctx context.Context, courseID uint, q string) ([]models.Video, error) {
+	rows, err := h.db.QueryContext(ctx, searchCourseVideosQuery, courseID, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := []models.Video{}
+	for rows.Next() {
+		var video models.Video
+		if err := rows.Scan(&video.ID, &video.Title, &video.Link, &video.CourseID); err != nil {
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+	return videos, rows.Err()
+}
+
+// searchArticles lists the articles of a course whose title or description contains q
+func (h *CourseController) searchArticles(ctx context.Context, courseID uint, q string) ([]models.Article, error) {
+	rows, err := h.db.QueryContext(ctx, searchCourseArticlesQuery, courseID, q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+	for rows.Next() {
+		var article models.Article
+		if err := rows.Scan(&article.ID, &article.Title, &article.Link, &article.Description, &article.CourseID, &article.Position); err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+	return articles, rows.Err()
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSearchCourseContentMatchesBothTypes(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsQuery, 7).returns(row(true))
+	f.expect(searchCourseVideosQuery, 7, "setup").returns(row(1, "Setup walkthrough", "setup.mp4", 7))
+	f.expect(searchCourseArticlesQuery, 7, "setup").returns(
+		row(2, "Installing Go", "install.html", "Setup on every platform", 7, 1),
+		row(3, "Editor setup", "editor.html", "", 7, 2),
+	)
+
+	w := serve(t, NewCourseController(db).SearchCourseContent, testRequest{body: `{"course_id": 7, "q": "  setup "}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var got ContentSearchResult
+	decodeBody(t, w, &got)
+	if len(got.Videos) != 1 || got.Videos[0].Title != "Setup walkthrough" {
+		t.Errorf("videos = %+v, want the setup walkthrough", got.Videos)
+	}
+	if len(got.Articles) != 2 || got.Articles[0].ID != 2 || got.Articles[1].ID != 3 {
+		t.Errorf("articles = %+v, want articles 2 and 3 in reading order", got.Articles)
+	}
+}
+
+func TestSearchCourseContentWithoutMatches(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsQuery, 7).returns(row(true))
+	f.expect(searchCourseVideosQuery, 7, "kubernetes")
+	f.expect(searchCourseArticlesQuery, 7, "kubernetes")
+
+	w := serve(t, NewCourseController(db).SearchCourseContent, testRequest{body: `{"course_id": 7, "q": "kubernetes"}`})
+	expectStatus(t, w, http.StatusOK)
+
+	got := responseKeys(t, w.Body.Bytes())
+	for _, section := range []string{"videos", "articles"} {
+		if list, ok := got[section].([]interface{}); !ok || len(list) != 0 {
+			t.Errorf("%s = %v, want an empty array", section, got[section])
+		}
+	}
+}
+
+func TestSearchCourseContentCourseNotFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsQuery, 7).returns(row(false))
+
+	w := serve(t, NewCourseController(db).SearchCourseContent, testRequest{body: `{"course_id": 7, "q": "setup"}`})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestSearchCourseContentRejectsBadBodies(t *testing.T) {
+	tests := map[string]string{
+		"missing course": `{"q": "setup"}`,
+		"short query":    `{"course_id": 7, "q": " s "}`,
+		"malformed":      `{"course_id": "7"}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			db, _ := newFakeDB(t)
+			w := serve(t, NewCourseController(db).SearchCourseContent, testRequest{body: body})
+			expectStatus(t, w, http.StatusBadRequest)
+		})
+	}
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 	"github.com/cuddest/dz-skills/models"
 	"github.com/gin-gonic/gin"
 )
@@ -14,33 +19,175 @@ import (
 // SQL queries as constants to improve maintainability
 const (
 	createCourseQuery = `
-		INSERT INTO courses (name, description, pricing, duration, image, language, level, teacher_id, category_id) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+		INSERT INTO courses (name, description, pricing, duration, image, language, level, access_days, teacher_id, category_id, sub_cat_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) 
 		RETURNING id`
 
 	getCourseQuery = `
-		SELECT id, name, description, pricing, duration, image, language, level, teacher_id, category_id 
+		SELECT id, name, description, pricing, duration, image, language, level, access_days, teacher_id, category_id, sub_cat_id, created_at, average_rating, ratings_count 
 		FROM courses 
-		WHERE id = $1`
+		WHERE id = $1 AND deleted_at IS NULL`
+
+	getCourseDetailsQuery = `
+		SELECT c.id, c.name, c.description, c.pricing, c.duration, c.image, c.language, c.level,
+			c.access_days, c.teacher_id, c.category_id, c.sub_cat_id, c.created_at, c.average_rating, c.ratings_count,
+			COALESCE(cat.name, ''),
+			COALESCE(t.full_name, ''), COALESCE(t.username, ''), COALESCE(t.picture, '')
+		FROM courses c
+		LEFT JOIN categories cat ON c.category_id = cat.id
+		LEFT JOIN teachers t ON c.teacher_id = t.id
+		WHERE c.id = $1 AND c.deleted_at IS NULL`
 
 	getAllCoursesQuery = `
-		SELECT id, name, description, pricing, duration, image, language, level, teacher_id, category_id 
-		FROM courses`
+		SELECT id, name, description, pricing, duration, image, language, level, access_days, teacher_id, category_id, sub_cat_id, created_at, average_rating, ratings_count 
+		FROM courses 
+		WHERE deleted_at IS NULL`
+
+	countCoursesQuery = `
+		SELECT COUNT(*) FROM courses WHERE deleted_at IS NULL`
 
+	getManagedCoursesQuery = `
+		SELECT c.id, c.name, c.description, c.pricing, c.duration, c.image, c.language, c.level, 
+			c.access_days, c.teacher_id, c.category_id, c.sub_cat_id, c.created_at, c.average_rating, c.ratings_count, 
+			COUNT(sc.student_id) AS enrollment_count 
+		FROM courses c 
+		LEFT JOIN student_courses sc ON sc.course_id = c.id 
+		WHERE c.teacher_id = $1 AND c.deleted_at IS NULL 
+		GROUP BY c.id 
+		ORDER BY c.created_at DESC NULLS LAST, c.id DESC 
+		LIMIT $2 OFFSET $3`
+
+	getCoursesByTeacherQuery = `
+		SELECT c.id, c.name, c.description, c.pricing, c.duration, c.image, c.language, c.level,
+			c.access_days, c.teacher_id, c.category_id, c.sub_cat_id, c.created_at, c.average_rating, c.ratings_count,
+			COALESCE(cat.name, '')
+		FROM courses c
+		LEFT JOIN categories cat ON c.category_id = cat.id
+		WHERE c.teacher_id = $1 AND c.deleted_at IS NULL
+		ORDER BY c.created_at DESC NULLS LAST, c.id DESC`
+
+	countTeacherCoursesQuery = `
+		SELECT COUNT(*) FROM courses WHERE teacher_id = $1 AND deleted_at IS NULL`
 
 	updateCourseQuery = `
 		UPDATE courses 
 		SET name = $1, description = $2, pricing = $3, duration = $4, 
-			image = $5, language = $6, level = $7, teacher_id = $8, category_id = $9 
-		WHERE id = $10`
+			image = $5, language = $6, level = $7, access_days = $8, teacher_id = $9, category_id = $10, 
+			sub_cat_id = $11 
+		WHERE id = $12 AND deleted_at IS NULL`
+
+	// Courses are soft deleted, the row and its content stay so it can be restored
+	deleteCourseQuery = `UPDATE courses SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+
+	restoreCourseQuery = `UPDATE courses SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	courseExistsQuery = `SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)`
+
+	getSubCatCategoryQuery = `
+		SELECT category_id FROM sub_cats WHERE id = $1`
+
+	countSubCatCoursesQuery = `
+		SELECT COUNT(*) FROM courses WHERE sub_cat_id = $1 AND deleted_at IS NULL`
+
+	// coursePriceExpr is the numeric value of the pricing column, NULL when it
+	// holds something that is not a plain non-negative number
+	coursePriceExpr = `(CASE WHEN pricing ~ '^[0-9]+(\.[0-9]+)?$' THEN pricing::numeric END)`
+
+	getTeacherActiveQuery = `
+		SELECT active FROM teachers WHERE id = $1`
 
-	deleteCourseQuery = `DELETE FROM courses WHERE id = $1`
+	lockCourseTeacherQuery = `
+		SELECT teacher_id FROM courses WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`
+
+	updateCourseTeacherQuery = `
+		UPDATE courses SET teacher_id = $1 WHERE id = $2`
+
+	searchCoursesQuery = `
+		SELECT id, name, description, pricing, duration, image, language, level, access_days, teacher_id, category_id, sub_cat_id, created_at, average_rating, ratings_count 
+		FROM courses 
+		WHERE (name ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%') AND deleted_at IS NULL
+		ORDER BY id`
+
+	getTopRatedCoursesQuery = `
+		SELECT id, name, description, pricing, duration, image, language, level, access_days, teacher_id, category_id, sub_cat_id, created_at, average_rating, ratings_count 
+		FROM courses 
+		WHERE ratings_count >= $1 AND deleted_at IS NULL 
+		ORDER BY average_rating DESC, ratings_count DESC, id ASC 
+		LIMIT $2`
+)
+
+// minSearchLength is the shortest keyword SearchCourses accepts
+const minSearchLength = 2
+
+// Defaults and bounds of GetTopRatedCourses, requiring a few ratings keeps a
+// single 5-star rating from topping the list
+const (
+	defaultTopRatedLimit      = 10
+	maxTopRatedLimit          = 50
+	defaultTopRatedMinRatings = 3
 )
 
+// courseSortOptions maps the accepted sort values of course listings to their
+// ORDER BY clause, the raw parameter never reaches the query
+var courseSortOptions = map[string]string{
+	"newest":     "created_at DESC NULLS LAST, id DESC",
+	"price_asc":  coursePriceExpr + " ASC NULLS LAST, id ASC",
+	"price_desc": coursePriceExpr + " DESC NULLS LAST, id ASC",
+	"name":       "name ASC, id ASC",
+}
+
+// parseCourseSort resolves the sort query parameter of course listings,
+// defaulting to the newest courses first
+func parseCourseSort(c *gin.Context) (string, error) {
+	sort := c.DefaultQuery("sort", "newest")
+	order, ok := courseSortOptions[sort]
+	if !ok {
+		return "", fmt.Errorf("unsupported sort value: %s", sort)
+	}
+	return order, nil
+}
+
 type CourseController struct {
 	db *sql.DB
 }
 
+// CourseIDRequest identifies a single course in a request body
+type CourseIDRequest struct {
+	ID uint `json:"id"`
+}
+
+// TeacherCoursesRequest names the teacher whose courses are listed, a teacher
+// may leave it out to list their own
+type TeacherCoursesRequest struct {
+	TeacherID uint `json:"teacher_id"`
+}
+
+// TransferOwnershipRequest names the course to hand over and its new teacher
+type TransferOwnershipRequest struct {
+	CourseID  uint `json:"course_id"`
+	TeacherID uint `json:"teacher_id"`
+}
+
+// CourseTeacher is the public profile of the teacher giving a course
+type CourseTeacher struct {
+	ID       uint   `json:"ID"`
+	FullName string `json:"FullName"`
+	Username string `json:"username"`
+	Picture  string `json:"Picture"`
+}
+
+// CourseDetails is a course along with its teacher and category
+type CourseDetails struct {
+	models.Course
+	Teacher CourseTeacher `json:"teacher"`
+}
+
+// ManagedCourse is a course on the teacher dashboard along with its stats
+type ManagedCourse struct {
+	models.Course
+	EnrollmentCount int `json:"enrollment_count"`
+}
+
 func NewCourseController(db *sql.DB) *CourseController {
 	return &CourseController{db: db}
 }
@@ -56,9 +203,64 @@ func (h *CourseController) validateCourse(course *models.Course) error {
 	if course.CategoryID <= 0 {
 		return errors.New("valid category ID is required")
 	}
+	return validatePricing(course.Pricing)
+}
+
+// pricingPattern matches the prices coursePriceExpr can compare numerically
+var pricingPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// validatePricing checks that a course price is a non-negative number
+func validatePricing(pricing string) error {
+	if !pricingPattern.MatchString(pricing) {
+		return errors.New("pricing must be a non-negative number")
+	}
 	return nil
 }
 
+// parsePrice reads an optional non-negative price from the query string
+func parsePrice(c *gin.Context, key string) (*float64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	price, err := strconv.ParseFloat(raw, 64)
+	if err != nil || price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return nil, fmt.Errorf("%s must be a non-negative number", key)
+	}
+	return &price, nil
+}
+
+// checkSubCategory writes a 400 and returns false when the course names a
+// subcategory that does not exist or belongs to another category
+func (h *CourseController) checkSubCategory(ctx context.Context, c *gin.Context, course *models.Course) bool {
+	if course.SubCatID == nil {
+		return true
+	}
+
+	var categoryID sql.NullInt64
+	err := h.db.QueryRowContext(ctx, getSubCatCategoryQuery, *course.SubCatID).Scan(&categoryID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Subcategory not found"})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check subcategory"})
+		return false
+	}
+	if !categoryID.Valid || uint(categoryID.Int64) != course.CategoryID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Subcategory does not belong to the course category"})
+		return false
+	}
+	return true
+}
+
+// courseExists reports whether a course with the given ID exists
+func (h *CourseController) courseExists(ctx context.Context, id uint) (bool, error) {
+	var exists bool
+	err := h.db.QueryRowContext(ctx, courseExistsQuery, id).Scan(&exists)
+	return exists, err
+}
+
 // CreateCourse creates a new course
 func (h *CourseController) CreateCourse(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
@@ -74,17 +276,26 @@ func (h *CourseController) CreateCourse(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Course name is required"})
 		return
 	}
+
+	if err := validatePricing(course.Pricing); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !h.checkSubCategory(ctx, c, &course) {
+		return
+	}
  
 	var id uint
 	err := h.db.QueryRowContext(ctx, `
 		INSERT INTO courses 
-		(name, description, pricing, duration, image, language, level, teacher_id, category_id) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
-		RETURNING id`,
+		(name, description, pricing, duration, image, language, level, access_days, teacher_id, category_id, sub_cat_id) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) 
+		RETURNING id, created_at`,
 		course.Name, course.Description, course.Pricing,
 		course.Duration,course.Image, course.Language,  course.Level, 
-		course.TeacherID, course.CategoryID,
-	).Scan(&id)
+		course.AccessDays, course.TeacherID, course.CategoryID, course.SubCatID,
+	).Scan(&id, &course.CreatedAt)
  
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create course"})
@@ -92,56 +303,503 @@ func (h *CourseController) CreateCourse(c *gin.Context) {
 	}
  
 	course.ID = id
-	c.JSON(http.StatusCreated, course)
+	respond(c, http.StatusCreated, course)
  }
- func (h *CourseController) GetAllCourses(c *gin.Context) {
+
+// @Summary Get all courses
+// @Description Retrieve every course in the catalog
+// @Tags courses
+// @Produce json
+// @Param sort query string false "Sort order: newest (default), price_asc, price_desc or name"
+// @Success 200 {array} models.Course
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/all [get]
+func (h *CourseController) GetAllCourses(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	rows, err := h.db.QueryContext(ctx, getAllCoursesQuery)
+	order, err := parseCourseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getAllCoursesQuery+" ORDER BY "+order)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve courses"})
 		return
 	}
 	defer rows.Close()
 
-	var courses []models.Course
+	courses, err := scanCourses(rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process courses"})
+		return
+	}
+
+	respond(c, http.StatusOK, courses)
+}
+
+// @Summary Search courses
+// @Description Find courses whose name or description contains the keyword, case-insensitively
+// @Tags courses
+// @Produce json
+// @Param q query string true "Keyword, at least 2 characters"
+// @Success 200 {array} models.Course
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/search [get]
+func (h *CourseController) SearchCourses(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	q := strings.TrimSpace(c.Query("q"))
+	if utf8.RuneCountInString(q) < minSearchLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("q must be at least %d characters", minSearchLength)})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, searchCoursesQuery, q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search courses"})
+		return
+	}
+	defer rows.Close()
+
+	courses, err := scanCourses(rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process courses"})
+		return
+	}
+
+	respond(c, http.StatusOK, courses)
+}
+
+// @Summary Filter courses
+// @Description Retrieve a page of courses, optionally filtered by category, level, language and price range
+// @Tags courses
+// @Produce json
+// @Param category_id query int false "Only courses in this category"
+// @Param level query string false "Only courses of this level"
+// @Param language query string false "Only courses taught in this language"
+// @Param min_price query number false "Only courses priced at least this much"
+// @Param max_price query number false "Only courses priced at most this much"
+// @Param sort query string false "Sort order: newest (default), price_asc, price_desc or name"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of courses per page (max 100)"
+// @Success 200 {object} PagedResponse{data=[]models.Course}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/filter [get]
+func (h *CourseController) GetCoursesFiltered(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := parseCourseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var conditions []string
+	var args []interface{}
+	if raw := c.Query("category_id"); raw != "" {
+		categoryID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID format"})
+			return
+		}
+		args = append(args, categoryID)
+		conditions = append(conditions, fmt.Sprintf("category_id = $%d", len(args)))
+	}
+	if level := c.Query("level"); level != "" {
+		args = append(args, level)
+		conditions = append(conditions, fmt.Sprintf("level = $%d", len(args)))
+	}
+	if language := c.Query("language"); language != "" {
+		args = append(args, language)
+		conditions = append(conditions, fmt.Sprintf("language = $%d", len(args)))
+	}
+
+	minPrice, err := parsePrice(c, "min_price")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	maxPrice, err := parsePrice(c, "max_price")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if minPrice != nil && maxPrice != nil && *minPrice > *maxPrice {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_price cannot be greater than max_price"})
+		return
+	}
+	if minPrice != nil {
+		args = append(args, *minPrice)
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", coursePriceExpr, len(args)))
+	}
+	if maxPrice != nil {
+		args = append(args, *maxPrice)
+		conditions = append(conditions, fmt.Sprintf("%s <= $%d", coursePriceExpr, len(args)))
+	}
+
+	query := getAllCoursesQuery
+	countQuery := countCoursesQuery
+	if len(conditions) > 0 {
+		where := " AND " + strings.Join(conditions, " AND ")
+		query += where
+		countQuery += where
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count courses"})
+		return
+	}
+
+	args = append(args, page.PageSize, page.Offset())
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", order, len(args)-1, len(args))
+
+	rows, err := h.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve courses"})
+		return
+	}
+	defer rows.Close()
+
+	courses, err := scanCourses(rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process courses"})
+		return
+	}
+
+	respond(c, http.StatusOK, PagedResponse{Data: courses, Pagination: page.Meta(total)})
+}
+
+// @Summary List courses of a subcategory
+// @Description Retrieve a page of the courses filed under a subcategory
+// @Tags courses
+// @Produce json
+// @Param sub_cat_id query int true "Subcategory ID"
+// @Param sort query string false "Sort order: newest (default), price_asc, price_desc or name"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of courses per page (max 100)"
+// @Success 200 {object} PagedResponse{data=[]models.Course}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/bySubCategory [get]
+func (h *CourseController) GetCoursesBySubCategory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	subCatID, err := strconv.ParseUint(c.Query("sub_cat_id"), 10, 32)
+	if err != nil || subCatID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid subcategory ID format"})
+		return
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := parseCourseSort(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var categoryID sql.NullInt64
+	err = h.db.QueryRowContext(ctx, getSubCatCategoryQuery, subCatID).Scan(&categoryID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Subcategory not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check subcategory"})
+		return
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countSubCatCoursesQuery, subCatID).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count courses"})
+		return
+	}
+
+	query := getAllCoursesQuery + " AND sub_cat_id = $1 ORDER BY " + order + " LIMIT $2 OFFSET $3"
+	rows, err := h.db.QueryContext(ctx, query, subCatID, page.PageSize, page.Offset())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve courses"})
+		return
+	}
+	defer rows.Close()
+
+	courses, err := scanCourses(rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process courses"})
+		return
+	}
+
+	respond(c, http.StatusOK, PagedResponse{Data: courses, Pagination: page.Meta(total)})
+}
+
+// @Summary Get top-rated courses
+// @Description Retrieve the highest rated courses that have at least min_ratings ratings, best first
+// @Tags courses
+// @Produce json
+// @Param limit query int false "Number of courses to return (default 10, max 50)"
+// @Param min_ratings query int false "Minimum number of ratings a course needs (default 3)"
+// @Success 200 {array} models.Course
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/topRated [get]
+func (h *CourseController) GetTopRatedCourses(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultTopRatedLimit)))
+	if err != nil || limit < 1 || limit > maxTopRatedLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be an integer between 1 and %d", maxTopRatedLimit)})
+		return
+	}
+
+	minRatings, err := strconv.Atoi(c.DefaultQuery("min_ratings", strconv.Itoa(defaultTopRatedMinRatings)))
+	if err != nil || minRatings < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_ratings must be a non-negative integer"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getTopRatedCoursesQuery, minRatings, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve courses"})
+		return
+	}
+	defer rows.Close()
+
+	courses, err := scanCourses(rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process courses"})
+		return
+	}
+
+	respond(c, http.StatusOK, courses)
+}
+
+// @Summary List the courses a teacher manages
+// @Description Retrieve the authenticated teacher's courses, newest first, each with its enrollment count and rating
+// @Tags courses
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of courses per page (max 100)"
+// @Success 200 {object} PagedResponse{data=[]ManagedCourse}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/manage [post]
+func (h *CourseController) GetManagedCourses(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	teacherID, ok, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only teachers can manage courses"})
+		return
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countTeacherCoursesQuery, teacherID).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count courses"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getManagedCoursesQuery, teacherID, page.PageSize, page.Offset())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve courses"})
+		return
+	}
+	defer rows.Close()
+
+	courses := []ManagedCourse{}
+	for rows.Next() {
+		var mc ManagedCourse
+		course := &mc.Course
+		if err := rows.Scan(
+			&course.ID, &course.Name, &course.Description,
+			&course.Pricing, &course.Duration, &course.Image,
+			&course.Language, &course.Level, &course.AccessDays,
+			&course.TeacherID, &course.CategoryID, &course.SubCatID, &course.CreatedAt,
+			&course.AverageRating, &course.RatingsCount, &mc.EnrollmentCount,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process courses"})
+			return
+		}
+		course.AverageRating = roundRating(course.AverageRating)
+		courses = append(courses, mc)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing courses"})
+		return
+	}
+
+	respond(c, http.StatusOK, PagedResponse{Data: courses, Pagination: page.Meta(total)})
+}
+
+// @Summary List a teacher's courses
+// @Description Retrieve every course of a teacher, newest first, with its category and rating. Teachers may omit teacher_id to list their own courses
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param teacher body TeacherCoursesRequest false "Teacher ID"
+// @Success 200 {array} models.Course
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/byTeacher [post]
+func (h *CourseController) GetCoursesByTeacher(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req TeacherCoursesRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if req.TeacherID == 0 {
+		teacherID, ok, err := authenticatedTeacherID(ctx, h.db, c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "teacher_id is required"})
+			return
+		}
+		req.TeacherID = teacherID
+	}
+
+	var active bool
+	err := h.db.QueryRowContext(ctx, getTeacherActiveQuery, req.TeacherID).Scan(&active)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Teacher not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check teacher existence"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getCoursesByTeacherQuery, req.TeacherID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve courses"})
+		return
+	}
+	defer rows.Close()
+
+	courses := []models.Course{}
 	for rows.Next() {
 		var course models.Course
 		if err := rows.Scan(
 			&course.ID, &course.Name, &course.Description,
 			&course.Pricing, &course.Duration, &course.Image,
-			&course.Language, &course.Level, &course.TeacherID,
-			&course.CategoryID,
+			&course.Language, &course.Level, &course.AccessDays,
+			&course.TeacherID, &course.CategoryID, &course.SubCatID, &course.CreatedAt,
+			&course.AverageRating, &course.RatingsCount, &course.Category.Name,
 		); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process courses"})
 			return
 		}
+		course.AverageRating = roundRating(course.AverageRating)
+		course.Category.ID = course.CategoryID
 		courses = append(courses, course)
 	}
 
-	if err := rows.Err(); err != nil {
+	if err = rows.Err(); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing courses"})
 		return
 	}
 
-	c.JSON(http.StatusOK, courses)
+	respond(c, http.StatusOK, courses)
 }
 
-// UpdateCourse updates a course
-func (h *CourseController) UpdateCourse(c *gin.Context) {
+// scanCourses reads every course row, returning an empty slice rather than nil
+// so listings always serialize as a JSON array
+func scanCourses(rows *sql.Rows) ([]models.Course, error) {
+	courses := []models.Course{}
+	for rows.Next() {
+		var course models.Course
+		if err := rows.Scan(
+			&course.ID, &course.Name, &course.Description,
+			&course.Pricing, &course.Duration, &course.Image,
+			&course.Language, &course.Level, &course.AccessDays,
+			&course.TeacherID, &course.CategoryID, &course.SubCatID, &course.CreatedAt,
+			&course.AverageRating, &course.RatingsCount,
+		); err != nil {
+			return nil, err
+		}
+		course.AverageRating = roundRating(course.AverageRating)
+		courses = append(courses, course)
+	}
+	return courses, rows.Err()
+}
+
+// @Summary Get course by ID
+// @Description Retrieve a single course with its teacher and category
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param course body CourseIDRequest true "Course ID"
+// @Success 200 {object} CourseDetails
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/get [post]
+func (h *CourseController) GetCourse(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	var req CourseIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
 		return
 	}
 
-	// Check if course exists
-	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", id).Scan(&exists)
+	exists, err := h.courseExists(ctx, req.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check course existence"})
 		return
@@ -151,21 +809,101 @@ func (h *CourseController) UpdateCourse(c *gin.Context) {
 		return
 	}
 
+	details, err := h.courseDetails(ctx, req.ID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve course"})
+		return
+	}
+
+	respond(c, http.StatusOK, details)
+}
+
+// courseDetails loads a course with its teacher and category
+func (h *CourseController) courseDetails(ctx context.Context, id uint) (CourseDetails, error) {
+	var details CourseDetails
+	course := &details.Course
+	err := h.db.QueryRowContext(ctx, getCourseDetailsQuery, id).Scan(
+		&course.ID, &course.Name, &course.Description,
+		&course.Pricing, &course.Duration, &course.Image,
+		&course.Language, &course.Level, &course.AccessDays,
+		&course.TeacherID, &course.CategoryID, &course.SubCatID, &course.CreatedAt,
+		&course.AverageRating, &course.RatingsCount, &course.Category.Name,
+		&details.Teacher.FullName, &details.Teacher.Username, &details.Teacher.Picture,
+	)
+	if err != nil {
+		return details, err
+	}
+
+	course.AverageRating = roundRating(course.AverageRating)
+	course.Category.ID = course.CategoryID
+	details.Teacher.ID = course.TeacherID
+	return details, nil
+}
+
+// UpdateCourse updates a course
+func (h *CourseController) UpdateCourse(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
 	var course models.Course
 	if err := c.ShouldBindJSON(&course); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if course.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := course.ID
+
+	// Check if course exists
+	exists, err := h.courseExists(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check course existence"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
 
 	if err := h.validateCourse(&course); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if !h.checkSubCategory(ctx, c, &course) {
+		return
+	}
+
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Course
+	if wantsDiff(c) {
+		err = h.db.QueryRowContext(ctx, getCourseQuery, id).Scan(
+			&before.ID, &before.Name, &before.Description,
+			&before.Pricing, &before.Duration, &before.Image,
+			&before.Language, &before.Level, &before.AccessDays,
+			&before.TeacherID, &before.CategoryID, &before.SubCatID, &before.CreatedAt,
+			&before.AverageRating, &before.RatingsCount,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve course"})
+			return
+		}
+	}
+
 	result, err := h.db.ExecContext(ctx, updateCourseQuery,
 		course.Name, course.Description, course.Pricing,
 		course.Duration, course.Image, course.Language,
-		course.Level, course.TeacherID, course.CategoryID, id,
+		course.Level, course.AccessDays, course.TeacherID, course.CategoryID, course.SubCatID, id,
 	)
 
 	if err != nil {
@@ -183,11 +921,11 @@ func (h *CourseController) UpdateCourse(c *gin.Context) {
 		return
 	}
 
-	course.ID = uint(id)
-	c.JSON(http.StatusOK, course)
+	respondUpdated(c, before, course)
 }
 
-// DeleteCourse deletes a course
+// DeleteCourse soft deletes a course, it disappears from every listing but
+// keeps its content and image so RestoreCourse can bring it back
 func (h *CourseController) DeleteCourse(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
@@ -214,5 +952,124 @@ func (h *CourseController) DeleteCourse(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Course deleted successfully"})
-}
\ No newline at end of file
+	respond(c, http.StatusOK, gin.H{"message": "Course deleted successfully"})
+}
+
+// @Summary Restore a deleted course
+// @Description Bring back a soft deleted course with all its content
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param course body CourseIDRequest true "Course ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/restore [post]
+func (h *CourseController) RestoreCourse(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req CourseIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+
+	result, err := h.db.ExecContext(ctx, restoreCourseQuery, req.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore course"})
+		return
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to confirm restore"})
+		return
+	}
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Deleted course not found"})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{"message": "Course restored successfully"})
+}
+
+// @Summary Transfer course ownership
+// @Description Hand a course over to another active teacher. Admin only, the transfer is recorded in the audit log.
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param request body TransferOwnershipRequest true "Course and new teacher"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/transferOwnership [post]
+func (h *CourseController) TransferOwnership(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+	if req.TeacherID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid teacher ID is required"})
+		return
+	}
+
+	// Verify the new owner exists and can take courses
+	var active bool
+	err := h.db.QueryRowContext(ctx, getTeacherActiveQuery, req.TeacherID).Scan(&active)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Teacher not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+		return
+	}
+	if !active {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Teacher is not active"})
+		return
+	}
+
+	var previousTeacherID sql.NullInt64
+	err = withTx(ctx, h.db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, lockCourseTeacherQuery, req.CourseID).Scan(&previousTeacherID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, updateCourseTeacherQuery, req.TeacherID, req.CourseID); err != nil {
+			return err
+		}
+		details := fmt.Sprintf("course %d: teacher %d -> %d", req.CourseID, previousTeacherID.Int64, req.TeacherID)
+		return recordAudit(ctx, tx, c, "course.transfer_ownership", details)
+	})
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer course"})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"course_id":           req.CourseID,
+		"previous_teacher_id": previousTeacherID.Int64,
+		"teacher_id":          req.TeacherID,
+	})
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// courseDetailsRow is course 7 as getCourseDetailsQuery answers it
+func courseDetailsRow() []interface{} {
+	return row(7, "Go", "Learn Go", "49.99", "10h", "go.png", "en", "beginner", 0,
+		3, 2, nil, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 4.333, 3,
+		"Programming", "Bob Teacher", "bob", "bob.png")
+}
+
+func TestGetCourseFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsQuery, 7).returns(row(true))
+	f.expect(getCourseDetailsQuery, 7).returns(courseDetailsRow())
+
+	w := serve(t, NewCourseController(db).GetCourse, testRequest{body: `{"id": 7}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var got CourseDetails
+	decodeBody(t, w, &got)
+	if got.ID != 7 || got.Name != "Go" || got.Category.Name != "Programming" {
+		t.Errorf("course = %+v, want course 7 Go in Programming", got.Course)
+	}
+	if got.Teacher.ID != 3 || got.Teacher.Username != "bob" {
+		t.Errorf("teacher = %+v, want bob with ID 3", got.Teacher)
+	}
+	if got.AverageRating != 4.33 {
+		t.Errorf("average rating = %v, want 4.33", got.AverageRating)
+	}
+}
+
+func TestGetCourseNotFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsQuery, 7).returns(row(false))
+
+	w := serve(t, NewCourseController(db).GetCourse, testRequest{body: `{"id": 7}`})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestGetCourseRequiresID(t *testing.T) {
+	for _, body := range []string{``, `{}`, `{"id": "seven"}`} {
+		db, _ := newFakeDB(t)
+
+		w := serve(t, NewCourseController(db).GetCourse, testRequest{body: body})
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	at := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	setClock(t, at)
+
+	db, f := newFakeDB(t)
+	f.expect(getTeacherActiveQuery, 4).returns(row(true))
+	f.expect(lockCourseTeacherQuery, 7).returns(row(3))
+	f.expect(updateCourseTeacherQuery, 4, 7).affects(1)
+	f.expect(createAuditLogQuery, "root", "course.transfer_ownership", "course 7: teacher 3 -> 4", at).affects(1)
+
+	w := serve(t, NewCourseController(db).TransferOwnership, testRequest{
+		body:   `{"course_id": 7, "teacher_id": 4}`,
+		claims: adminClaims("root"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var got map[string]interface{}
+	decodeBody(t, w, &got)
+	if got["previous_teacher_id"] != float64(3) || got["teacher_id"] != float64(4) {
+		t.Errorf("response = %v, want teacher 3 -> 4", got)
+	}
+	if f.commits != 1 {
+		t.Errorf("commits = %d, want 1", f.commits)
+	}
+}
+
+func TestTransferOwnershipToMissingTeacher(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherActiveQuery, 4)
+
+	w := serve(t, NewCourseController(db).TransferOwnership, testRequest{
+		body:   `{"course_id": 7, "teacher_id": 4}`,
+		claims: adminClaims("root"),
+	})
+	expectStatus(t, w, http.StatusNotFound)
+	if f.commits != 0 {
+		t.Errorf("commits = %d, want no transfer", f.commits)
+	}
+}
+
+func TestTransferOwnershipToInactiveTeacher(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherActiveQuery, 4).returns(row(false))
+
+	w := serve(t, NewCourseController(db).TransferOwnership, testRequest{
+		body:   `{"course_id": 7, "teacher_id": 4}`,
+		claims: adminClaims("root"),
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestTransferOwnershipOfMissingCourse(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherActiveQuery, 4).returns(row(true))
+	f.expect(lockCourseTeacherQuery, 7)
+
+	w := serve(t, NewCourseController(db).TransferOwnership, testRequest{
+		body:   `{"course_id": 7, "teacher_id": 4}`,
+		claims: adminClaims("root"),
+	})
+	expectStatus(t, w, http.StatusNotFound)
+	if f.rollbacks != 1 {
+		t.Errorf("rollbacks = %d, want 1", f.rollbacks)
+	}
+}
+
+func TestGetManagedCoursesStats(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(countTeacherCoursesQuery, 3).returns(row(2))
+	// The query is scoped to the caller's teacher ID, so only their courses come back
+	f.expect(getManagedCoursesQuery, 3, defaultPageSize, 0).returns(
+		row(8, "Rust", "Learn Rust", "59", "12h", "rust.png", "en", "advanced", 0,
+			3, 2, nil, created.AddDate(0, 1, 0), 4.666666, 3, 12),
+		row(7, "Go", "Learn Go", "49.99", "10h", "go.png", "en", "beginner", 0,
+			3, 2, nil, created, 0, 0, 0),
+	)
+
+	w := serve(t, NewCourseController(db).GetManagedCourses, testRequest{claims: teacherClaims("bob")})
+	expectStatus(t, w, http.StatusOK)
+
+	var page struct {
+		Data []struct {
+			ID              uint    `json:"ID"`
+			TeacherID       uint    `json:"teacher_id"`
+			AverageRating   float64 `json:"average_rating"`
+			TotalRatings    uint    `json:"total_ratings"`
+			EnrollmentCount int     `json:"enrollment_count"`
+		} `json:"data"`
+		Pagination PageMeta `json:"pagination"`
+	}
+	decodeBody(t, w, &page)
+	if len(page.Data) != 2 || page.Pagination.Total != 2 {
+		t.Fatalf("page = %+v, want 2 courses", page)
+	}
+	rust, golang := page.Data[0], page.Data[1]
+	if rust.ID != 8 || rust.EnrollmentCount != 12 || rust.AverageRating != 4.67 || rust.TotalRatings != 3 {
+		t.Errorf("rust = %+v, want 12 enrollments and 4.67 over 3 ratings", rust)
+	}
+	if golang.ID != 7 || golang.EnrollmentCount != 0 || golang.AverageRating != 0 || golang.TotalRatings != 0 {
+		t.Errorf("go = %+v, want no enrollments or ratings", golang)
+	}
+	for _, course := range page.Data {
+		if course.TeacherID != 3 {
+			t.Errorf("course %d belongs to teacher %d, want only teacher 3's courses", course.ID, course.TeacherID)
+		}
+	}
+}
+
+func TestGetManagedCoursesRequiresTeacher(t *testing.T) {
+	db, _ := newFakeDB(t)
+	w := serve(t, NewCourseController(db).GetManagedCourses, testRequest{claims: studentClaims("alice")})
+	expectStatus(t, w, http.StatusForbidden)
+}
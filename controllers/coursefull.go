@@ -0,0 +1,355 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cuddest/dz-skills/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	getCourseExamSummaryQuery = `
+		SELECT e.id, e.description, e.archived, COUNT(eq.id) 
+		FROM exams e 
+		LEFT JOIN exam_quizzes eq ON eq.exam_id = e.id 
+		WHERE e.course_id = $1 
+		GROUP BY e.id`
+
+	getRatingDistributionQuery = `
+		SELECT ROUND(rating)::integer AS stars, COUNT(*) 
+		FROM cratings WHERE course_id = $1 
+		GROUP BY stars ORDER BY stars`
+
+	// Only the latest questions are included, older ones are paged through /questions/all
+	getCourseQAQuery = `
+		SELECT q.id, q.student_id, q.question, 
+			COALESCE(json_agg(json_build_object(
+				'ID', a.id,
+				'Answer', a.answer,
+				'is_instructor', a.is_instructor
+			) ORDER BY a.id) FILTER (WHERE a.id IS NOT NULL), '[]') 
+		FROM questions q 
+		LEFT JOIN answers a ON a.question_id = q.id 
+		WHERE q.course_id = $1 
+		GROUP BY q.id 
+		ORDER BY q.id DESC 
+		LIMIT $2 OFFSET $3`
+)
+
+// courseFullQuestionLimit is how many of the latest questions GetCourseFull includes
+const courseFullQuestionLimit = 50
+
+// CourseExamSummary describes the exam of a course. Its quizzes, with their
+// answers, are only included for the course teacher.
+type CourseExamSummary struct {
+	ID            uint               `json:"ID"`
+	Description   string             `json:"Description"`
+	Archived      bool               `json:"archived"`
+	QuestionCount int                `json:"question_count"`
+	Quizzes       []models.ExamQuizz `json:"quizzes,omitempty"`
+}
+
+// RatingBucket is how many ratings of a course round to a number of stars
+type RatingBucket struct {
+	Stars int `json:"stars"`
+	Count int `json:"count"`
+}
+
+// CourseRatings sums up the ratings of a course
+type CourseRatings struct {
+	AverageRating float64        `json:"average_rating"`
+	TotalRatings  uint           `json:"total_ratings"`
+	Distribution  []RatingBucket `json:"distribution"`
+}
+
+// CourseAnswer is an answer shown under a question of a course
+type CourseAnswer struct {
+	ID           uint   `json:"ID"`
+	Answer       string `json:"Answer"`
+	IsInstructor bool   `json:"is_instructor"`
+}
+
+// CourseQuestion is a question of a course along with its answers
+type CourseQuestion struct {
+	ID        uint           `json:"ID"`
+	StudentID uint           `json:"student_id"`
+	Question  string         `json:"question"`
+	Answers   []CourseAnswer `json:"answers"`
+}
+
+// CourseFullQuizz is a course quiz, its answer is only included for the course teacher
+type CourseFullQuizz struct {
+	PracticeQuizz
+	Answer string `json:"Answer,omitempty"`
+}
+
+// CourseFull is everything a course page shows. A section that failed to
+// load is left empty and explained in Errors, keyed by section name.
+type CourseFull struct {
+	Course    CourseDetails      `json:"course"`
+	Videos    []models.Video     `json:"videos"`
+	Articles  []models.Article   `json:"articles"`
+	Quizzes   []CourseFullQuizz  `json:"quizzes"`
+	Exam      *CourseExamSummary `json:"exam"`
+	Ratings   CourseRatings      `json:"ratings"`
+	Questions []CourseQuestion   `json:"questions"`
+	Errors    map[string]string  `json:"errors,omitempty"`
+}
+
+// @Summary Get a course with all its content
+// @Description Retrieve a course along with its videos, articles, quizzes, exam, ratings and latest questions with their answers, in one payload. Quiz answers and exam quizzes are only included for the course teacher. A section that fails to load is left empty and reported in errors.
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param course body CourseIDRequest true "Course ID"
+// @Success 200 {object} CourseFull
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/full [post]
+func (h *CourseController) GetCourseFull(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req CourseIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+
+	details, err := h.courseDetails(ctx, req.ID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve course"})
+		return
+	}
+
+	// Students lose access to course material once their enrollment expires
+	if denyExpiredAccess(ctx, h.db, c, req.ID) {
+		return
+	}
+
+	teacherID, isTeacher, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify caller"})
+		return
+	}
+	isOwner := isTeacher && teacherID == details.Course.TeacherID
+
+	full := CourseFull{
+		Course: details,
+		Ratings: CourseRatings{
+			AverageRating: details.Course.AverageRating,
+			TotalRatings:  details.Course.RatingsCount,
+		},
+	}
+
+	// Every section writes only its own fields, errors are collected under mu
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	load := func(section string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				if full.Errors == nil {
+					full.Errors = make(map[string]string)
+				}
+				full.Errors[section] = "Failed to load " + section
+			}
+		}()
+	}
+
+	load("videos", func() (err error) {
+		full.Videos, err = h.courseVideos(ctx, req.ID)
+		return err
+	})
+	load("articles", func() (err error) {
+		full.Articles, err = h.courseArticles(ctx, req.ID)
+		return err
+	})
+	load("quizzes", func() (err error) {
+		full.Quizzes, err = h.courseQuizzes(ctx, req.ID, isOwner)
+		return err
+	})
+	load("exam", func() (err error) {
+		full.Exam, err = h.courseExam(ctx, req.ID, isOwner)
+		return err
+	})
+	load("ratings", func() (err error) {
+		full.Ratings.Distribution, err = h.courseRatingDistribution(ctx, req.ID)
+		return err
+	})
+	load("questions", func() (err error) {
+		full.Questions, err = h.courseQuestions(ctx, req.ID)
+		return err
+	})
+	wg.Wait()
+
+	respond(c, http.StatusOK, full)
+}
+
+// courseVideos lists the videos of a course
+func (h *CourseController) courseVideos(ctx context.Context, courseID uint) ([]models.Video, error) {
+	rows, err := h.db.QueryContext(ctx, getVideosByCourseQuery, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	videos := []models.Video{}
+	for rows.Next() {
+		var video models.Video
+		if err := rows.Scan(&video.ID, &video.Title, &video.Link, &video.CourseID); err != nil {
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+	return videos, rows.Err()
+}
+
+// courseArticles lists the articles of a course
+func (h *CourseController) courseArticles(ctx context.Context, courseID uint) ([]models.Article, error) {
+	rows, err := h.db.QueryContext(ctx, getArticlesByCourseQuery, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	articles := []models.Article{}
+	for rows.Next() {
+		var article models.Article
+		if err := rows.Scan(&article.ID, &article.Title, &article.Link, &article.Description, &article.CourseID, &article.Position); err != nil {
+			return nil, err
+		}
+		articles = append(articles, article)
+	}
+	return articles, rows.Err()
+}
+
+// courseQuizzes lists the quizzes of a course, with their answers only when
+// withAnswers is set
+func (h *CourseController) courseQuizzes(ctx context.Context, courseID uint, withAnswers bool) ([]CourseFullQuizz, error) {
+	rows, err := h.db.QueryContext(ctx, getQuizzesByCourseQuery+" ORDER BY id", courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quizzes := []CourseFullQuizz{}
+	for rows.Next() {
+		var quizz CourseFullQuizz
+		if err := rows.Scan(
+			&quizz.ID, &quizz.Question, &quizz.Option1, &quizz.Option2,
+			&quizz.Option3, &quizz.Option4, &quizz.Answer, &quizz.CourseID,
+		); err != nil {
+			return nil, err
+		}
+		if !withAnswers {
+			quizz.Answer = ""
+		}
+		quizzes = append(quizzes, quizz)
+	}
+	return quizzes, rows.Err()
+}
+
+// courseExam summarizes the exam of a course, nil when it has none. The
+// quizzes are only loaded when withQuizzes is set.
+func (h *CourseController) courseExam(ctx context.Context, courseID uint, withQuizzes bool) (*CourseExamSummary, error) {
+	var exam CourseExamSummary
+	err := h.db.QueryRowContext(ctx, getCourseExamSummaryQuery, courseID).Scan(
+		&exam.ID, &exam.Description, &exam.Archived, &exam.QuestionCount,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !withQuizzes {
+		return &exam, nil
+	}
+
+	rows, err := h.db.QueryContext(ctx, getExamQuizzesQuery, exam.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exam.Quizzes = []models.ExamQuizz{}
+	for rows.Next() {
+		var quizz models.ExamQuizz
+		if err := rows.Scan(
+			&quizz.ID, &quizz.Question, &quizz.Option1, &quizz.Option2,
+			&quizz.Option3, &quizz.Option4, &quizz.Answer, &quizz.ExamID,
+		); err != nil {
+			return nil, err
+		}
+		exam.Quizzes = append(exam.Quizzes, quizz)
+	}
+	return &exam, rows.Err()
+}
+
+// courseRatingDistribution counts the ratings of a course per number of stars
+func (h *CourseController) courseRatingDistribution(ctx context.Context, courseID uint) ([]RatingBucket, error) {
+	rows, err := h.db.QueryContext(ctx, getRatingDistributionQuery, courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []RatingBucket{}
+	for rows.Next() {
+		var bucket RatingBucket
+		if err := rows.Scan(&bucket.Stars, &bucket.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, rows.Err()
+}
+
+// courseQuestions lists the latest questions of a course with their answers
+func (h *CourseController) courseQuestions(ctx context.Context, courseID uint) ([]CourseQuestion, error) {
+	return loadCourseQuestions(ctx, h.db, courseID, courseFullQuestionLimit, 0)
+}
+
+// loadCourseQuestions lists a page of a course's questions, newest first, each
+// with its answers
+func loadCourseQuestions(ctx context.Context, db *sql.DB, courseID uint, limit, offset int) ([]CourseQuestion, error) {
+	rows, err := db.QueryContext(ctx, getCourseQAQuery, courseID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	questions := []CourseQuestion{}
+	for rows.Next() {
+		var question CourseQuestion
+		var answersJSON []byte
+		if err := rows.Scan(&question.ID, &question.StudentID, &question.Question, &answersJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(answersJSON, &question.Answers); err != nil {
+			return nil, err
+		}
+		questions = append(questions, question)
+	}
+	return questions, rows.Err()
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// expectCourseFull scripts every section of course 7, the exam quizzes are
+// only scripted for the course teacher
+func expectCourseFull(f *fakeDB, owner bool) {
+	f.expect(getVideosByCourseQuery, 7).returns(row(1, "Intro", "intro.mp4", 7))
+	f.expect(getArticlesByCourseQuery, 7).returns(row(2, "Setup", "setup.html", "Install Go", 7, 1))
+	f.expect(getQuizzesByCourseQuery, 7).returns(row(3, "2+2?", "3", "4", "5", "6", "4", 7))
+	f.expect(getCourseExamSummaryQuery, 7).returns(row(4, "Final", false, 1))
+	if owner {
+		f.expect(getExamQuizzesQuery, 4).returns(row(5, "3+3?", "5", "6", "7", "8", 2, 4))
+	}
+	f.expect(getRatingDistributionQuery, 7).returns(row(4, 2), row(5, 1))
+	f.expect(getCourseQAQuery, 7, courseFullQuestionLimit, 0).returns(
+		row(6, 5, "Which version?", `[{"ID": 8, "Answer": "1.21", "is_instructor": true}]`),
+	)
+}
+
+func TestGetCourseFullForStudentStripsAnswers(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getCourseDetailsQuery, 7).returns(courseDetailsRow())
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+	expectCourseFull(f, false)
+
+	w := serve(t, NewCourseController(db).GetCourseFull, testRequest{body: `{"id": 7}`, claims: studentClaims("alice")})
+	expectStatus(t, w, http.StatusOK)
+
+	got := responseKeys(t, w.Body.Bytes())
+	for _, section := range []string{"course", "videos", "articles", "quizzes", "exam", "ratings", "questions"} {
+		if got[section] == nil {
+			t.Errorf("section %s is missing from %v", section, got)
+		}
+	}
+	if _, ok := got["errors"]; ok {
+		t.Errorf("errors = %v, want none", got["errors"])
+	}
+
+	var full CourseFull
+	decodeBody(t, w, &full)
+	if len(full.Quizzes) != 1 || full.Quizzes[0].Answer != "" {
+		t.Errorf("quizzes = %+v, want the answer stripped", full.Quizzes)
+	}
+	if full.Exam == nil || full.Exam.QuestionCount != 1 || full.Exam.Quizzes != nil {
+		t.Errorf("exam = %+v, want its metadata without quizzes", full.Exam)
+	}
+	if len(full.Ratings.Distribution) != 2 || full.Ratings.TotalRatings != 3 {
+		t.Errorf("ratings = %+v, want 3 ratings in 2 buckets", full.Ratings)
+	}
+	if len(full.Questions) != 1 || len(full.Questions[0].Answers) != 1 || !full.Questions[0].Answers[0].IsInstructor {
+		t.Errorf("questions = %+v, want one question with the instructor's answer", full.Questions)
+	}
+}
+
+func TestGetCourseFullForTeacherIncludesAnswers(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getCourseDetailsQuery, 7).returns(courseDetailsRow())
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	expectCourseFull(f, true)
+
+	w := serve(t, NewCourseController(db).GetCourseFull, testRequest{body: `{"id": 7}`, claims: teacherClaims("bob")})
+	expectStatus(t, w, http.StatusOK)
+
+	var full CourseFull
+	decodeBody(t, w, &full)
+	if len(full.Quizzes) != 1 || full.Quizzes[0].Answer != "4" {
+		t.Errorf("quizzes = %+v, want the teacher to see answer 4", full.Quizzes)
+	}
+	if full.Exam == nil || len(full.Exam.Quizzes) != 1 || full.Exam.Quizzes[0].Answer != 2 {
+		t.Errorf("exam = %+v, want its quizzes with answers", full.Exam)
+	}
+}
+
+func TestGetCourseFullReportsFailedSection(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getCourseDetailsQuery, 7).returns(courseDetailsRow())
+	f.expect(getVideosByCourseQuery, 7).fails(errors.New("connection reset"))
+	f.expect(getArticlesByCourseQuery, 7).returns(row(2, "Setup", "setup.html", "Install Go", 7, 1))
+	f.expect(getQuizzesByCourseQuery, 7)
+	f.expect(getCourseExamSummaryQuery, 7)
+	f.expect(getRatingDistributionQuery, 7)
+	f.expect(getCourseQAQuery, 7, courseFullQuestionLimit, 0)
+
+	w := serve(t, NewCourseController(db).GetCourseFull, testRequest{body: `{"id": 7}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var full CourseFull
+	decodeBody(t, w, &full)
+	if len(full.Errors) != 1 || !strings.Contains(full.Errors["videos"], "videos") {
+		t.Errorf("errors = %v, want only videos reported", full.Errors)
+	}
+	if full.Videos != nil {
+		t.Errorf("videos = %+v, want the failed section left empty", full.Videos)
+	}
+	if len(full.Articles) != 1 || full.Course.ID != 7 {
+		t.Errorf("payload = %+v, want the sections that loaded", full)
+	}
+	if full.Exam != nil {
+		t.Errorf("exam = %+v, want none for a course without one", full.Exam)
+	}
+}
+
+func TestGetCourseFullNotFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getCourseDetailsQuery, 7)
+
+	w := serve(t, NewCourseController(db).GetCourseFull, testRequest{body: `{"id": 7}`})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestGetCourseFullAfterExpiry(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	setClock(t, expiresAt.Add(time.Minute))
+
+	db, f := newFakeDB(t)
+	f.expect(getCourseDetailsQuery, 7).returns(courseDetailsRow())
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(expiresAt))
+
+	w := serve(t, NewCourseController(db).GetCourseFull, testRequest{body: `{"id": 7}`, claims: studentClaims("alice")})
+	expectStatus(t, w, http.StatusForbidden)
+}
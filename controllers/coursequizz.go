@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cuddest/dz-skills/models"
@@ -37,12 +39,84 @@ const (
 
 	deleteQuizzQuery = `
 		DELETE FROM course_quizzes WHERE id = $1`
+
+	deleteQuizzesBulkQuery = `
+		DELETE FROM course_quizzes WHERE id = ANY($1)`
+
+	getPracticeQuizzesQuery = `
+		SELECT id, question, option1, option2, option3, option4, course_id 
+		FROM course_quizzes WHERE course_id = $1 
+		ORDER BY random() 
+		LIMIT $2`
+
+	getQuizzAnswersQuery = `
+		SELECT id, answer 
+		FROM course_quizzes WHERE course_id = $1 AND id = ANY($2)`
+
+	getQuizzesNotOwnedQuery = `
+		SELECT q.id 
+		FROM course_quizzes q 
+		LEFT JOIN courses c ON c.id = q.course_id 
+		WHERE q.id = ANY($1) AND c.teacher_id IS DISTINCT FROM $2`
 )
 
 type CourseQuizzController struct {
 	db *sql.DB
 }
 
+const (
+	defaultPracticeCount = 10
+	maxPracticeCount     = 50
+)
+
+// CourseQuizzesRequest selects the course whose quizzes to list
+type CourseQuizzesRequest struct {
+	CourseID uint `json:"course_id"`
+}
+
+// PracticeRequest asks for Count random quizzes of a course
+type PracticeRequest struct {
+	CourseID uint `json:"course_id"`
+	Count    int  `json:"count"`
+}
+
+// PracticeQuizz is a course quiz with its answer left out
+type PracticeQuizz struct {
+	ID       uint   `json:"ID"`
+	Question string `json:"Question"`
+	Option1  string `json:"Option1"`
+	Option2  string `json:"Option2"`
+	Option3  string `json:"Option3"`
+	Option4  string `json:"Option4"`
+	CourseID uint   `json:"course_id"`
+}
+
+// PracticeAnswer is a student's answer to a practice quiz
+type PracticeAnswer struct {
+	QuizzID uint   `json:"quizz_id"`
+	Answer  string `json:"answer"`
+}
+
+// PracticeSubmission holds the answers to grade for a course
+type PracticeSubmission struct {
+	CourseID uint             `json:"course_id"`
+	Answers  []PracticeAnswer `json:"answers"`
+}
+
+// PracticeAnswerResult tells whether one practice answer was right
+type PracticeAnswerResult struct {
+	QuizzID       uint   `json:"quizz_id"`
+	Correct       bool   `json:"correct"`
+	CorrectAnswer string `json:"correct_answer"`
+}
+
+// PracticeResult is the score of a practice submission
+type PracticeResult struct {
+	Correct int                    `json:"correct"`
+	Total   int                    `json:"total"`
+	Results []PracticeAnswerResult `json:"results"`
+}
+
 func NewCourseQuizzController(db *sql.DB) *CourseQuizzController {
 	return &CourseQuizzController{db: db}
 }
@@ -93,7 +167,7 @@ func (h *CourseQuizzController) CreateQuizz(c *gin.Context) {
 
 	// Verify course exists
 	var exists bool
-	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", quizz.CourseID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", quizz.CourseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -121,7 +195,7 @@ func (h *CourseQuizzController) CreateQuizz(c *gin.Context) {
 // @Tags quizzes
 // @Accept json
 // @Produce json
-// @Param id path int true "Quiz ID"
+// @Param request body IDRequest true "Quiz ID"
 // @Success 200 {object} models.CourseQuizz
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -132,14 +206,13 @@ func (h *CourseQuizzController) GetQuizz(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var quizz models.CourseQuizz
-	err = h.db.QueryRowContext(ctx, getQuizzQuery, id).Scan(
+	err := h.db.QueryRowContext(ctx, getQuizzQuery, id).Scan(
 		&quizz.ID, &quizz.Question, &quizz.Option1,
 		&quizz.Option2, &quizz.Option3, &quizz.Option4,
 		&quizz.Answer, &quizz.CourseID,
@@ -206,9 +279,10 @@ func (h *CourseQuizzController) GetAllQuizzes(c *gin.Context) {
 // @Tags quizzes
 // @Accept json
 // @Produce json
-// @Param courseId path int true "Course ID"
+// @Param request body CourseQuizzesRequest true "Course ID"
 // @Success 200 {array} models.CourseQuizz
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /coursequizzs/GetQuizzesByCourse [post]
@@ -217,15 +291,25 @@ func (h *CourseQuizzController) GetQuizzesByCourse(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	courseID, err := strconv.Atoi(c.Param("courseId"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
+	var req CourseQuizzesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+	courseID := req.CourseID
+
+	// Students lose access to course material once their enrollment expires
+	if denyExpiredAccess(ctx, h.db, c, courseID) {
 		return
 	}
 
 	// Verify course exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", courseID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", courseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -269,7 +353,6 @@ func (h *CourseQuizzController) GetQuizzesByCourse(c *gin.Context) {
 // @Tags quizzes
 // @Accept json
 // @Produce json
-// @Param id path int true "Quiz ID"
 // @Param quiz body models.CourseQuizz true "Quiz object"
 // @Success 200 {object} models.CourseQuizz
 // @Failure 400 {object} map[string]interface{}
@@ -281,17 +364,16 @@ func (h *CourseQuizzController) UpdateQuizz(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var quizz models.CourseQuizz
 	if err := c.ShouldBindJSON(&quizz); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if quizz.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := quizz.ID
 
 	if err := h.validateQuizz(&quizz); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -300,7 +382,7 @@ func (h *CourseQuizzController) UpdateQuizz(c *gin.Context) {
 
 	// Verify course exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", quizz.CourseID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", quizz.CourseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -310,6 +392,24 @@ func (h *CourseQuizzController) UpdateQuizz(c *gin.Context) {
 		return
 	}
 
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.CourseQuizz
+	if wantsDiff(c) {
+		err = h.db.QueryRowContext(ctx, getQuizzQuery, id).Scan(
+			&before.ID, &before.Question, &before.Option1,
+			&before.Option2, &before.Option3, &before.Option4,
+			&before.Answer, &before.CourseID,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Quiz not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve quiz"})
+			return
+		}
+	}
+
 	result, err := h.db.ExecContext(ctx, updateQuizzQuery,
 		quizz.Question, quizz.Option1, quizz.Option2,
 		quizz.Option3, quizz.Option4, quizz.Answer,
@@ -330,8 +430,7 @@ func (h *CourseQuizzController) UpdateQuizz(c *gin.Context) {
 		return
 	}
 
-	quizz.ID = uint(id)
-	c.JSON(http.StatusOK, quizz)
+	respondUpdated(c, before, quizz)
 }
 
 // @Summary Delete quiz
@@ -374,3 +473,200 @@ func (h *CourseQuizzController) DeleteQuizz(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Quiz deleted successfully"})
 }
+
+// DeleteQuizzesBulk handles the deletion of several quizzes at once
+// @Summary Delete quizzes in bulk
+// @Description Delete several quizzes of the authenticated teacher's courses in one transaction. If any ID belongs to another teacher's course nothing is deleted. The number of deleted quizzes is returned so IDs that did not exist can be detected.
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Param request body IDsRequest true "Quiz IDs"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /coursequizzs/bulk [delete]
+func (h *CourseQuizzController) DeleteQuizzesBulk(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	ids, ok := bindIDs(c)
+	if !ok {
+		return
+	}
+
+	teacherID, ok, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only teachers can delete quizzes in bulk"})
+		return
+	}
+
+	deleted, notOwned, err := deleteOwned(ctx, h.db, ids, teacherID, getQuizzesNotOwnedQuery, deleteQuizzesBulkQuery)
+	respondBulkDelete(c, len(ids), deleted, notOwned, err)
+}
+
+// GetPracticeQuizzes handles fetching a random practice set
+// @Summary Get practice quizzes
+// @Description Retrieve a random set of a course's quizzes, without their answers, to practice with
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Param request body PracticeRequest true "Course ID and number of quizzes (default 10, max 50)"
+// @Success 200 {array} PracticeQuizz
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /coursequizzs/practice [post]
+func (h *CourseQuizzController) GetPracticeQuizzes(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req PracticeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+	if req.Count == 0 {
+		req.Count = defaultPracticeCount
+	}
+	if req.Count < 0 || req.Count > maxPracticeCount {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("count must be between 1 and %d", maxPracticeCount)})
+		return
+	}
+
+	// Students lose access to course material once their enrollment expires
+	if denyExpiredAccess(ctx, h.db, c, req.CourseID) {
+		return
+	}
+
+	// Verify course exists
+	var exists bool
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", req.CourseID).Scan(&exists)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getPracticeQuizzesQuery, req.CourseID, req.Count)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve quizzes"})
+		return
+	}
+	defer rows.Close()
+
+	quizzes := []PracticeQuizz{}
+	for rows.Next() {
+		var quizz PracticeQuizz
+		if err := rows.Scan(
+			&quizz.ID, &quizz.Question, &quizz.Option1,
+			&quizz.Option2, &quizz.Option3, &quizz.Option4, &quizz.CourseID,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process quizzes"})
+			return
+		}
+		quizzes = append(quizzes, quizz)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing quizzes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, quizzes)
+}
+
+// GradePractice handles scoring a practice submission
+// @Summary Grade practice answers
+// @Description Score answers to practice quizzes. Practice never affects official grades or exam attempts.
+// @Tags quizzes
+// @Accept json
+// @Produce json
+// @Param submission body PracticeSubmission true "Course ID and answers"
+// @Success 200 {object} PracticeResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /coursequizzs/practice/grade [post]
+func (h *CourseQuizzController) GradePractice(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var submission PracticeSubmission
+	if err := c.ShouldBindJSON(&submission); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if submission.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+	if len(submission.Answers) == 0 || len(submission.Answers) > maxPracticeCount {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("between 1 and %d answers are required", maxPracticeCount)})
+		return
+	}
+
+	// Students lose access to course material once their enrollment expires
+	if denyExpiredAccess(ctx, h.db, c, submission.CourseID) {
+		return
+	}
+
+	ids := make([]int64, 0, len(submission.Answers))
+	for _, answer := range submission.Answers {
+		ids = append(ids, int64(answer.QuizzID))
+	}
+
+	rows, err := h.db.QueryContext(ctx, getQuizzAnswersQuery, submission.CourseID, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve answers"})
+		return
+	}
+	defer rows.Close()
+
+	correctAnswers := make(map[uint]string, len(ids))
+	for rows.Next() {
+		var id uint
+		var answer string
+		if err := rows.Scan(&id, &answer); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process answers"})
+			return
+		}
+		correctAnswers[id] = answer
+	}
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing answers"})
+		return
+	}
+
+	result := PracticeResult{Total: len(submission.Answers)}
+	for _, answer := range submission.Answers {
+		correctAnswer, ok := correctAnswers[answer.QuizzID]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Quiz %d does not belong to this course", answer.QuizzID)})
+			return
+		}
+		correct := strings.TrimSpace(answer.Answer) == strings.TrimSpace(correctAnswer)
+		if correct {
+			result.Correct++
+		}
+		result.Results = append(result.Results, PracticeAnswerResult{
+			QuizzID:       answer.QuizzID,
+			Correct:       correct,
+			CorrectAnswer: correctAnswer,
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
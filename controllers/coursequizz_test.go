@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/cuddest/dz-skills/models"
+)
+
+const courseExistsInlineQuery = "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)"
+
+func TestGetQuizzesByCourseReadsBody(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsInlineQuery, 7).returns(row(true))
+	f.expect(getQuizzesByCourseQuery, 7).returns(
+		row(1, "2+2?", "3", "4", "5", "6", "4", 7),
+	)
+
+	w := serve(t, NewCourseQuizzController(db).GetQuizzesByCourse, testRequest{body: `{"course_id": 7}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var quizzes []models.CourseQuizz
+	decodeBody(t, w, &quizzes)
+	if len(quizzes) != 1 || quizzes[0].CourseID != 7 {
+		t.Errorf("quizzes = %+v, want the one quiz of course 7", quizzes)
+	}
+}
+
+func TestGetQuizzesByCourseMissingCourse(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsInlineQuery, 7).returns(row(false))
+
+	w := serve(t, NewCourseQuizzController(db).GetQuizzesByCourse, testRequest{body: `{"course_id": 7}`})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestGetQuizzesByCourseRejectsBadBodies(t *testing.T) {
+	for _, body := range []string{``, `{}`, `{"course_id": "seven"}`} {
+		db, _ := newFakeDB(t)
+		w := serve(t, NewCourseQuizzController(db).GetQuizzesByCourse, testRequest{body: body})
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+}
+
+func TestDeleteQuizzesBulk(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(getQuizzesNotOwnedQuery, []int64{4, 5}, 3)
+	f.expect(deleteQuizzesBulkQuery, []int64{4, 5}).affects(2)
+
+	w := serve(t, NewCourseQuizzController(db).DeleteQuizzesBulk, testRequest{
+		method: http.MethodDelete,
+		body:   `{"ids": [4, 5, 4]}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var resp struct {
+		Requested int   `json:"requested"`
+		Deleted   int64 `json:"deleted"`
+	}
+	decodeBody(t, w, &resp)
+	if resp.Requested != 2 || resp.Deleted != 2 {
+		t.Errorf("response = %+v, want 2 requested and 2 deleted", resp)
+	}
+	if f.commits != 1 {
+		t.Errorf("commits = %d, want 1", f.commits)
+	}
+}
+
+func TestDeleteQuizzesBulkRejectsUnownedIDs(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(getQuizzesNotOwnedQuery, []int64{4, 9}, 3).returns(row(9))
+
+	// No delete is scripted, the fake fails the test if one runs
+	w := serve(t, NewCourseQuizzController(db).DeleteQuizzesBulk, testRequest{
+		method: http.MethodDelete,
+		body:   `{"ids": [4, 9]}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusForbidden)
+
+	var resp struct {
+		NotOwned []int64 `json:"not_owned"`
+	}
+	decodeBody(t, w, &resp)
+	if len(resp.NotOwned) != 1 || resp.NotOwned[0] != 9 {
+		t.Errorf("not_owned = %v, want [9]", resp.NotOwned)
+	}
+	if f.commits != 0 || f.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want the transaction rolled back", f.commits, f.rollbacks)
+	}
+}
+
+func TestDeleteQuizzesBulkRequiresTeacher(t *testing.T) {
+	db, _ := newFakeDB(t)
+	w := serve(t, NewCourseQuizzController(db).DeleteQuizzesBulk, testRequest{
+		method: http.MethodDelete,
+		body:   `{"ids": [4]}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusForbidden)
+}
+
+func TestGetPracticeQuizzesLeavesAnswersOut(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsInlineQuery, 7).returns(row(true))
+	f.expect(getPracticeQuizzesQuery, 7, 3).returns(
+		row(1, "2+2?", "3", "4", "5", "6", 7),
+		row(2, "3+3?", "5", "6", "7", "8", 7),
+	)
+
+	w := serve(t, NewCourseQuizzController(db).GetPracticeQuizzes, testRequest{body: `{"course_id": 7, "count": 3}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var quizzes []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &quizzes); err != nil {
+		t.Fatalf("decode %s: %v", w.Body.String(), err)
+	}
+	if len(quizzes) != 2 {
+		t.Fatalf("got %d quizzes, want 2", len(quizzes))
+	}
+	for _, quizz := range quizzes {
+		if _, ok := quizz["Answer"]; ok {
+			t.Errorf("practice quiz %v exposes its answer", quizz["ID"])
+		}
+	}
+}
+
+func TestGetPracticeQuizzesCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		limit  int
+		status int
+	}{
+		{"default", `{"course_id": 7}`, defaultPracticeCount, http.StatusOK},
+		{"maximum", `{"course_id": 7, "count": 50}`, maxPracticeCount, http.StatusOK},
+		{"too many", `{"course_id": 7, "count": 51}`, 0, http.StatusBadRequest},
+		{"negative", `{"course_id": 7, "count": -1}`, 0, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			if tt.status == http.StatusOK {
+				f.expect(courseExistsInlineQuery, 7).returns(row(true))
+				f.expect(getPracticeQuizzesQuery, 7, tt.limit)
+			}
+
+			w := serve(t, NewCourseQuizzController(db).GetPracticeQuizzes, testRequest{body: tt.body})
+			expectStatus(t, w, tt.status)
+		})
+	}
+}
+
+func TestGradePractice(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+	f.expect(getQuizzAnswersQuery, 7, []int64{1, 2}).returns(
+		row(1, "4"),
+		row(2, "6"),
+	)
+
+	// Only the reads above are scripted, so any write to student_courses,
+	// a grade or an attempt count, fails the test
+	w := serve(t, NewCourseQuizzController(db).GradePractice, testRequest{
+		body: `{"course_id": 7, "answers": [
+			{"quizz_id": 1, "answer": " 4 "},
+			{"quizz_id": 2, "answer": "7"}
+		]}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var result PracticeResult
+	decodeBody(t, w, &result)
+	if result.Correct != 1 || result.Total != 2 {
+		t.Errorf("score = %d/%d, want 1/2", result.Correct, result.Total)
+	}
+	if len(result.Results) != 2 || !result.Results[0].Correct || result.Results[1].Correct || result.Results[1].CorrectAnswer != "6" {
+		t.Errorf("results = %+v, want quiz 1 right and quiz 2 wrong with answer 6", result.Results)
+	}
+	if f.commits != 0 {
+		t.Errorf("commits = %d, practice grading must not write anything", f.commits)
+	}
+}
+
+func TestGradePracticeRejectsQuizzesOfOtherCourses(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getQuizzAnswersQuery, 7, []int64{1, 9}).returns(row(1, "4"))
+
+	w := serve(t, NewCourseQuizzController(db).GradePractice, testRequest{
+		body: `{"course_id": 7, "answers": [{"quizz_id": 1, "answer": "4"}, {"quizz_id": 9, "answer": "x"}]}`,
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
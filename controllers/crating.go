@@ -4,8 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"math"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/cuddest/dz-skills/models"
@@ -14,9 +14,12 @@ import (
 
 // SQL queries for Crating
 const (
+	// A student rating a course again replaces their previous rating
 	createCratingQuery = `
 		INSERT INTO cratings (course_id, student_id, rating) 
-		VALUES ($1, $2, $3)`
+		VALUES ($1, $2, $3) 
+		ON CONFLICT (course_id, student_id) DO UPDATE SET rating = EXCLUDED.rating 
+		RETURNING (xmax = 0) AS inserted`
 
 	getAverageRatingByCourseIDQuery = `
         SELECT COALESCE(AVG(rating), 0) as average_rating, COUNT(*) as total_ratings
@@ -28,8 +31,18 @@ const (
 		FROM cratings WHERE course_id = $1`
 
 	getCratingByStudentIDQuery = `
-		SELECT course_id, student_id, rating 
-		FROM cratings WHERE student_id = $1`
+		SELECT r.course_id, r.student_id, r.rating, c.name 
+		FROM cratings r 
+		JOIN courses c ON c.id = r.course_id 
+		WHERE r.student_id = $1 AND c.deleted_at IS NULL 
+		ORDER BY c.name ASC, c.id ASC 
+		LIMIT $2 OFFSET $3`
+
+	countCratingsByStudentQuery = `
+		SELECT COUNT(*) 
+		FROM cratings r 
+		JOIN courses c ON c.id = r.course_id 
+		WHERE r.student_id = $1 AND c.deleted_at IS NULL`
 
 	getCratingByCourseAndStudentIDQuery = `
 		SELECT course_id, student_id, rating 
@@ -46,12 +59,73 @@ const (
 
 	deleteCratingQuery = `
 		DELETE FROM cratings WHERE course_id = $1 AND student_id = $2`
+
+	refreshCourseRatingQuery = `
+		UPDATE courses 
+		SET average_rating = r.average_rating, ratings_count = r.total_ratings 
+		FROM (
+			SELECT COALESCE(AVG(rating), 0) AS average_rating, COUNT(*) AS total_ratings 
+			FROM cratings WHERE course_id = $1
+		) r 
+		WHERE courses.id = $1`
 )
 
+// refreshCourseRating recomputes the denormalized rating of a course, to be
+// called in the same transaction as any change to its ratings
+func refreshCourseRating(ctx context.Context, tx *sql.Tx, courseID uint) error {
+	_, err := tx.ExecContext(ctx, refreshCourseRatingQuery, courseID)
+	return err
+}
+
 type CratingController struct {
 	db *sql.DB
 }
 
+// CourseAverageRating is the response of GetCourseAverageRating
+type CourseAverageRating struct {
+	CourseID           uint    `json:"course_id"`
+	AverageRating      float64 `json:"average_rating"` // rounded to 2 decimals
+	AverageRatingExact float64 `json:"average_rating_exact"`
+	TotalRatings       int     `json:"total_ratings"`
+}
+
+// CourseRatingsRequest selects the course whose ratings to read
+type CourseRatingsRequest struct {
+	CourseID uint `json:"course_id"`
+}
+
+// CratingKeyRequest identifies a rating by its course and student
+type CratingKeyRequest struct {
+	CourseID  uint `json:"course_id"`
+	StudentID uint `json:"student_id"`
+}
+
+// bindCratingKey reads a CratingKeyRequest body, writing a 400 and returning
+// false when either ID is missing or invalid
+func bindCratingKey(c *gin.Context) (CratingKeyRequest, bool) {
+	var req CratingKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	if req.CourseID == 0 || req.StudentID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course_id and student_id are required"})
+		return req, false
+	}
+	return req, true
+}
+
+// StudentRating is a rating the student gave along with the rated course
+type StudentRating struct {
+	models.Crating
+	CourseName string `json:"course_name"`
+}
+
+// roundRating rounds a rating to 2 decimals for display
+func roundRating(rating float64) float64 {
+	return math.Round(rating*100) / 100
+}
+
 func NewCratingController(db *sql.DB) *CratingController {
 	return &CratingController{db: db}
 }
@@ -60,18 +134,19 @@ func (h *CratingController) validateCrating(crating *models.Crating) error {
 	if crating.CourseID == 0 || crating.StudentID == 0 {
 		return errors.New("course_id and student_id are required")
 	}
-	if crating.Rating < 0 || crating.Rating > 5 {
-		return errors.New("rating must be between 0 and 5")
+	if crating.Rating < 1 || crating.Rating > 5 {
+		return errors.New("rating must be between 1 and 5")
 	}
 	return nil
 }
 
 // @Summary Create new rating
-// @Description Create a new course rating
+// @Description Create a new course rating, or replace the rating the student already gave the course
 // @Tags ratings
 // @Accept json
 // @Produce json
 // @Param rating body models.Crating true "Rating object"
+// @Success 200 {object} models.Crating
 // @Success 201 {object} models.Crating
 // @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
@@ -92,12 +167,22 @@ func (h *CratingController) CreateCrating(c *gin.Context) {
 		return
 	}
 
-	_, err := h.db.ExecContext(ctx, createCratingQuery, crating.CourseID, crating.StudentID, crating.Rating)
+	var inserted bool
+	err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, createCratingQuery, crating.CourseID, crating.StudentID, crating.Rating).Scan(&inserted); err != nil {
+			return err
+		}
+		return refreshCourseRating(ctx, tx, crating.CourseID)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create rating"})
+		respondDBError(c, err, "Failed to create rating")
 		return
 	}
 
+	if !inserted {
+		c.JSON(http.StatusOK, crating)
+		return
+	}
 	c.JSON(http.StatusCreated, crating)
 }
 
@@ -106,7 +191,7 @@ func (h *CratingController) CreateCrating(c *gin.Context) {
 // @Tags ratings
 // @Accept json
 // @Produce json
-// @Param course_id path int true "Course ID"
+// @Param request body CourseRatingsRequest true "Course ID"
 // @Success 200 {array} models.Crating
 // @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
@@ -116,13 +201,17 @@ func (h *CratingController) GetCratingsByCourse(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	courseID, err := strconv.Atoi(c.Param("course_id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
+	var req CourseRatingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
 		return
 	}
 
-	rows, err := h.db.QueryContext(ctx, getCratingByCourseIDQuery, courseID)
+	rows, err := h.db.QueryContext(ctx, getCratingByCourseIDQuery, req.CourseID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ratings"})
 		return
@@ -142,45 +231,68 @@ func (h *CratingController) GetCratingsByCourse(c *gin.Context) {
 	c.JSON(http.StatusOK, cratings)
 }
 
-// @Summary Get ratings by student
-// @Description Retrieve all ratings for a specific student
+// @Summary Get the student's ratings
+// @Description Retrieve a page of the ratings the authenticated student gave, each with the rated course name
 // @Tags ratings
-// @Accept json
 // @Produce json
-// @Param student_id path int true "Student ID"
-// @Success 200 {array} models.Crating
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of ratings per page (max 100)"
+// @Success 200 {object} PagedResponse{data=[]StudentRating}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
 // @Router /cratings/GetCratingsByStudent [post]
-// GetCratingsByStudent retrieves ratings by student
+// GetCratingsByStudent retrieves the ratings of the authenticated student
 func (h *CratingController) GetCratingsByStudent(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	studentID, err := strconv.Atoi(c.Param("student_id"))
+	page, err := parsePagination(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID format"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	rows, err := h.db.QueryContext(ctx, getCratingByStudentIDQuery, studentID)
+	studentID, ok, err := authenticatedStudentID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only students can list their ratings"})
+		return
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countCratingsByStudentQuery, studentID).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count ratings"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getCratingByStudentIDQuery, studentID, page.PageSize, page.Offset())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ratings"})
 		return
 	}
 	defer rows.Close()
 
-	var cratings []models.Crating
+	ratings := []StudentRating{}
 	for rows.Next() {
-		var crating models.Crating
-		if err := rows.Scan(&crating.CourseID, &crating.StudentID, &crating.Rating); err != nil {
+		var rating StudentRating
+		if err := rows.Scan(&rating.CourseID, &rating.StudentID, &rating.Rating, &rating.CourseName); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process ratings"})
 			return
 		}
-		cratings = append(cratings, crating)
+		ratings = append(ratings, rating)
 	}
 
-	c.JSON(http.StatusOK, cratings)
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing ratings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, PagedResponse{Data: ratings, Pagination: page.Meta(total)})
 }
 
 // @Summary Get rating by course and student
@@ -188,8 +300,7 @@ func (h *CratingController) GetCratingsByStudent(c *gin.Context) {
 // @Tags ratings
 // @Accept json
 // @Produce json
-// @Param course_id path int true "Course ID"
-// @Param student_id path int true "Student ID"
+// @Param request body CratingKeyRequest true "Course and student IDs"
 // @Success 200 {object} models.Crating
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -200,20 +311,13 @@ func (h *CratingController) GetCratingByCourseAndStudent(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	courseID, err := strconv.Atoi(c.Param("course_id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
-		return
-	}
-
-	studentID, err := strconv.Atoi(c.Param("student_id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID format"})
+	req, ok := bindCratingKey(c)
+	if !ok {
 		return
 	}
 
 	var crating models.Crating
-	err = h.db.QueryRowContext(ctx, getCratingByCourseAndStudentIDQuery, courseID, studentID).Scan(&crating.CourseID, &crating.StudentID, &crating.Rating)
+	err := h.db.QueryRowContext(ctx, getCratingByCourseAndStudentIDQuery, req.CourseID, req.StudentID).Scan(&crating.CourseID, &crating.StudentID, &crating.Rating)
 	if err == sql.ErrNoRows {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Rating not found"})
 		return
@@ -252,9 +356,14 @@ func (h *CratingController) UpdateCrating(c *gin.Context) {
 		return
 	}
 
-	_, err := h.db.ExecContext(ctx, updateCratingQuery, crating.Rating, crating.CourseID, crating.StudentID)
+	err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, updateCratingQuery, crating.Rating, crating.CourseID, crating.StudentID); err != nil {
+			return err
+		}
+		return refreshCourseRating(ctx, tx, crating.CourseID)
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update rating"})
+		respondDBError(c, err, "Failed to update rating")
 		return
 	}
 
@@ -266,8 +375,7 @@ func (h *CratingController) UpdateCrating(c *gin.Context) {
 // @Tags ratings
 // @Accept json
 // @Produce json
-// @Param course_id path int true "Course ID"
-// @Param student_id path int true "Student ID"
+// @Param request body CratingKeyRequest true "Course and student IDs"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
@@ -278,19 +386,17 @@ func (h *CratingController) DeleteCrating(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	courseID, err := strconv.Atoi(c.Param("course_id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
-		return
-	}
-
-	studentID, err := strconv.Atoi(c.Param("student_id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID format"})
+	req, ok := bindCratingKey(c)
+	if !ok {
 		return
 	}
 
-	_, err = h.db.ExecContext(ctx, deleteCratingQuery, courseID, studentID)
+	err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, deleteCratingQuery, req.CourseID, req.StudentID); err != nil {
+			return err
+		}
+		return refreshCourseRating(ctx, tx, req.CourseID)
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete rating"})
 		return
@@ -304,8 +410,8 @@ func (h *CratingController) DeleteCrating(c *gin.Context) {
 // @Tags ratings
 // @Accept json
 // @Produce json
-// @Param course_id path int true "Course ID"
-// @Success 200 {object} map[string]interface{}
+// @Param request body CourseRatingsRequest true "Course ID"
+// @Success 200 {object} CourseAverageRating
 // @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /cratings/GetCourseAverageRating [post]
@@ -314,25 +420,30 @@ func (h *CratingController) GetCourseAverageRating(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	courseID, err := strconv.Atoi(c.Param("course_id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
+	var req CourseRatingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
 		return
 	}
 
 	var averageRating float64
 	var totalRatings int
 
-	err = h.db.QueryRowContext(ctx, getAverageRatingByCourseIDQuery, courseID).Scan(&averageRating, &totalRatings)
+	err := h.db.QueryRowContext(ctx, getAverageRatingByCourseIDQuery, req.CourseID).Scan(&averageRating, &totalRatings)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate average rating"})
 		return
 	}
 
-	response := gin.H{
-		"course_id":      courseID,
-		"average_rating": averageRating,
-		"total_ratings":  totalRatings,
+	response := CourseAverageRating{
+		CourseID:           req.CourseID,
+		AverageRating:      roundRating(averageRating),
+		AverageRatingExact: averageRating,
+		TotalRatings:       totalRatings,
 	}
 
 	c.JSON(http.StatusOK, response)
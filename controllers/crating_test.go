@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetCratingByCourseAndStudentReturnsOneRating(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getCratingByCourseAndStudentIDQuery, 3, 5).returns(row(3, 5, 4.5))
+
+	w := serve(t, NewCratingController(db).GetCratingByCourseAndStudent, testRequest{
+		body: `{"course_id": 3, "student_id": 5}`,
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var rating map[string]interface{}
+	decodeBody(t, w, &rating)
+	if rating["course_id"] != float64(3) || rating["student_id"] != float64(5) || rating["rating"] != 4.5 {
+		t.Errorf("rating = %v, want course 3 student 5 rated 4.5", rating)
+	}
+}
+
+// The single rating lookup and the student's listing used to share a route,
+// the listing answers a page of every course the student rated
+func TestGetCratingsByStudentReturnsPage(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(countCratingsByStudentQuery, 5).returns(row(2))
+	f.expect(getCratingByStudentIDQuery, 5, defaultPageSize, 0).returns(
+		row(3, 5, 4.5, "Go"),
+		row(4, 5, 2, "Rust"),
+	)
+
+	w := serve(t, NewCratingController(db).GetCratingsByStudent, testRequest{
+		body:   `{"course_id": 3, "student_id": 5}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var page struct {
+		Data []StudentRating `json:"data"`
+	}
+	decodeBody(t, w, &page)
+	if len(page.Data) != 2 {
+		t.Errorf("got %d ratings, want 2", len(page.Data))
+	}
+}
+
+func TestGetCratingsByStudentEmbedsCourseName(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(countCratingsByStudentQuery, 5).returns(row(3))
+	f.expect(getCratingByStudentIDQuery, 5, 2, 2).returns(row(4, 5, 2, "Rust"))
+
+	w := serve(t, NewCratingController(db).GetCratingsByStudent, testRequest{
+		query:  "page=2&page_size=2",
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var page struct {
+		Data       []StudentRating `json:"data"`
+		Pagination PageMeta        `json:"pagination"`
+	}
+	decodeBody(t, w, &page)
+	if len(page.Data) != 1 || page.Data[0].CourseName != "Rust" || page.Data[0].CourseID != 4 {
+		t.Errorf("ratings = %+v, want course 4 named Rust", page.Data)
+	}
+	if page.Pagination.Total != 3 || page.Pagination.HasNext || !page.Pagination.HasPrev {
+		t.Errorf("pagination = %+v, want the last page of 3 ratings", page.Pagination)
+	}
+}
+
+// Every statement is scripted for student 5, so naming another student in
+// the body or query string fails the test
+func TestGetCratingsByStudentOnlyListsCaller(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(countCratingsByStudentQuery, 5).returns(row(1))
+	f.expect(getCratingByStudentIDQuery, 5, defaultPageSize, 0).returns(row(3, 5, 4.5, "Go"))
+
+	w := serve(t, NewCratingController(db).GetCratingsByStudent, testRequest{
+		body:   `{"student_id": 9}`,
+		query:  "student_id=9",
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var page struct {
+		Data []StudentRating `json:"data"`
+	}
+	decodeBody(t, w, &page)
+	for _, rating := range page.Data {
+		if rating.StudentID != 5 {
+			t.Errorf("rating %+v belongs to another student", rating)
+		}
+	}
+}
+
+func TestGetCratingsByStudentRequiresStudent(t *testing.T) {
+	db, _ := newFakeDB(t)
+	w := serve(t, NewCratingController(db).GetCratingsByStudent, testRequest{claims: teacherClaims("bob")})
+	expectStatus(t, w, http.StatusForbidden)
+}
+
+func TestGetCratingByCourseAndStudentRejectsBadBodies(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty", ``},
+		{"missing student", `{"course_id": 3}`},
+		{"non-numeric course", `{"course_id": "3", "student_id": 5}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newFakeDB(t)
+			w := serve(t, NewCratingController(db).GetCratingByCourseAndStudent, testRequest{body: tt.body})
+			expectStatus(t, w, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestGetCratingByCourseAndStudentNotFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getCratingByCourseAndStudentIDQuery, 3, 5).returns()
+
+	w := serve(t, NewCratingController(db).GetCratingByCourseAndStudent, testRequest{
+		body: `{"course_id": 3, "student_id": 5}`,
+	})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestGetCratingsByCourseReadsBody(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getCratingByCourseIDQuery, 3).returns(row(3, 5, 4), row(3, 6, 5))
+
+	w := serve(t, NewCratingController(db).GetCratingsByCourse, testRequest{body: `{"course_id": 3}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var ratings []map[string]interface{}
+	decodeBody(t, w, &ratings)
+	if len(ratings) != 2 {
+		t.Errorf("got %d ratings, want 2", len(ratings))
+	}
+}
+
+func TestDeleteCratingReadsBody(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(deleteCratingQuery, 3, 5).affects(1)
+	f.expect(refreshCourseRatingQuery, 3)
+
+	w := serve(t, NewCratingController(db).DeleteCrating, testRequest{
+		method: http.MethodDelete,
+		body:   `{"course_id": 3, "student_id": 5}`,
+	})
+	expectStatus(t, w, http.StatusOK)
+	if f.commits != 1 {
+		t.Errorf("commits = %d, want 1", f.commits)
+	}
+}
+
+func TestGetCourseAverageRatingRounds(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getAverageRatingByCourseIDQuery, 3).returns(row(4.333333333, 3))
+
+	w := serve(t, NewCratingController(db).GetCourseAverageRating, testRequest{body: `{"course_id": 3}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var got CourseAverageRating
+	decodeBody(t, w, &got)
+	want := CourseAverageRating{CourseID: 3, AverageRating: 4.33, AverageRatingExact: 4.333333333, TotalRatings: 3}
+	if got != want {
+		t.Errorf("average = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetCourseAverageRatingWithoutRatings(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getAverageRatingByCourseIDQuery, 3).returns(row(0, 0))
+
+	w := serve(t, NewCratingController(db).GetCourseAverageRating, testRequest{body: `{"course_id": 3}`})
+	expectStatus(t, w, http.StatusOK)
+
+	// Every key is present even when nobody rated the course
+	var got map[string]interface{}
+	decodeBody(t, w, &got)
+	for _, key := range []string{"course_id", "average_rating", "average_rating_exact", "total_ratings"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("response %v has no %q", got, key)
+		}
+	}
+	if got["total_ratings"] != float64(0) || got["average_rating"] != float64(0) {
+		t.Errorf("response = %v, want zero average and count", got)
+	}
+}
+
+func TestRoundRating(t *testing.T) {
+	tests := []struct {
+		in, want float64
+	}{
+		{4.333333, 4.33},
+		{4.125, 4.13},
+		{4.666666, 4.67},
+		{5, 5},
+		{0, 0},
+	}
+	for _, tt := range tests {
+		if got := roundRating(tt.in); got != tt.want {
+			t.Errorf("roundRating(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGetCratingsByStudentSkipsDeletedCourses(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect("JOIN courses c ON c.id = r.course_id \n\t\tWHERE r.student_id = $1 AND c.deleted_at IS NULL", 5).returns(row(0))
+	f.expect("WHERE r.student_id = $1 AND c.deleted_at IS NULL", 5, defaultPageSize, 0)
+
+	w := serve(t, NewCratingController(db).GetCratingsByStudent, testRequest{
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+}
+
+// Rating a course twice goes through the upsert both times, the second call
+// replaces the first rating instead of adding a row
+func TestCreateCratingTwiceUpserts(t *testing.T) {
+	if !strings.Contains(createCratingQuery, "ON CONFLICT (course_id, student_id) DO UPDATE") {
+		t.Fatalf("createCratingQuery does not upsert on (course_id, student_id): %s", createCratingQuery)
+	}
+
+	db, f := newFakeDB(t)
+	f.expect(createCratingQuery, 3, 5, 4).returns(row(true))
+	f.expect(refreshCourseRatingQuery, 3)
+	f.expect(createCratingQuery, 3, 5, 2).returns(row(false))
+	f.expect(refreshCourseRatingQuery, 3)
+	f.expect(getCratingByCourseIDQuery, 3).returns(row(3, 5, 2))
+	h := NewCratingController(db)
+
+	w := serve(t, h.CreateCrating, testRequest{body: `{"course_id": 3, "student_id": 5, "rating": 4}`})
+	expectStatus(t, w, http.StatusCreated)
+
+	w = serve(t, h.CreateCrating, testRequest{body: `{"course_id": 3, "student_id": 5, "rating": 2}`})
+	expectStatus(t, w, http.StatusOK)
+
+	w = serve(t, h.GetCratingsByCourse, testRequest{body: `{"course_id": 3}`})
+	expectStatus(t, w, http.StatusOK)
+	var ratings []map[string]interface{}
+	decodeBody(t, w, &ratings)
+	if len(ratings) != 1 || ratings[0]["rating"] != float64(2) {
+		t.Errorf("ratings = %v, want the single updated rating", ratings)
+	}
+	if f.commits != 2 {
+		t.Errorf("commits = %d, want 2", f.commits)
+	}
+}
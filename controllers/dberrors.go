@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// checkViolation is the SQLSTATE Postgres reports when a row breaks a CHECK constraint
+const checkViolation = "23514"
+
+// sqlStateError is implemented by the driver's error type, matching it by
+// behaviour keeps the driver out of the controllers' imports
+type sqlStateError interface {
+	SQLState() string
+}
+
+// isCheckViolation reports whether err comes from a CHECK constraint of the database
+func isCheckViolation(err error) bool {
+	var stateErr sqlStateError
+	return errors.As(err, &stateErr) && stateErr.SQLState() == checkViolation
+}
+
+// respondDBError writes a 400 when the database rejected the write for
+// breaking a constraint the validators should have caught, and a 500 with
+// the given message for any other error
+func respondDBError(c *gin.Context, err error, message string) {
+	if isCheckViolation(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "value out of allowed range"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
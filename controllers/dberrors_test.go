@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// stateError stands in for the driver's error carrying a SQLSTATE
+type stateError string
+
+func (e stateError) Error() string    { return "database error " + string(e) }
+func (e stateError) SQLState() string { return string(e) }
+
+func TestIsCheckViolation(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{stateError(checkViolation), true},
+		{fmt.Errorf("insert: %w", stateError(checkViolation)), true},
+		{stateError("23505"), false},
+		{errors.New("connection reset"), false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isCheckViolation(tt.err); got != tt.want {
+			t.Errorf("isCheckViolation(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+// A rating written past the validators, like an import, is rejected by the
+// check constraint and reported as a bad request
+func TestCreateCratingCheckViolation(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(createCratingQuery, 3, 5, 4).fails(stateError(checkViolation))
+
+	w := serve(t, NewCratingController(db).CreateCrating, testRequest{body: `{"course_id": 3, "student_id": 5, "rating": 4}`})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	if got := responseKeys(t, w.Body.Bytes())["error"]; got != "value out of allowed range" {
+		t.Errorf("error = %v, want the range violation", got)
+	}
+	if f.rollbacks != 1 {
+		t.Errorf("rollbacks = %d, want 1", f.rollbacks)
+	}
+}
+
+func TestCreateCratingOtherDatabaseError(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(createCratingQuery, 3, 5, 4).fails(stateError("57014"))
+
+	w := serve(t, NewCratingController(db).CreateCrating, testRequest{body: `{"course_id": 3, "student_id": 5, "rating": 4}`})
+	expectStatus(t, w, http.StatusInternalServerError)
+}
+
+func TestCreateCratingOutOfRange(t *testing.T) {
+	for _, rating := range []string{"0", "6"} {
+		db, _ := newFakeDB(t)
+		w := serve(t, NewCratingController(db).CreateCrating, testRequest{body: `{"course_id": 3, "student_id": 5, "rating": ` + rating + `}`})
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+}
+
+func TestCreateFeedbackCheckViolation(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect("SELECT EXISTS(SELECT 1 FROM students WHERE id = $1)", 5).returns(row(true))
+	f.expect(createFeedbackQuery).fails(stateError(checkViolation))
+
+	w := serve(t, NewFeedbackController(db).CreateFeedback, testRequest{body: `{"Description": "Great", "Review": 5, "student_id": 5}`})
+	expectStatus(t, w, http.StatusBadRequest)
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/cuddest/dz-skills/config"
 	"github.com/cuddest/dz-skills/models"
 	"github.com/gin-gonic/gin"
 )
@@ -19,7 +20,7 @@ const (
 		VALUES ($1, $2) RETURNING id`
 
 	getExamQuery = `
-		SELECT e.id, e.description, e.course_id,
+		SELECT e.id, e.description, e.course_id, e.archived,
 			json_build_object(
 				'ID', c.id,
 				'Name', c.name,
@@ -27,20 +28,21 @@ const (
 			) as course
 		FROM exams e
 		LEFT JOIN courses c ON e.course_id = c.id 
-		WHERE e.id = $1`
+		WHERE e.id = $1 AND c.deleted_at IS NULL`
 
 	getAllExamsQuery = `
-		SELECT e.id, e.description, e.course_id,
+		SELECT e.id, e.description, e.course_id, e.archived,
 			json_build_object(
 				'ID', c.id,
 				'Name', c.name,
 				'Description', c.description
 			) as course
 		FROM exams e
-		LEFT JOIN courses c ON e.course_id = c.id`
+		LEFT JOIN courses c ON e.course_id = c.id
+		WHERE c.deleted_at IS NULL`
 
 	getExamsByCourseQuery = `
-		SELECT e.id, e.description, e.course_id,
+		SELECT e.id, e.description, e.course_id, e.archived,
 			json_build_object(
 				'ID', c.id,
 				'Name', c.name,
@@ -57,12 +59,39 @@ const (
 
 	deleteExamQuery = `
 		DELETE FROM exams WHERE id = $1`
+
+	getExamOwnerQuery = `
+		SELECT e.id, e.description, e.course_id, e.archived, c.teacher_id 
+		FROM exams e 
+		JOIN courses c ON c.id = e.course_id 
+		WHERE e.id = $1 AND c.deleted_at IS NULL`
+
+	setExamArchivedQuery = `
+		UPDATE exams SET archived = $1 WHERE id = $2`
+
+	getExamQuizzesQuery = `
+		SELECT id, question, option1, option2, option3, option4, answer, exam_id 
+		FROM exam_quizzes WHERE exam_id = $1 
+		ORDER BY id`
 )
 
 type ExamController struct {
 	db *sql.DB
 }
 
+// ExamPreview describes an exam before it is taken. Quizzes, with their
+// answers, are only included for the course teacher.
+type ExamPreview struct {
+	ExamID        uint               `json:"exam_id"`
+	CourseID      uint               `json:"course_id"`
+	Description   string             `json:"description"`
+	Archived      bool               `json:"archived"`
+	QuestionCount int                `json:"question_count"`
+	PassPercent   int                `json:"pass_percent"`
+	MaxAttempts   int                `json:"max_attempts"`
+	Quizzes       []models.ExamQuizz `json:"quizzes,omitempty"`
+}
+
 func NewExamController(db *sql.DB) *ExamController {
 	return &ExamController{db: db}
 }
@@ -106,7 +135,7 @@ func (h *ExamController) CreateExam(c *gin.Context) {
 
 	// Verify course exists
 	var exists bool
-	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", exam.CourseID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", exam.CourseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -132,7 +161,7 @@ func (h *ExamController) CreateExam(c *gin.Context) {
 // @Tags exams
 // @Accept json
 // @Produce json
-// @Param id body int true "Exam ID"
+// @Param request body IDRequest true "Exam ID"
 // @Success 200 {object} models.Exam
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -143,16 +172,15 @@ func (h *ExamController) GetExam(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var exam models.Exam
 	var courseJSON []byte
-	err = h.db.QueryRowContext(ctx, getExamQuery, id).Scan(
-		&exam.ID, &exam.Description, &exam.CourseID, &courseJSON,
+	err := h.db.QueryRowContext(ctx, getExamQuery, id).Scan(
+		&exam.ID, &exam.Description, &exam.CourseID, &exam.Archived, &courseJSON,
 	)
 
 	if err == sql.ErrNoRows {
@@ -164,6 +192,11 @@ func (h *ExamController) GetExam(c *gin.Context) {
 		return
 	}
 
+	if err := decodeJoined(courseJSON, &exam.Course); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exam"})
+		return
+	}
+
 	c.JSON(http.StatusOK, exam)
 }
 
@@ -192,11 +225,15 @@ func (h *ExamController) GetAllExams(c *gin.Context) {
 		var exam models.Exam
 		var courseJSON []byte
 		if err := rows.Scan(
-			&exam.ID, &exam.Description, &exam.CourseID, &courseJSON,
+			&exam.ID, &exam.Description, &exam.CourseID, &exam.Archived, &courseJSON,
 		); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exams"})
 			return
 		}
+		if err := decodeJoined(courseJSON, &exam.Course); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exams"})
+			return
+		}
 		exams = append(exams, exam)
 	}
 
@@ -232,7 +269,7 @@ func (h *ExamController) GetExamsByCourse(c *gin.Context) {
 
 	// Verify course exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", courseID).Scan(&exists)
+	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", courseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -254,11 +291,15 @@ func (h *ExamController) GetExamsByCourse(c *gin.Context) {
 		var exam models.Exam
 		var courseJSON []byte
 		if err := rows.Scan(
-			&exam.ID, &exam.Description, &exam.CourseID, &courseJSON,
+			&exam.ID, &exam.Description, &exam.CourseID, &exam.Archived, &courseJSON,
 		); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exams"})
 			return
 		}
+		if err := decodeJoined(courseJSON, &exam.Course); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exams"})
+			return
+		}
 		exams = append(exams, exam)
 	}
 
@@ -286,17 +327,16 @@ func (h *ExamController) UpdateExam(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var exam models.Exam
 	if err := c.ShouldBindJSON(&exam); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if exam.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := exam.ID
 
 	if err := h.validateExam(&exam); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -305,7 +345,7 @@ func (h *ExamController) UpdateExam(c *gin.Context) {
 
 	// Verify course exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", exam.CourseID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", exam.CourseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -315,6 +355,23 @@ func (h *ExamController) UpdateExam(c *gin.Context) {
 		return
 	}
 
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Exam
+	var courseJSON []byte
+	if wantsDiff(c) {
+		err = h.db.QueryRowContext(ctx, getExamQuery, id).Scan(
+			&before.ID, &before.Description, &before.CourseID, &before.Archived, &courseJSON,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Exam not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exam"})
+			return
+		}
+	}
+
 	result, err := h.db.ExecContext(ctx, updateExamQuery,
 		exam.Description, exam.CourseID, id)
 
@@ -333,8 +390,7 @@ func (h *ExamController) UpdateExam(c *gin.Context) {
 		return
 	}
 
-	exam.ID = uint(id)
-	c.JSON(http.StatusOK, exam)
+	respondUpdated(c, before, exam)
 }
 
 // @Summary Delete exam
@@ -377,3 +433,191 @@ func (h *ExamController) DeleteExam(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Exam deleted successfully"})
 }
+
+// @Summary Preview an exam
+// @Description Describe an exam without starting an attempt. The course teacher gets every question with its answer, enrolled students only the question count, pass threshold and attempt limit.
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param request body IDRequest true "Exam ID"
+// @Success 200 {object} ExamPreview
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /exams/preview [post]
+func (h *ExamController) PreviewExam(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	preview := ExamPreview{PassPercent: examPassPercent, MaxAttempts: config.ExamMaxAttempts()}
+	var ownerID sql.NullInt64
+	err := h.db.QueryRowContext(ctx, getExamOwnerQuery, id).Scan(
+		&preview.ExamID, &preview.Description, &preview.CourseID, &preview.Archived, &ownerID,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exam"})
+		return
+	}
+
+	teacherID, isTeacher, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify caller"})
+		return
+	}
+	isOwner := isTeacher && ownerID.Valid && uint(ownerID.Int64) == teacherID
+
+	if !isOwner {
+		studentID, isStudent, err := authenticatedStudentID(ctx, h.db, c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify caller"})
+			return
+		}
+		if !isStudent {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only the course teacher and its students can preview the exam"})
+			return
+		}
+		err = checkCourseAccess(ctx, h.db, studentID, preview.CourseID)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You are not enrolled in this course"})
+			return
+		}
+		if err == errAccessExpired {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Course access has expired"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course access"})
+			return
+		}
+		if preview.Archived {
+			c.JSON(http.StatusConflict, gin.H{"error": "This exam has been archived"})
+			return
+		}
+
+		if err := h.db.QueryRowContext(ctx, countExamQuizzesQuery, id).Scan(&preview.QuestionCount); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count exam questions"})
+			return
+		}
+		c.JSON(http.StatusOK, preview)
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getExamQuizzesQuery, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exam questions"})
+		return
+	}
+	defer rows.Close()
+
+	preview.Quizzes = []models.ExamQuizz{}
+	for rows.Next() {
+		var quizz models.ExamQuizz
+		if err := rows.Scan(
+			&quizz.ID, &quizz.Question, &quizz.Option1, &quizz.Option2,
+			&quizz.Option3, &quizz.Option4, &quizz.Answer, &quizz.ExamID,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exam questions"})
+			return
+		}
+		preview.Quizzes = append(preview.Quizzes, quizz)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing exam questions"})
+		return
+	}
+
+	preview.QuestionCount = len(preview.Quizzes)
+	c.JSON(http.StatusOK, preview)
+}
+
+// @Summary Archive an exam
+// @Description Retire an exam so students can no longer preview or take it. Past attempts and results stay available. Only the course teacher can archive its exam.
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param request body IDRequest true "Exam ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /exams/archive [post]
+func (h *ExamController) ArchiveExam(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// @Summary Unarchive an exam
+// @Description Make an archived exam available to students again. Only the course teacher can unarchive its exam.
+// @Tags exams
+// @Accept json
+// @Produce json
+// @Param request body IDRequest true "Exam ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /exams/unarchive [post]
+func (h *ExamController) UnarchiveExam(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+// setArchived archives or unarchives the exam named in the request body on
+// behalf of the course teacher
+func (h *ExamController) setArchived(c *gin.Context, archived bool) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	id, ok := bindID(c)
+	if !ok {
+		return
+	}
+
+	teacherID, isTeacher, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+		return
+	}
+	if !isTeacher {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only teachers can archive exams"})
+		return
+	}
+
+	var exam models.Exam
+	var ownerID sql.NullInt64
+	err = h.db.QueryRowContext(ctx, getExamOwnerQuery, id).Scan(
+		&exam.ID, &exam.Description, &exam.CourseID, &exam.Archived, &ownerID,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exam"})
+		return
+	}
+	if !ownerID.Valid || uint(ownerID.Int64) != teacherID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this exam's course"})
+		return
+	}
+
+	if _, err := h.db.ExecContext(ctx, setExamArchivedQuery, archived, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update exam"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exam_id": id, "archived": archived})
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPreviewExamForTeacherIncludesAnswers(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getExamOwnerQuery, 2).returns(row(2, "Final", 7, false, 3))
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(getExamQuizzesQuery, 2).returns(
+		row(1, "2+2?", "3", "4", "5", "6", 2, 2),
+		row(2, "3+3?", "5", "6", "7", "8", 2, 2),
+	)
+
+	w := serve(t, NewExamController(db).PreviewExam, testRequest{body: `{"id": 2}`, claims: teacherClaims("bob")})
+	expectStatus(t, w, http.StatusOK)
+
+	var preview ExamPreview
+	decodeBody(t, w, &preview)
+	if preview.QuestionCount != 2 || len(preview.Quizzes) != 2 {
+		t.Fatalf("preview = %+v, want both quizzes", preview)
+	}
+	if preview.Quizzes[0].Answer != 2 {
+		t.Errorf("answer = %d, want the teacher to see answer 2", preview.Quizzes[0].Answer)
+	}
+}
+
+func TestPreviewExamForStudentIsMetadataOnly(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getExamOwnerQuery, 2).returns(row(2, "Final", 7, false, 3))
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+	f.expect(countExamQuizzesQuery, 2).returns(row(12))
+
+	// Nothing else is scripted, so starting an attempt or bumping a counter fails the test
+	w := serve(t, NewExamController(db).PreviewExam, testRequest{body: `{"id": 2}`, claims: studentClaims("alice")})
+	expectStatus(t, w, http.StatusOK)
+
+	got := responseKeys(t, w.Body.Bytes())
+	if _, ok := got["quizzes"]; ok {
+		t.Errorf("student preview includes quizzes: %v", got)
+	}
+	if got["question_count"] != float64(12) || got["pass_percent"] != float64(examPassPercent) {
+		t.Errorf("preview = %v, want 12 questions and the pass threshold", got)
+	}
+	if _, ok := got["max_attempts"]; !ok {
+		t.Errorf("preview %v has no max_attempts", got)
+	}
+}
+
+func TestPreviewExamRequiresEnrollment(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getExamOwnerQuery, 2).returns(row(2, "Final", 7, false, 3))
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAccessExpiryQuery, 5, 7)
+
+	w := serve(t, NewExamController(db).PreviewExam, testRequest{body: `{"id": 2}`, claims: studentClaims("alice")})
+	expectStatus(t, w, http.StatusForbidden)
+}
+
+func TestPreviewExamArchivedForStudent(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getExamOwnerQuery, 2).returns(row(2, "Final", 7, true, 3))
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+
+	w := serve(t, NewExamController(db).PreviewExam, testRequest{body: `{"id": 2}`, claims: studentClaims("alice")})
+	expectStatus(t, w, http.StatusConflict)
+}
+
+func TestArchiveExam(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  func(*ExamController) func(*gin.Context)
+		archived bool
+	}{
+		{"archive", func(h *ExamController) func(*gin.Context) { return h.ArchiveExam }, true},
+		{"unarchive", func(h *ExamController) func(*gin.Context) { return h.UnarchiveExam }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+			f.expect(getExamOwnerQuery, 2).returns(row(2, "Final", 7, !tt.archived, 3))
+			f.expect(setExamArchivedQuery, tt.archived, 2).affects(1)
+
+			w := serve(t, tt.handler(NewExamController(db)), testRequest{body: `{"id": 2}`, claims: teacherClaims("bob")})
+			expectStatus(t, w, http.StatusOK)
+
+			got := responseKeys(t, w.Body.Bytes())
+			if got["archived"] != tt.archived {
+				t.Errorf("archived = %v, want %v", got["archived"], tt.archived)
+			}
+		})
+	}
+}
+
+func TestArchiveExamRequiresCourseTeacher(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "carol").returns(row(4))
+	f.expect(getExamOwnerQuery, 2).returns(row(2, "Final", 7, false, 3))
+
+	// No update is scripted, so archiving someone else's exam fails the test
+	w := serve(t, NewExamController(db).ArchiveExam, testRequest{body: `{"id": 2}`, claims: teacherClaims("carol")})
+	expectStatus(t, w, http.StatusForbidden)
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The facet queries take an optional category ID, NULL meaning every course
+const (
+	getLanguageFacetsQuery = `
+		SELECT language, COUNT(*) FROM courses 
+		WHERE ($1::integer IS NULL OR category_id = $1) AND deleted_at IS NULL AND COALESCE(language, '') <> '' 
+		GROUP BY language ORDER BY language`
+
+	getLevelFacetsQuery = `
+		SELECT level, COUNT(*) FROM courses 
+		WHERE ($1::integer IS NULL OR category_id = $1) AND deleted_at IS NULL AND COALESCE(level, '') <> '' 
+		GROUP BY level ORDER BY level`
+
+	getPriceRangeQuery = `
+		SELECT MIN(` + coursePriceExpr + `), MAX(` + coursePriceExpr + `) FROM courses 
+		WHERE ($1::integer IS NULL OR category_id = $1) AND deleted_at IS NULL`
+)
+
+// FacetValue is one value a course filter can take and how many courses have it
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// CourseFacets lists the values the course filters can take, to build the
+// filter UI of GetCoursesFiltered. The price range is null without any priced course.
+type CourseFacets struct {
+	Languages []FacetValue `json:"languages"`
+	Levels    []FacetValue `json:"levels"`
+	MinPrice  *float64     `json:"min_price"`
+	MaxPrice  *float64     `json:"max_price"`
+}
+
+// queryFacets runs a facet query and collects its value and count pairs
+func (h *CourseController) queryFacets(ctx context.Context, query string, categoryID interface{}) ([]FacetValue, error) {
+	rows, err := h.db.QueryContext(ctx, query, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facets := []FacetValue{}
+	for rows.Next() {
+		var facet FacetValue
+		if err := rows.Scan(&facet.Value, &facet.Count); err != nil {
+			return nil, err
+		}
+		facets = append(facets, facet)
+	}
+	return facets, rows.Err()
+}
+
+// @Summary Get course facets
+// @Description List the distinct languages and levels of the courses with their counts, and the range of their prices, optionally within a category
+// @Tags courses
+// @Produce json
+// @Param category_id query int false "Only facets of the courses in this category"
+// @Success 200 {object} CourseFacets
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/facets [get]
+func (h *CourseController) GetCourseFacets(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var categoryID interface{}
+	if raw := c.Query("category_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID format"})
+			return
+		}
+		categoryID = id
+	}
+
+	var facets CourseFacets
+	var err error
+	if facets.Languages, err = h.queryFacets(ctx, getLanguageFacetsQuery, categoryID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve languages"})
+		return
+	}
+	if facets.Levels, err = h.queryFacets(ctx, getLevelFacetsQuery, categoryID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve levels"})
+		return
+	}
+
+	var minPrice, maxPrice sql.NullFloat64
+	if err := h.db.QueryRowContext(ctx, getPriceRangeQuery, categoryID).Scan(&minPrice, &maxPrice); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve the price range"})
+		return
+	}
+	if minPrice.Valid {
+		facets.MinPrice = &minPrice.Float64
+	}
+	if maxPrice.Valid {
+		facets.MaxPrice = &maxPrice.Float64
+	}
+
+	respond(c, http.StatusOK, facets)
+}
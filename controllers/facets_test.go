@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetCourseFacetsNarrowByCategory(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		category  interface{}
+		languages [][]interface{}
+		levels    [][]interface{}
+		prices    []interface{}
+		want      CourseFacets
+	}{
+		{
+			"every course", "", nil,
+			[][]interface{}{row("en", 3), row("fr", 1)},
+			[][]interface{}{row("advanced", 1), row("beginner", 3)},
+			row(0, 99.5),
+			CourseFacets{
+				Languages: []FacetValue{{"en", 3}, {"fr", 1}},
+				Levels:    []FacetValue{{"advanced", 1}, {"beginner", 3}},
+			},
+		},
+		{
+			"one category", "category_id=2", 2,
+			[][]interface{}{row("en", 2)},
+			[][]interface{}{row("beginner", 2)},
+			row(10, 49.99),
+			CourseFacets{
+				Languages: []FacetValue{{"en", 2}},
+				Levels:    []FacetValue{{"beginner", 2}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(getLanguageFacetsQuery, tt.category).returns(tt.languages...)
+			f.expect(getLevelFacetsQuery, tt.category).returns(tt.levels...)
+			f.expect(getPriceRangeQuery, tt.category).returns(tt.prices)
+
+			w := serve(t, NewCourseController(db).GetCourseFacets, testRequest{method: http.MethodGet, query: tt.query})
+			expectStatus(t, w, http.StatusOK)
+
+			var got CourseFacets
+			decodeBody(t, w, &got)
+			if !facetsEqual(got.Languages, tt.want.Languages) || !facetsEqual(got.Levels, tt.want.Levels) {
+				t.Errorf("facets = %+v, want %+v", got, tt.want)
+			}
+			if got.MinPrice == nil || got.MaxPrice == nil || *got.MaxPrice != tt.prices[1] {
+				t.Errorf("price range = %v-%v, want up to %v", got.MinPrice, got.MaxPrice, tt.prices[1])
+			}
+		})
+	}
+}
+
+func TestGetCourseFacetsWithoutPricedCourses(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getLanguageFacetsQuery, 2)
+	f.expect(getLevelFacetsQuery, 2)
+	f.expect(getPriceRangeQuery, 2).returns(row(nil, nil))
+
+	w := serve(t, NewCourseController(db).GetCourseFacets, testRequest{method: http.MethodGet, query: "category_id=2"})
+	expectStatus(t, w, http.StatusOK)
+
+	got := responseKeys(t, w.Body.Bytes())
+	if got["min_price"] != nil || got["max_price"] != nil {
+		t.Errorf("price range = %v-%v, want null", got["min_price"], got["max_price"])
+	}
+}
+
+func TestGetCourseFacetsRejectsBadCategory(t *testing.T) {
+	db, _ := newFakeDB(t)
+	w := serve(t, NewCourseController(db).GetCourseFacets, testRequest{method: http.MethodGet, query: "category_id=go"})
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func facetsEqual(a, b []FacetValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
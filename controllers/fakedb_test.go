@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB is a database/sql driver that answers statements from a script of
+// expectations, so handlers can be exercised without PostgreSQL. A statement
+// is matched to the first unused expectation whose query it contains and,
+// when the expectation lists arguments, whose arguments are equal.
+type fakeDB struct {
+	t  *testing.T
+	mu sync.Mutex
+
+	expected  []*expectation
+	commits   int
+	rollbacks int
+}
+
+// expectation is one scripted statement and what it answers
+type expectation struct {
+	query   string
+	args    []driver.Value
+	columns []string
+	rows    [][]driver.Value
+	result  int64
+	err     error
+	used    bool
+}
+
+// newFakeDB returns a database backed by a fresh script, every expectation
+// must have been used by the end of the test
+func newFakeDB(t *testing.T) (*sql.DB, *fakeDB) {
+	t.Helper()
+	f := &fakeDB{t: t}
+	db := sql.OpenDB(fakeConnector{f})
+	t.Cleanup(func() {
+		db.Close()
+		for _, e := range f.expected {
+			if !e.used {
+				t.Errorf("expected statement was not run: %s %v", strings.TrimSpace(e.query), e.args)
+			}
+		}
+	})
+	return db, f
+}
+
+// expect adds a statement containing query, args are only compared when given
+func (f *fakeDB) expect(query string, args ...interface{}) *expectation {
+	e := &expectation{query: strings.TrimSpace(query)}
+	for _, arg := range args {
+		e.args = append(e.args, normalizeValue(arg))
+	}
+	f.mu.Lock()
+	f.expected = append(f.expected, e)
+	f.mu.Unlock()
+	return e
+}
+
+// returns sets the rows a query answers, no row at all makes QueryRow fail
+// with sql.ErrNoRows
+func (e *expectation) returns(rows ...[]interface{}) *expectation {
+	for _, row := range rows {
+		values := make([]driver.Value, len(row))
+		for i, v := range row {
+			values[i] = normalizeValue(v)
+		}
+		e.rows = append(e.rows, values)
+		if e.columns == nil {
+			for i := range row {
+				e.columns = append(e.columns, fmt.Sprintf("c%d", i))
+			}
+		}
+	}
+	return e
+}
+
+// named names the result columns, for callers like GORM that scan by name
+func (e *expectation) named(columns ...string) *expectation {
+	e.columns = columns
+	return e
+}
+
+// affects sets the number of rows an Exec reports as changed
+func (e *expectation) affects(n int64) *expectation {
+	e.result = n
+	return e
+}
+
+// fails makes the statement return err
+func (e *expectation) fails(err error) *expectation {
+	e.err = err
+	return e
+}
+
+// row is shorthand for one row of returns
+func row(values ...interface{}) []interface{} {
+	return values
+}
+
+func normalizeValue(v interface{}) driver.Value {
+	if converted, err := driver.DefaultParameterConverter.ConvertValue(v); err == nil {
+		return converted
+	}
+	return v
+}
+
+func (f *fakeDB) match(query string, args []driver.NamedValue) (*expectation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, e := range f.expected {
+		if e.used || !strings.Contains(query, e.query) {
+			continue
+		}
+		if e.args != nil && !argsEqual(e.args, args) {
+			continue
+		}
+		e.used = true
+		return e, e.err
+	}
+
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	f.t.Errorf("unexpected statement: %s %v", strings.TrimSpace(query), values)
+	return nil, errors.New("fakedb: unexpected statement")
+}
+
+func argsEqual(want []driver.Value, got []driver.NamedValue) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if fmt.Sprint(want[i]) != fmt.Sprint(got[i].Value) {
+			return false
+		}
+	}
+	return true
+}
+
+type fakeConnector struct{ db *fakeDB }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: c.db}, nil }
+func (c fakeConnector) Driver() driver.Driver                        { return fakeDriver{} }
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("fakedb: open through newFakeDB")
+}
+
+type fakeConn struct{ db *fakeDB }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fakedb: prepared statements are not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{c.db}, nil }
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return fakeTx{c.db}, nil
+}
+
+// CheckNamedValue lets arguments the default converter rejects, like the ID
+// slices passed as Postgres arrays, through unchanged
+func (c *fakeConn) CheckNamedValue(nv *driver.NamedValue) error {
+	nv.Value = normalizeValue(nv.Value)
+	return nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	e, err := c.db.match(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{columns: e.columns, rows: e.rows}, nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, err := c.db.match(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(e.result), nil
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (tx fakeTx) Commit() error {
+	tx.db.mu.Lock()
+	tx.db.commits++
+	tx.db.mu.Unlock()
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.db.mu.Lock()
+	tx.db.rollbacks++
+	tx.db.mu.Unlock()
+	return nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	next    int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
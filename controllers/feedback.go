@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cuddest/dz-skills/models"
@@ -15,50 +17,88 @@ import (
 // SQL queries for Feedback
 const (
 	createFeedbackQuery = `
-		INSERT INTO feedbacks (description, review, student_id) 
-		VALUES ($1, $2, $3) RETURNING id`
+		INSERT INTO feedbacks (description, review, student_id, course_id) 
+		VALUES ($1, $2, $3, $4) RETURNING id`
 
 	getFeedbackQuery = `
-		SELECT f.id, f.description, f.review, f.student_id,
+		SELECT f.id, f.description, f.review, f.student_id, f.course_id,
 			json_build_object(
 				'ID', s.id,
-				'Name', s.name,
-				'Email', s.email
+				'FullName', s.full_name,
+				'email', s.email
 			) as student
 		FROM feedbacks f
 		LEFT JOIN students s ON f.student_id = s.id 
 		WHERE f.id = $1`
 
 	getAllFeedbacksQuery = `
-		SELECT f.id, f.description, f.review, f.student_id,
+		SELECT f.id, f.description, f.review, f.student_id, f.course_id,
 			json_build_object(
 				'ID', s.id,
-				'Name', s.name,
-				'Email', s.email
+				'FullName', s.full_name,
+				'email', s.email
 			) as student
 		FROM feedbacks f
 		LEFT JOIN students s ON f.student_id = s.id`
 
+	countFeedbacksQuery = `
+		SELECT COUNT(*) FROM feedbacks f`
+
 	getFeedbacksByStudentQuery = `
-		SELECT f.id, f.description, f.review, f.student_id,
+		SELECT f.id, f.description, f.review, f.student_id, f.course_id,
 			json_build_object(
 				'ID', s.id,
-				'Name', s.name,
-				'Email', s.email
+				'FullName', s.full_name,
+				'email', s.email
 			) as student
 		FROM feedbacks f
 		LEFT JOIN students s ON f.student_id = s.id
 		WHERE f.student_id = $1`
 
+	getFeedbacksByCourseQuery = `
+		SELECT f.id, f.description, f.review, f.student_id, f.course_id,
+			json_build_object(
+				'ID', s.id,
+				'FullName', s.full_name,
+				'email', s.email
+			) as student
+		FROM feedbacks f
+		LEFT JOIN students s ON f.student_id = s.id
+		WHERE f.course_id = $1
+		ORDER BY f.id DESC
+		LIMIT $2 OFFSET $3`
+
+	countCourseFeedbacksQuery = `
+		SELECT COUNT(*) FROM feedbacks WHERE course_id = $1`
+
 	updateFeedbackQuery = `
 		UPDATE feedbacks 
-		SET description = $1, review = $2, student_id = $3 
-		WHERE id = $4`
+		SET description = $1, review = $2, student_id = $3, course_id = $4 
+		WHERE id = $5`
 
 	deleteFeedbackQuery = `
 		DELETE FROM feedbacks WHERE id = $1`
 )
 
+// FeedbackStudent is the part of a student's profile shown with their feedback
+type FeedbackStudent struct {
+	ID       uint   `json:"ID"`
+	FullName string `json:"FullName"`
+	Email    string `json:"email"`
+}
+
+// FeedbackDetails is a feedback along with the student who wrote it, null when
+// the student is gone
+type FeedbackDetails struct {
+	models.Feedback
+	Student *FeedbackStudent `json:"Student"`
+}
+
+// CourseFeedbacksRequest selects the course whose feedbacks to list
+type CourseFeedbacksRequest struct {
+	CourseID uint `json:"course_id"`
+}
+
 type FeedbackController struct {
 	db *sql.DB
 }
@@ -67,6 +107,19 @@ func NewFeedbackController(db *sql.DB) *FeedbackController {
 	return &FeedbackController{db: db}
 }
 
+// parseReview reads an optional review bound from the query string, nil when absent
+func parseReview(c *gin.Context, name string) (*int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	review, err := strconv.Atoi(raw)
+	if err != nil || review < 1 || review > 5 {
+		return nil, fmt.Errorf("%s must be an integer between 1 and 5", name)
+	}
+	return &review, nil
+}
+
 func (h *FeedbackController) validateFeedback(feedback *models.Feedback) error {
 	if feedback.Description == "" {
 		return errors.New("description is required")
@@ -77,9 +130,44 @@ func (h *FeedbackController) validateFeedback(feedback *models.Feedback) error {
 	if feedback.StudentID <= 0 {
 		return errors.New("valid student ID is required")
 	}
+	// The course is optional, general feedback leaves it out
+	if feedback.CourseID != nil && *feedback.CourseID == 0 {
+		return errors.New("course ID must be valid when provided")
+	}
 	return nil
 }
 
+// checkFeedbackCourse reports whether the feedback's course exists, always
+// true for general feedback
+func (h *FeedbackController) checkFeedbackCourse(ctx context.Context, feedback *models.Feedback) (bool, error) {
+	if feedback.CourseID == nil {
+		return true, nil
+	}
+	var exists bool
+	err := h.db.QueryRowContext(ctx, courseExistsQuery, *feedback.CourseID).Scan(&exists)
+	return exists, err
+}
+
+// scanFeedbacks reads feedback rows along with their joined student
+func scanFeedbacks(rows *sql.Rows) ([]FeedbackDetails, error) {
+	feedbacks := []FeedbackDetails{}
+	for rows.Next() {
+		var feedback FeedbackDetails
+		var studentJSON []byte
+		if err := rows.Scan(
+			&feedback.ID, &feedback.Description, &feedback.Review,
+			&feedback.StudentID, &feedback.CourseID, &studentJSON,
+		); err != nil {
+			return nil, err
+		}
+		if err := decodeJoined(studentJSON, &feedback.Student); err != nil {
+			return nil, err
+		}
+		feedbacks = append(feedbacks, feedback)
+	}
+	return feedbacks, rows.Err()
+}
+
 // @Summary Create new feedback
 // @Description Create a new feedback in the system
 // @Tags feedbacks
@@ -119,12 +207,22 @@ func (h *FeedbackController) CreateFeedback(c *gin.Context) {
 		return
 	}
 
+	exists, err = h.checkFeedbackCourse(ctx, &feedback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
 	err = h.db.QueryRowContext(ctx, createFeedbackQuery,
 		feedback.Description, feedback.Review,
-		feedback.StudentID).Scan(&feedback.ID)
+		feedback.StudentID, feedback.CourseID).Scan(&feedback.ID)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create feedback"})
+		respondDBError(c, err, "Failed to create feedback")
 		return
 	}
 
@@ -136,8 +234,8 @@ func (h *FeedbackController) CreateFeedback(c *gin.Context) {
 // @Tags feedbacks
 // @Accept json
 // @Produce json
-// @Param id body int true "Feedback ID"
-// @Success 200 {object} models.Feedback
+// @Param request body IDRequest true "Feedback ID"
+// @Success 200 {object} FeedbackDetails
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
@@ -147,17 +245,16 @@ func (h *FeedbackController) GetFeedback(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
-	var feedback models.Feedback
+	var feedback FeedbackDetails
 	var studentJSON []byte
-	err = h.db.QueryRowContext(ctx, getFeedbackQuery, id).Scan(
+	err := h.db.QueryRowContext(ctx, getFeedbackQuery, id).Scan(
 		&feedback.ID, &feedback.Description, &feedback.Review,
-		&feedback.StudentID, &studentJSON,
+		&feedback.StudentID, &feedback.CourseID, &studentJSON,
 	)
 
 	if err == sql.ErrNoRows {
@@ -169,49 +266,96 @@ func (h *FeedbackController) GetFeedback(c *gin.Context) {
 		return
 	}
 
+	if err := decodeJoined(studentJSON, &feedback.Student); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process feedback"})
+		return
+	}
+
 	c.JSON(http.StatusOK, feedback)
 }
 
 // @Summary Get all feedbacks
-// @Description Retrieve all feedbacks from the database
+// @Description Retrieve a page of feedbacks, newest first, optionally limited to a review range
 // @Tags feedbacks
 // @Accept json
 // @Produce json
-// @Success 200 {array} models.Feedback
+// @Param min_review query int false "Only feedbacks reviewed at least this much (1-5)"
+// @Param max_review query int false "Only feedbacks reviewed at most this much (1-5)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Number of feedbacks per page (max 100)"
+// @Success 200 {object} PagedResponse{data=[]FeedbackDetails}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /feedbacks/all [get]
-// GetAllFeedbacks retrieves all feedbacks
+// GetAllFeedbacks retrieves a page of feedbacks
 func (h *FeedbackController) GetAllFeedbacks(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	rows, err := h.db.QueryContext(ctx, getAllFeedbacksQuery)
+	page, err := parsePagination(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve feedbacks"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer rows.Close()
 
-	var feedbacks []models.Feedback
-	for rows.Next() {
-		var feedback models.Feedback
-		var studentJSON []byte
-		if err := rows.Scan(
-			&feedback.ID, &feedback.Description, &feedback.Review,
-			&feedback.StudentID, &studentJSON,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process feedbacks"})
-			return
-		}
-		feedbacks = append(feedbacks, feedback)
+	minReview, err := parseReview(c, "min_review")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	maxReview, err := parseReview(c, "max_review")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if minReview != nil && maxReview != nil && *minReview > *maxReview {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_review cannot be greater than max_review"})
+		return
+	}
+
+	var conditions []string
+	var args []interface{}
+	if minReview != nil {
+		args = append(args, *minReview)
+		conditions = append(conditions, fmt.Sprintf("f.review >= $%d", len(args)))
+	}
+	if maxReview != nil {
+		args = append(args, *maxReview)
+		conditions = append(conditions, fmt.Sprintf("f.review <= $%d", len(args)))
 	}
 
-	if err = rows.Err(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing feedbacks"})
+	query := getAllFeedbacksQuery
+	countQuery := countFeedbacksQuery
+	if len(conditions) > 0 {
+		where := " WHERE " + strings.Join(conditions, " AND ")
+		query += where
+		countQuery += where
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count feedbacks"})
 		return
 	}
 
-	c.JSON(http.StatusOK, feedbacks)
+	// Feedbacks carry no timestamp, the serial ID follows creation order
+	args = append(args, page.PageSize, page.Offset())
+	query += fmt.Sprintf(" ORDER BY f.id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := h.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve feedbacks"})
+		return
+	}
+	defer rows.Close()
+
+	feedbacks, err := scanFeedbacks(rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process feedbacks"})
+		return
+	}
+
+	respond(c, http.StatusOK, PagedResponse{Data: feedbacks, Pagination: page.Meta(total)})
 }
 
 // @Summary Get feedbacks by student
@@ -220,7 +364,7 @@ func (h *FeedbackController) GetAllFeedbacks(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param studentId body int true "Student ID"
-// @Success 200 {array} models.Feedback
+// @Success 200 {array} FeedbackDetails
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
@@ -255,26 +399,79 @@ func (h *FeedbackController) GetFeedbacksByStudent(c *gin.Context) {
 	}
 	defer rows.Close()
 
-	var feedbacks []models.Feedback
-	for rows.Next() {
-		var feedback models.Feedback
-		var studentJSON []byte
-		if err := rows.Scan(
-			&feedback.ID, &feedback.Description, &feedback.Review,
-			&feedback.StudentID, &studentJSON,
-		); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process feedbacks"})
-			return
-		}
-		feedbacks = append(feedbacks, feedback)
+	feedbacks, err := scanFeedbacks(rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process feedbacks"})
+		return
 	}
 
-	if err = rows.Err(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing feedbacks"})
+	c.JSON(http.StatusOK, feedbacks)
+}
+
+// @Summary Get feedbacks by course
+// @Description Retrieve a page of the feedbacks left on a course, newest first
+// @Tags feedbacks
+// @Accept json
+// @Produce json
+// @Param request body CourseFeedbacksRequest true "Course ID"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Number of feedbacks per page (max 100)"
+// @Success 200 {object} PagedResponse{data=[]FeedbackDetails}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /feedbacks/getFeedbacksByCourse [post]
+// GetFeedbacksByCourse retrieves a page of a course's feedbacks
+func (h *FeedbackController) GetFeedbacksByCourse(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req CourseFeedbacksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
 		return
 	}
 
-	c.JSON(http.StatusOK, feedbacks)
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var exists bool
+	if err := h.db.QueryRowContext(ctx, courseExistsQuery, req.CourseID).Scan(&exists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countCourseFeedbacksQuery, req.CourseID).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count feedbacks"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getFeedbacksByCourseQuery, req.CourseID, page.PageSize, page.Offset())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve feedbacks"})
+		return
+	}
+	defer rows.Close()
+
+	feedbacks, err := scanFeedbacks(rows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process feedbacks"})
+		return
+	}
+
+	respond(c, http.StatusOK, PagedResponse{Data: feedbacks, Pagination: page.Meta(total)})
 }
 
 // @Summary Update feedback
@@ -293,17 +490,16 @@ func (h *FeedbackController) UpdateFeedback(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var feedback models.Feedback
 	if err := c.ShouldBindJSON(&feedback); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if feedback.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := feedback.ID
 
 	if err := h.validateFeedback(&feedback); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -312,7 +508,7 @@ func (h *FeedbackController) UpdateFeedback(c *gin.Context) {
 
 	// Verify student exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM students WHERE id = $1)", feedback.StudentID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM students WHERE id = $1)", feedback.StudentID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
 		return
@@ -322,12 +518,40 @@ func (h *FeedbackController) UpdateFeedback(c *gin.Context) {
 		return
 	}
 
+	exists, err = h.checkFeedbackCourse(ctx, &feedback)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Feedback
+	var studentJSON []byte
+	if wantsDiff(c) {
+		err = h.db.QueryRowContext(ctx, getFeedbackQuery, id).Scan(
+			&before.ID, &before.Description, &before.Review,
+			&before.StudentID, &before.CourseID, &studentJSON,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Feedback not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve feedback"})
+			return
+		}
+	}
+
 	result, err := h.db.ExecContext(ctx, updateFeedbackQuery,
 		feedback.Description, feedback.Review,
-		feedback.StudentID, id)
+		feedback.StudentID, feedback.CourseID, id)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update feedback"})
+		respondDBError(c, err, "Failed to update feedback")
 		return
 	}
 
@@ -341,8 +565,7 @@ func (h *FeedbackController) UpdateFeedback(c *gin.Context) {
 		return
 	}
 
-	feedback.ID = uint(id)
-	c.JSON(http.StatusOK, feedback)
+	respondUpdated(c, before, feedback)
 }
 
 // @Summary Delete feedback
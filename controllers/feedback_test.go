@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetFeedbackEmbedsStudentWithoutPassword(t *testing.T) {
+	db, f := newFakeDB(t)
+	// Even if the join ever picked up the password, the response must not carry it
+	f.expect(getFeedbackQuery, 4).returns(row(4, "Great course", 5, 5, 7,
+		[]byte(`{"ID": 5, "FullName": "Alice", "email": "alice@example.com", "Password": "$2a$14$hash"}`)))
+
+	w := serve(t, NewFeedbackController(db).GetFeedback, testRequest{body: `{"id": 4}`})
+	expectStatus(t, w, http.StatusOK)
+
+	got := responseKeys(t, w.Body.Bytes())
+	student, ok := got["Student"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("response %v has no embedded student", got)
+	}
+	if student["FullName"] != "Alice" || student["email"] != "alice@example.com" {
+		t.Errorf("student = %v, want Alice's name and email", student)
+	}
+	for _, key := range []string{"Password", "password"} {
+		if _, ok := student[key]; ok {
+			t.Errorf("embedded student %v has a %s field", student, key)
+		}
+	}
+}
+
+func TestGetFeedbackWithoutStudent(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getFeedbackQuery, 4).returns(row(4, "Great course", 5, 5, 7, nil))
+
+	w := serve(t, NewFeedbackController(db).GetFeedback, testRequest{body: `{"id": 4}`})
+	expectStatus(t, w, http.StatusOK)
+
+	if got := responseKeys(t, w.Body.Bytes()); got["Student"] != nil {
+		t.Errorf("student = %v, want null", got["Student"])
+	}
+}
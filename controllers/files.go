@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cuddest/dz-skills/config"
+)
+
+// removeUploadedFile deletes a stored file once the row referencing it is
+// gone. Only paths inside the upload directory are touched, so URLs and
+// anything else a client stored are left alone, and a file that is already
+// missing is not an error.
+func removeUploadedFile(path string) {
+	if path == "" || strings.Contains(path, "://") {
+		return
+	}
+
+	dir, err := filepath.Abs(config.UploadDir())
+	if err != nil {
+		log.Printf("Failed to resolve the upload directory: %v", err)
+		return
+	}
+	target, err := filepath.Abs(path)
+	if err != nil || !strings.HasPrefix(target, dir+string(filepath.Separator)) {
+		return
+	}
+
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to remove uploaded file %s: %v", target, err)
+	}
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// uploadDir points UPLOAD_DIR at a fresh directory for the test
+func uploadDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("UPLOAD_DIR", dir)
+	return dir
+}
+
+// writeUpload creates an uploaded file and returns its path
+func writeUpload(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRemoveUploadedFile(t *testing.T) {
+	dir := uploadDir(t)
+	path := writeUpload(t, dir, "go.png")
+
+	removeUploadedFile(path)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("%s still exists after removal", path)
+	}
+
+	// A file that is already gone is not an error
+	removeUploadedFile(path)
+}
+
+func TestRemoveUploadedFileStaysInUploadDir(t *testing.T) {
+	uploadDir(t)
+	outside := writeUpload(t, t.TempDir(), "keep.png")
+
+	for _, path := range []string{outside, "https://cdn.dzskills.com/go.png", ""} {
+		removeUploadedFile(path)
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the upload directory was touched: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthPingTimeout bounds the database ping so a load balancer probe fails fast
+const healthPingTimeout = 2 * time.Second
+
+// HealthStatus is the response of the health check
+type HealthStatus struct {
+	Status string `json:"status"`
+}
+
+// HealthController reports whether the server can reach its database
+type HealthController struct {
+	db *sql.DB
+}
+
+func NewHealthController(db *sql.DB) *HealthController {
+	return &HealthController{db: db}
+}
+
+// @Summary Health check
+// @Description Ping the database, for load balancers and orchestration. No authentication required.
+// @Tags version
+// @Produce json
+// @Success 200 {object} HealthStatus
+// @Failure 503 {object} HealthStatus
+// @Router /health [get]
+func (h *HealthController) Health(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), healthPingTimeout)
+	defer cancel()
+
+	if err := h.db.PingContext(ctx); err != nil {
+		c.JSON(http.StatusServiceUnavailable, HealthStatus{Status: "degraded"})
+		return
+	}
+	c.JSON(http.StatusOK, HealthStatus{Status: "ok"})
+}
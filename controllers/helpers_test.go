@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testRequest builds a handler call with a JSON body, body may be a raw
+// string or any value to marshal
+type testRequest struct {
+	method  string
+	body    interface{}
+	query   string
+	headers map[string]string
+	claims  *auth.JWTClaim
+}
+
+// serve runs handler on req and returns the recorded response
+func serve(t *testing.T, handler gin.HandlerFunc, req testRequest) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var body []byte
+	switch b := req.body.(type) {
+	case nil:
+	case string:
+		body = []byte(b)
+	default:
+		var err error
+		if body, err = json.Marshal(b); err != nil {
+			t.Fatalf("marshal body: %v", err)
+		}
+	}
+	method := req.method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/?"+req.query, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	for name, value := range req.headers {
+		c.Request.Header.Set(name, value)
+	}
+	if req.claims != nil {
+		c.Set(auth.ClaimsKey, req.claims)
+	}
+
+	handler(c)
+	return w
+}
+
+func studentClaims(username string) *auth.JWTClaim {
+	return &auth.JWTClaim{Username: username, Role: auth.RoleStudent}
+}
+
+func teacherClaims(username string) *auth.JWTClaim {
+	return &auth.JWTClaim{Username: username, Role: auth.RoleTeacher}
+}
+
+func adminClaims(username string) *auth.JWTClaim {
+	return &auth.JWTClaim{Username: username, Role: auth.RoleAdmin}
+}
+
+// expectStatus fails the test when the response does not have status
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d, body: %s", w.Code, status, w.Body.String())
+	}
+}
+
+// decodeBody unmarshals the JSON response into v
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+}
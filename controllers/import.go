@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cuddest/dz-skills/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxImportFileSize caps the size of an uploaded question bank
+	maxImportFileSize = 1 << 20
+
+	// maxImportExamQuizzes caps how many rows a single CSV import may hold
+	maxImportExamQuizzes = 500
+)
+
+// examQuizzCSVColumns is the column order expected in a question bank
+var examQuizzCSVColumns = []string{"question", "option1", "option2", "option3", "option4", "answer"}
+
+// RejectedRow is a CSV line that was not imported and why
+type RejectedRow struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ImportResult sums up a CSV import
+type ImportResult struct {
+	Imported int                `json:"imported"`
+	Quizzes  []models.ExamQuizz `json:"quizzes"`
+	Rejected []RejectedRow      `json:"rejected"`
+}
+
+// parseExamQuizzRecord turns one CSV record into a quiz for examID
+func parseExamQuizzRecord(record []string, examID uint) (models.ExamQuizz, error) {
+	if len(record) != len(examQuizzCSVColumns) {
+		return models.ExamQuizz{}, fmt.Errorf("expected %d columns, got %d", len(examQuizzCSVColumns), len(record))
+	}
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+
+	answer, err := strconv.ParseUint(record[5], 10, 32)
+	if err != nil || answer < 1 || answer > 4 {
+		return models.ExamQuizz{}, errors.New("answer must be the number of an option, 1 to 4")
+	}
+	if record[answer] == "" {
+		return models.ExamQuizz{}, fmt.Errorf("answer points to option%d, which is empty", answer)
+	}
+
+	return models.ExamQuizz{
+		Question: record[0],
+		Option1:  record[1],
+		Option2:  record[2],
+		Option3:  record[3],
+		Option4:  record[4],
+		Answer:   uint(answer),
+		ExamID:   examID,
+	}, nil
+}
+
+// @Summary Import exam quizzes from CSV
+// @Description Upload a question bank as CSV with the columns question, option1, option2, option3, option4, answer (1 to 4). A header row is optional. Valid rows are created in a single transaction; invalid rows are skipped and reported with their line number.
+// @Tags examquizzes
+// @Accept multipart/form-data
+// @Produce json
+// @Param exam_id formData int true "Exam ID"
+// @Param file formData file true "CSV question bank"
+// @Success 201 {object} ImportResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /examquizzes/importCSV [post]
+func (h *ExamQuizzController) ImportExamQuizzesCSV(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	// Leave some room for the other form fields and the multipart framing
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize+(64<<10))
+
+	examID, err := strconv.ParseUint(c.PostForm("exam_id"), 10, 32)
+	if err != nil || examID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid exam ID is required"})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a CSV file is required"})
+		return
+	}
+	if header.Size > maxImportFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("the file must not exceed %d bytes", maxImportFileSize)})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	// Verify exam exists
+	var exists bool
+	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM exams WHERE id = $1)", examID).Scan(&exists)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify exam"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam not found"})
+		return
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // column counts are checked per row so they can be reported
+	result := ImportResult{Quizzes: []models.ExamQuizz{}, Rejected: []RejectedRow{}}
+	first := true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// A malformed quote leaves the reader unable to find the next row
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid CSV: %s", err.Error())})
+			return
+		}
+		line, _ := reader.FieldPos(0)
+
+		// The first row is a header when it names the first column
+		isHeader := first && strings.EqualFold(strings.TrimSpace(record[0]), examQuizzCSVColumns[0])
+		first = false
+		if isHeader {
+			continue
+		}
+		if len(result.Quizzes)+len(result.Rejected) >= maxImportExamQuizzes {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d quizzes can be imported at once", maxImportExamQuizzes)})
+			return
+		}
+
+		quizz, err := parseExamQuizzRecord(record, uint(examID))
+		if err == nil {
+			err = h.validateExamQuizz(&quizz)
+		}
+		if err != nil {
+			result.Rejected = append(result.Rejected, RejectedRow{Line: line, Reason: err.Error()})
+			continue
+		}
+		result.Quizzes = append(result.Quizzes, quizz)
+	}
+
+	if len(result.Quizzes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no valid quiz found in the file", "rejected": result.Rejected})
+		return
+	}
+
+	if err := h.createExamQuizzes(ctx, result.Quizzes); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import exam quizzes"})
+		return
+	}
+	result.Imported = len(result.Quizzes)
+
+	c.JSON(http.StatusCreated, result)
+}
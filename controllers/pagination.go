@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+
+	// maxOffset bounds how deep offset pagination may go, beyond it the
+	// database would scan and discard too many rows
+	maxOffset = 10000
+)
+
+// Pagination holds the page requested through the page and page_size query parameters
+type Pagination struct {
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+}
+
+// Offset returns the number of rows to skip for the requested page
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+// PageMeta describes where a page sits in the full listing
+type PageMeta struct {
+	Page       int  `json:"page"`
+	PageSize   int  `json:"page_size"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
+}
+
+// Meta computes the metadata of the page given the total number of items,
+// e.g. 25 items at page_size 10 make 3 pages
+func (p Pagination) Meta(total int) PageMeta {
+	totalPages := 0
+	if p.PageSize > 0 {
+		totalPages = (total + p.PageSize - 1) / p.PageSize
+	}
+	return PageMeta{
+		Page:       p.Page,
+		PageSize:   p.PageSize,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    p.Page < totalPages,
+		HasPrev:    p.Page > 1,
+	}
+}
+
+// PagedResponse is the envelope of listings that report pagination metadata
+type PagedResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination PageMeta    `json:"pagination"`
+}
+
+// parsePagination reads page and page_size from the query string, falling back to sane defaults
+func parsePagination(c *gin.Context) (Pagination, error) {
+	p := Pagination{Page: 1, PageSize: defaultPageSize}
+
+	if raw := c.Query("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			return p, errors.New("page must be a positive integer")
+		}
+		p.Page = page
+	}
+
+	if raw := c.Query("page_size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 {
+			return p, errors.New("page_size must be a positive integer")
+		}
+		if size > maxPageSize {
+			size = maxPageSize
+		}
+		p.PageSize = size
+	}
+
+	// Compare pages rather than offsets so a huge page cannot overflow
+	if maxPage := maxOffset/p.PageSize + 1; p.Page > maxPage {
+		return p, fmt.Errorf("page must be at most %d for page_size %d", maxPage, p.PageSize)
+	}
+
+	return p, nil
+}
+
+// parseSort turns the sort query parameter ("field" or "-field" for descending)
+// into an ORDER BY clause. Only fields listed in allowed, which maps the public
+// name to its column, are accepted so the clause is safe to concatenate.
+func parseSort(c *gin.Context, allowed map[string]string, fallback string) (string, error) {
+	raw := c.Query("sort")
+	if raw == "" {
+		return fallback, nil
+	}
+
+	direction := "ASC"
+	if strings.HasPrefix(raw, "-") {
+		direction = "DESC"
+		raw = raw[1:]
+	}
+
+	column, ok := allowed[raw]
+	if !ok {
+		return "", errors.New("unsupported sort field: " + raw)
+	}
+	return column + " " + direction, nil
+}
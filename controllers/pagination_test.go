@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPaginationMeta(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     Pagination
+		total int
+		want  PageMeta
+	}{
+		{
+			name: "no items",
+			p:    Pagination{Page: 1, PageSize: 10},
+			want: PageMeta{Page: 1, PageSize: 10, Total: 0, TotalPages: 0},
+		},
+		{
+			name:  "exact multiple first page",
+			p:     Pagination{Page: 1, PageSize: 10},
+			total: 30,
+			want:  PageMeta{Page: 1, PageSize: 10, Total: 30, TotalPages: 3, HasNext: true},
+		},
+		{
+			name:  "exact multiple last page",
+			p:     Pagination{Page: 3, PageSize: 10},
+			total: 30,
+			want:  PageMeta{Page: 3, PageSize: 10, Total: 30, TotalPages: 3, HasPrev: true},
+		},
+		{
+			name:  "partial last page",
+			p:     Pagination{Page: 3, PageSize: 10},
+			total: 25,
+			want:  PageMeta{Page: 3, PageSize: 10, Total: 25, TotalPages: 3, HasPrev: true},
+		},
+		{
+			name:  "middle page",
+			p:     Pagination{Page: 2, PageSize: 10},
+			total: 25,
+			want:  PageMeta{Page: 2, PageSize: 10, Total: 25, TotalPages: 3, HasNext: true, HasPrev: true},
+		},
+		{
+			name:  "past the last page",
+			p:     Pagination{Page: 5, PageSize: 10},
+			total: 25,
+			want:  PageMeta{Page: 5, PageSize: 10, Total: 25, TotalPages: 3, HasPrev: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Meta(tt.total); got != tt.want {
+				t.Errorf("Meta(%d) = %+v, want %+v", tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginationOffset(t *testing.T) {
+	if got := (Pagination{Page: 3, PageSize: 20}).Offset(); got != 40 {
+		t.Errorf("Offset() = %d, want 40", got)
+	}
+}
+
+// queryContext returns a context for a request with the given query string
+func queryContext(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?"+query, nil)
+	return c
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    Pagination
+		wantErr bool
+	}{
+		{query: "", want: Pagination{Page: 1, PageSize: defaultPageSize}},
+		{query: "page=2&page_size=5", want: Pagination{Page: 2, PageSize: 5}},
+		{query: "page_size=1000", want: Pagination{Page: 1, PageSize: maxPageSize}},
+		{query: "page=0", wantErr: true},
+		{query: "page=two", wantErr: true},
+		{query: "page_size=-1", wantErr: true},
+		{query: "page=10000&page_size=100", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := parsePagination(queryContext(tt.query))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePagination(%q) = %+v, want an error", tt.query, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("parsePagination(%q) = %+v, %v, want %+v", tt.query, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	allowed := map[string]string{"name": "c.name"}
+	tests := []struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{query: "", want: "c.id ASC"},
+		{query: "sort=name", want: "c.name ASC"},
+		{query: "sort=-name", want: "c.name DESC"},
+		{query: "sort=password", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := parseSort(queryContext(tt.query), allowed, "c.id ASC")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseSort(%q) = %q, %v, want %q", tt.query, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePaginationRejectsAbsurdPages(t *testing.T) {
+	tests := []struct {
+		query string
+		error string
+	}{
+		{"page=9223372036854775807", "page must be at most 501 for page_size 20"},
+		{"page=9223372036854775807&page_size=100", "page must be at most 101 for page_size 100"},
+		{"page=99999999999999999999", "page must be a positive integer"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := parsePagination(queryContext(tt.query))
+			if err == nil || err.Error() != tt.error {
+				t.Errorf("parsePagination(%q) error = %v, want %q", tt.query, err, tt.error)
+			}
+		})
+	}
+
+	// The deepest allowed page stays within maxOffset
+	p, err := parsePagination(queryContext("page=501"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Offset() > maxOffset {
+		t.Errorf("offset %d exceeds %d", p.Offset(), maxOffset)
+	}
+}
+
+func TestAbsurdPageIsABadRequest(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	w := serve(t, NewQuestionController(db).GetAllQuestions, testRequest{
+		method: http.MethodGet,
+		query:  "page=9223372036854775807",
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
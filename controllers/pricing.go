@@ -0,0 +1,190 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	lockCoursePricingQuery = `
+		SELECT id, pricing, teacher_id
+		FROM courses WHERE id = ANY($1) AND deleted_at IS NULL
+		ORDER BY id
+		FOR UPDATE`
+
+	updateCoursePricingQuery = `
+		UPDATE courses SET pricing = $1 WHERE id = $2`
+)
+
+var (
+	errCoursesMissing = errors.New("courses not found")
+	errInvalidPrices  = errors.New("prices would become invalid")
+)
+
+// BulkPricingRequest changes the price of several courses at once, either by
+// Percent (-20 for a 20% discount) or by a fixed Amount, never both
+type BulkPricingRequest struct {
+	IDs     []uint   `json:"ids"`
+	Percent *float64 `json:"percent"`
+	Amount  *float64 `json:"amount"`
+}
+
+// PriceChange is the old and new price of a course updated by BulkUpdatePricing
+type PriceChange struct {
+	CourseID   uint   `json:"course_id"`
+	OldPricing string `json:"old_pricing"`
+	NewPricing string `json:"pricing"`
+}
+
+// apply returns price changed by the request, rounded to cents
+func (r BulkPricingRequest) apply(price float64) float64 {
+	if r.Percent != nil {
+		price *= 1 + *r.Percent/100
+	} else {
+		price += *r.Amount
+	}
+	return math.Round(price*100) / 100
+}
+
+// @Summary Bulk update course pricing
+// @Description Change the price of several courses in one transaction, by a percentage or a fixed amount. Teachers can only reprice their own courses, admins any course. Nothing is updated when a course is missing, not owned, or would get a negative price.
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param request body BulkPricingRequest true "Course IDs and the percentage or amount to apply"
+// @Success 200 {array} PriceChange
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/bulkPricing [post]
+func (h *CourseController) BulkUpdatePricing(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req BulkPricingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (req.Percent == nil) == (req.Amount == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "exactly one of percent or amount is required"})
+		return
+	}
+	ids, ok := checkIDs(c, req.IDs)
+	if !ok {
+		return
+	}
+
+	// Admins may reprice any course, teachers only the ones they own
+	claims, _ := auth.ClaimsFromContext(c)
+	isAdmin := claims != nil && claims.Role == auth.RoleAdmin
+	var teacherID uint
+	if !isAdmin {
+		var isTeacher bool
+		var err error
+		teacherID, isTeacher, err = authenticatedTeacherID(ctx, h.db, c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+			return
+		}
+		if !isTeacher {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only teachers can change course pricing"})
+			return
+		}
+	}
+
+	changes := []PriceChange{}
+	var notOwned, invalid []uint
+	var missing []int64
+	err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, lockCoursePricingQuery, ids)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		found := make(map[int64]bool, len(ids))
+		for rows.Next() {
+			var change PriceChange
+			var ownerID sql.NullInt64
+			if err := rows.Scan(&change.CourseID, &change.OldPricing, &ownerID); err != nil {
+				return err
+			}
+			found[int64(change.CourseID)] = true
+
+			if !isAdmin && (!ownerID.Valid || uint(ownerID.Int64) != teacherID) {
+				notOwned = append(notOwned, change.CourseID)
+				continue
+			}
+
+			// Courses created before pricing was validated may hold something else
+			price, err := strconv.ParseFloat(change.OldPricing, 64)
+			if validatePricing(change.OldPricing) != nil || err != nil {
+				invalid = append(invalid, change.CourseID)
+				continue
+			}
+			newPrice := req.apply(price)
+			if newPrice < 0 || math.IsNaN(newPrice) || math.IsInf(newPrice, 0) {
+				invalid = append(invalid, change.CourseID)
+				continue
+			}
+			change.NewPricing = strconv.FormatFloat(newPrice, 'f', -1, 64)
+			changes = append(changes, change)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		rows.Close()
+
+		for _, id := range ids {
+			if !found[id] {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			return errCoursesMissing
+		}
+		if len(notOwned) > 0 {
+			return errNotOwned
+		}
+		if len(invalid) > 0 {
+			return errInvalidPrices
+		}
+
+		for _, change := range changes {
+			if _, err := tx.ExecContext(ctx, updateCoursePricingQuery, change.NewPricing, change.CourseID); err != nil {
+				return err
+			}
+		}
+		details := fmt.Sprintf("courses %v repriced", ids)
+		if req.Percent != nil {
+			details += fmt.Sprintf(" by %g%%", *req.Percent)
+		} else {
+			details += fmt.Sprintf(" by %g", *req.Amount)
+		}
+		return recordAudit(ctx, tx, c, "course.bulk_pricing", details)
+	})
+	switch err {
+	case nil:
+		respond(c, http.StatusOK, changes)
+	case errCoursesMissing:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Some courses were not found, no price was changed", "missing": missing})
+	case errNotOwned:
+		c.JSON(http.StatusForbidden, gin.H{"error": "Some courses are not yours, no price was changed", "not_owned": notOwned})
+	case errInvalidPrices:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Some courses would not have a valid non-negative price, no price was changed", "invalid": invalid})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pricing"})
+	}
+}
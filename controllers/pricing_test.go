@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBulkUpdatePricingPercentDiscount(t *testing.T) {
+	at := time.Date(2026, 11, 27, 8, 0, 0, 0, time.UTC)
+	setClock(t, at)
+
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(lockCoursePricingQuery, []int64{7, 8}).returns(
+		row(7, "50", 3),
+		row(8, "19.99", 3),
+	)
+	f.expect(updateCoursePricingQuery, "40", 7).affects(1)
+	f.expect(updateCoursePricingQuery, "15.99", 8).affects(1)
+	f.expect(createAuditLogQuery, "bob", "course.bulk_pricing", "courses [7 8] repriced by -20%", at).affects(1)
+
+	w := serve(t, NewCourseController(db).BulkUpdatePricing, testRequest{
+		body:   `{"ids": [7, 8], "percent": -20}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var changes []PriceChange
+	decodeBody(t, w, &changes)
+	want := []PriceChange{
+		{CourseID: 7, OldPricing: "50", NewPricing: "40"},
+		{CourseID: 8, OldPricing: "19.99", NewPricing: "15.99"},
+	}
+	if len(changes) != len(want) || changes[0] != want[0] || changes[1] != want[1] {
+		t.Errorf("changes = %+v, want %+v", changes, want)
+	}
+	if f.commits != 1 {
+		t.Errorf("commits = %d, want 1", f.commits)
+	}
+}
+
+func TestBulkUpdatePricingRejectsNegativePrice(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(lockCoursePricingQuery, []int64{7, 8}).returns(
+		row(7, "50", 3),
+		row(8, "20", 3),
+	)
+
+	// No update is scripted, the fake fails the test if any price changes
+	w := serve(t, NewCourseController(db).BulkUpdatePricing, testRequest{
+		body:   `{"ids": [7, 8], "amount": -30}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	var got struct {
+		Invalid []uint `json:"invalid"`
+	}
+	decodeBody(t, w, &got)
+	if len(got.Invalid) != 1 || got.Invalid[0] != 8 {
+		t.Errorf("invalid = %v, want [8]", got.Invalid)
+	}
+	if f.commits != 0 || f.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want the transaction rolled back", f.commits, f.rollbacks)
+	}
+}
+
+func TestBulkUpdatePricingRequiresOneChange(t *testing.T) {
+	for _, body := range []string{`{"ids": [7]}`, `{"ids": [7], "percent": -10, "amount": -5}`} {
+		db, _ := newFakeDB(t)
+		w := serve(t, NewCourseController(db).BulkUpdatePricing, testRequest{body: body, claims: teacherClaims("bob")})
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+}
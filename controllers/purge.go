@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	lockPurgeCourseQuery = `
+		SELECT teacher_id, image, deleted_at IS NOT NULL
+		FROM courses WHERE id = $1
+		FOR UPDATE`
+
+	// Enrollments are the only course children without ON DELETE CASCADE
+	deleteCourseEnrollmentsQuery = `
+		DELETE FROM student_courses WHERE course_id = $1`
+
+	purgeCourseQuery = `
+		DELETE FROM courses WHERE id = $1`
+)
+
+var errCourseNotDeleted = errors.New("course is not soft deleted")
+
+// PurgeCourseRequest names the soft deleted course to remove for good,
+// Confirm must be true so a stray call cannot destroy anything
+type PurgeCourseRequest struct {
+	ID      uint `json:"id"`
+	Confirm bool `json:"confirm"`
+}
+
+// @Summary Permanently delete a course
+// @Description Remove a soft deleted course with its content, enrollments and image for good. The course must have been deleted with DeleteCourse first and confirm must be true. Teachers can only purge their own courses, admins any course. The purge is recorded in the audit log.
+// @Tags courses
+// @Accept json
+// @Produce json
+// @Param request body PurgeCourseRequest true "Course ID and confirmation"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /Courses/purge [delete]
+func (h *CourseController) PurgeCourse(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req PurgeCourseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+	if !req.Confirm {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "confirm must be true to permanently delete a course"})
+		return
+	}
+
+	// Admins may purge any course, teachers only the ones they own
+	claims, _ := auth.ClaimsFromContext(c)
+	isAdmin := claims != nil && claims.Role == auth.RoleAdmin
+	var teacherID uint
+	if !isAdmin {
+		var isTeacher bool
+		var err error
+		teacherID, isTeacher, err = authenticatedTeacherID(ctx, h.db, c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+			return
+		}
+		if !isTeacher {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only teachers can purge courses"})
+			return
+		}
+	}
+
+	var image sql.NullString
+	err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+		var ownerID sql.NullInt64
+		var deleted bool
+		if err := tx.QueryRowContext(ctx, lockPurgeCourseQuery, req.ID).Scan(&ownerID, &image, &deleted); err != nil {
+			return err
+		}
+		if !isAdmin && (!ownerID.Valid || uint(ownerID.Int64) != teacherID) {
+			return errNotOwned
+		}
+		if !deleted {
+			return errCourseNotDeleted
+		}
+
+		if _, err := tx.ExecContext(ctx, deleteCourseEnrollmentsQuery, req.ID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, purgeCourseQuery, req.ID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, c, "course.purge", fmt.Sprintf("course %d permanently deleted", req.ID))
+	})
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	case errNotOwned:
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only purge your own courses"})
+		return
+	case errCourseNotDeleted:
+		c.JSON(http.StatusConflict, gin.H{"error": "Course must be deleted before it can be purged"})
+		return
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge course"})
+		return
+	}
+
+	// The file goes once the row is committed
+	removeUploadedFile(image.String)
+
+	respond(c, http.StatusOK, gin.H{"message": "Course permanently deleted"})
+}
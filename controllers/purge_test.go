@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPurgeCourseRemovesRowAndImage(t *testing.T) {
+	at := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	setClock(t, at)
+	image := writeUpload(t, uploadDir(t), "go.png")
+
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(lockPurgeCourseQuery, 7).returns(row(3, image, true))
+	f.expect(deleteCourseEnrollmentsQuery, 7).affects(2)
+	f.expect(purgeCourseQuery, 7).affects(1)
+	f.expect(createAuditLogQuery, "bob", "course.purge", "course 7 permanently deleted", at).affects(1)
+
+	w := serve(t, NewCourseController(db).PurgeCourse, testRequest{
+		method: http.MethodDelete,
+		body:   `{"id": 7, "confirm": true}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusOK)
+	if f.commits != 1 {
+		t.Errorf("commits = %d, want 1", f.commits)
+	}
+	if _, err := os.Stat(image); !os.IsNotExist(err) {
+		t.Errorf("course image %s was not removed", image)
+	}
+}
+
+func TestPurgeCourseWithMissingImage(t *testing.T) {
+	setClock(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	dir := uploadDir(t)
+
+	db, f := newFakeDB(t)
+	f.expect(lockPurgeCourseQuery, 7).returns(row(nil, dir+"/gone.png", true))
+	f.expect(deleteCourseEnrollmentsQuery, 7)
+	f.expect(purgeCourseQuery, 7).affects(1)
+	f.expect(createAuditLogQuery)
+
+	w := serve(t, NewCourseController(db).PurgeCourse, testRequest{
+		method: http.MethodDelete,
+		body:   `{"id": 7, "confirm": true}`,
+		claims: adminClaims("root"),
+	})
+	expectStatus(t, w, http.StatusOK)
+}
+
+func TestPurgeCourseKeepsImageWhenRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		owner   interface{}
+		deleted bool
+		status  int
+	}{
+		{"not owned", 4, true, http.StatusForbidden},
+		{"not soft deleted", 3, false, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := writeUpload(t, uploadDir(t), "go.png")
+			db, f := newFakeDB(t)
+			f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+			f.expect(lockPurgeCourseQuery, 7).returns(row(tt.owner, image, tt.deleted))
+
+			w := serve(t, NewCourseController(db).PurgeCourse, testRequest{
+				method: http.MethodDelete,
+				body:   `{"id": 7, "confirm": true}`,
+				claims: teacherClaims("bob"),
+			})
+			expectStatus(t, w, tt.status)
+			if _, err := os.Stat(image); err != nil {
+				t.Errorf("course image was removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestPurgeCourseNotFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(lockPurgeCourseQuery, 7)
+
+	w := serve(t, NewCourseController(db).PurgeCourse, testRequest{
+		method: http.MethodDelete,
+		body:   `{"id": 7, "confirm": true}`,
+		claims: adminClaims("root"),
+	})
+	expectStatus(t, w, http.StatusNotFound)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cuddest/dz-skills/models"
@@ -33,12 +36,65 @@ const (
 
 	deleteQuestionQuery = `
 		DELETE FROM questions WHERE id = $1`
+
+	getCourseTeacherQuery = `
+		SELECT teacher_id FROM courses WHERE id = $1 AND deleted_at IS NULL`
+
+	countCourseQuestionsQuery = `
+		SELECT COUNT(*) FROM questions WHERE course_id = $1`
+
+	// Question IDs are serial, so ordering by ID lists the oldest questions first
+	getUnansweredByCourseQuery = `
+		SELECT q.id, q.course_id, q.student_id, q.question 
+		FROM questions q
+		WHERE q.course_id = $1
+			AND NOT EXISTS (SELECT 1 FROM answers a WHERE a.question_id = q.id)
+		ORDER BY q.id ASC
+		LIMIT $2 OFFSET $3`
+
+	// Answers are counted per question first so questions without any
+	// answer still show up in the totals
+	getCourseQuestionStatsQuery = `
+		SELECT COUNT(q.id), COALESCE(SUM(a.answers), 0), COUNT(a.question_id)
+		FROM questions q
+		LEFT JOIN (
+			SELECT question_id, COUNT(*) AS answers
+			FROM answers
+			GROUP BY question_id
+		) a ON a.question_id = q.id
+		WHERE q.course_id = $1`
 )
 
+// questionSortFields maps the accepted sort values to their columns
+var questionSortFields = map[string]string{
+	"id":         "id",
+	"course_id":  "course_id",
+	"student_id": "student_id",
+}
+
 type QuestionController struct {
 	db *sql.DB
 }
 
+// UnansweredQuestionsRequest selects the course to list unanswered questions for
+type UnansweredQuestionsRequest struct {
+	CourseID uint `json:"course_id"`
+}
+
+// CourseQuestionsRequest selects the course whose questions to list
+type CourseQuestionsRequest struct {
+	CourseID uint `json:"course_id"`
+}
+
+// QuestionStats sums up the questions and answers of a course
+type QuestionStats struct {
+	CourseID          uint    `json:"course_id"`
+	Questions         int     `json:"questions"`
+	Answers           int     `json:"answers"`
+	AnsweredQuestions int     `json:"answered_questions"`
+	AnsweredRate      float64 `json:"answered_rate"` // share of questions with an answer, 0 to 1, rounded to 2 decimals
+}
+
 func NewQuestionController(db *sql.DB) *QuestionController {
 	return &QuestionController{db: db}
 }
@@ -82,26 +138,15 @@ func (h *QuestionController) CreateQuestion(c *gin.Context) {
 		return
 	}
 
-	tx, err := h.db.BeginTx(ctx, nil)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
-	defer tx.Rollback()
-
-	err = tx.QueryRowContext(ctx, createQuestionQuery,
-		question.CourseID, question.StudentID, question.Question).Scan(&question.ID)
-
+	err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, createQuestionQuery,
+			question.CourseID, question.StudentID, question.Question).Scan(&question.ID)
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create question"})
 		return
 	}
 
-	if err = tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
-	}
-
 	c.JSON(http.StatusCreated, question)
 }
 
@@ -110,7 +155,7 @@ func (h *QuestionController) CreateQuestion(c *gin.Context) {
 // @Tags questions
 // @Accept json
 // @Produce json
-// @Param id path int true "Question ID"
+// @Param request body IDRequest true "Question ID"
 // @Success 200 {object} models.Question
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -121,14 +166,13 @@ func (h *QuestionController) GetQuestion(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var question models.Question
-	err = h.db.QueryRowContext(ctx, getQuestionQuery, id).Scan(
+	err := h.db.QueryRowContext(ctx, getQuestionQuery, id).Scan(
 		&question.ID, &question.CourseID, &question.StudentID, &question.Question,
 	)
 
@@ -145,11 +189,17 @@ func (h *QuestionController) GetQuestion(c *gin.Context) {
 }
 
 // @Summary Get all questions
-// @Description Retrieve a list of all questions
+// @Description Retrieve a page of questions, optionally filtered by course and student
 // @Tags questions
 // @Accept json
 // @Produce json
+// @Param course_id query int false "Only questions for this course"
+// @Param student_id query int false "Only questions asked by this student"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of questions per page (max 100)"
+// @Param sort query string false "Sort field (id, course_id, student_id), prefix with - for descending"
 // @Success 200 {array} models.Question
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Security ApiKeyAuth
 // @Router /questions/all [get]
@@ -157,7 +207,47 @@ func (h *QuestionController) GetAllQuestions(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	rows, err := h.db.QueryContext(ctx, getAllQuestionsQuery)
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := parseSort(c, questionSortFields, "id ASC")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var conditions []string
+	var args []interface{}
+	if raw := c.Query("course_id"); raw != "" {
+		courseID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
+			return
+		}
+		args = append(args, courseID)
+		conditions = append(conditions, fmt.Sprintf("course_id = $%d", len(args)))
+	}
+	if raw := c.Query("student_id"); raw != "" {
+		studentID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID format"})
+			return
+		}
+		args = append(args, studentID)
+		conditions = append(conditions, fmt.Sprintf("student_id = $%d", len(args)))
+	}
+
+	query := getAllQuestionsQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, page.PageSize, page.Offset())
+	query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", order, len(args)-1, len(args))
+
+	rows, err := h.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve questions"})
 		return
@@ -184,6 +274,159 @@ func (h *QuestionController) GetAllQuestions(c *gin.Context) {
 	c.JSON(http.StatusOK, questions)
 }
 
+// @Summary Get unanswered questions for a course
+// @Description List, oldest first, the questions without any answer on a course owned by the authenticated teacher
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param request body UnansweredQuestionsRequest true "Course ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of questions per page (max 100)"
+// @Success 200 {array} models.Question
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /questions/unanswered [post]
+func (h *QuestionController) GetUnansweredByCourse(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req UnansweredQuestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	teacherID, ok, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only teachers can triage questions"})
+		return
+	}
+
+	// Only the course owner may triage its questions
+	var ownerID uint
+	err = h.db.QueryRowContext(ctx, getCourseTeacherQuery, req.CourseID).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if ownerID != teacherID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not own this course"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getUnansweredByCourseQuery, req.CourseID, page.PageSize, page.Offset())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve questions"})
+		return
+	}
+	defer rows.Close()
+
+	questions := []models.Question{}
+	for rows.Next() {
+		var question models.Question
+		if err := rows.Scan(
+			&question.ID, &question.CourseID, &question.StudentID, &question.Question,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process questions"})
+			return
+		}
+		questions = append(questions, question)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing questions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, questions)
+}
+
+// @Summary Get a course's questions with their answers
+// @Description List a page of the questions asked on a course, newest first, each with its answers oldest first
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param request body CourseQuestionsRequest true "Course ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of questions per page (max 100)"
+// @Success 200 {object} PagedResponse{data=[]CourseQuestion}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /questions/byCourse [post]
+func (h *QuestionController) GetQuestionsWithAnswersByCourse(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req CourseQuestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var exists bool
+	if err := h.db.QueryRowContext(ctx, courseExistsQuery, req.CourseID).Scan(&exists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	// Students lose access to course material once their enrollment expires
+	if denyExpiredAccess(ctx, h.db, c, req.CourseID) {
+		return
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countCourseQuestionsQuery, req.CourseID).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count questions"})
+		return
+	}
+
+	questions, err := loadCourseQuestions(ctx, h.db, req.CourseID, page.PageSize, page.Offset())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve questions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, PagedResponse{Data: questions, Pagination: page.Meta(total)})
+}
+
 // @Summary Update question
 // @Description Update an existing question
 // @Tags questions
@@ -202,23 +445,38 @@ func (h *QuestionController) UpdateQuestion(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var question models.Question
 	if err := c.ShouldBindJSON(&question); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if question.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := question.ID
 
 	if err := h.validateQuestion(&question); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Question
+	if wantsDiff(c) {
+		err := h.db.QueryRowContext(ctx, getQuestionQuery, id).Scan(
+			&before.ID, &before.CourseID, &before.StudentID, &before.Question,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Question not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve question"})
+			return
+		}
+	}
+
 	result, err := h.db.ExecContext(ctx, updateQuestionQuery,
 		question.CourseID, question.StudentID, question.Question, id)
 
@@ -237,8 +495,7 @@ func (h *QuestionController) UpdateQuestion(c *gin.Context) {
 		return
 	}
 
-	question.ID = uint(id)
-	c.JSON(http.StatusOK, question)
+	respondUpdated(c, before, question)
 }
 
 // @Summary Delete question
@@ -282,3 +539,54 @@ func (h *QuestionController) DeleteQuestion(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Question deleted successfully"})
 }
+
+// @Summary Get question stats for a course
+// @Description Count the questions of a course, their answers, and the share of questions that got at least one answer
+// @Tags questions
+// @Accept json
+// @Produce json
+// @Param request body UnansweredQuestionsRequest true "Course ID"
+// @Success 200 {object} QuestionStats
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /questions/stats [post]
+func (h *QuestionController) GetCourseQuestionStats(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req UnansweredQuestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+
+	var exists bool
+	if err := h.db.QueryRowContext(ctx, courseExistsQuery, req.CourseID).Scan(&exists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	stats := QuestionStats{CourseID: req.CourseID}
+	err := h.db.QueryRowContext(ctx, getCourseQuestionStatsQuery, req.CourseID).Scan(
+		&stats.Questions, &stats.Answers, &stats.AnsweredQuestions,
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute question stats"})
+		return
+	}
+	if stats.Questions > 0 {
+		stats.AnsweredRate = math.Round(float64(stats.AnsweredQuestions)/float64(stats.Questions)*100) / 100
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
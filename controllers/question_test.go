@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetAllQuestionsPaginates(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect("FROM questions ORDER BY id ASC LIMIT $1 OFFSET $2", 2, 2).returns(
+		row(3, 7, 5, "How do maps work?"),
+		row(4, 7, 6, "What is a slice?"),
+	)
+
+	w := serve(t, NewQuestionController(db).GetAllQuestions, testRequest{
+		method: http.MethodGet,
+		query:  "page=2&page_size=2",
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var questions []map[string]interface{}
+	decodeBody(t, w, &questions)
+	if len(questions) != 2 {
+		t.Errorf("got %d questions, want 2", len(questions))
+	}
+}
+
+func TestGetAllQuestionsCombinesFilters(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect("FROM questions WHERE course_id = $1 AND student_id = $2 ORDER BY student_id DESC LIMIT $3 OFFSET $4",
+		7, 5, defaultPageSize, 0).returns(row(3, 7, 5, "How do maps work?"))
+
+	w := serve(t, NewQuestionController(db).GetAllQuestions, testRequest{
+		method: http.MethodGet,
+		query:  "course_id=7&student_id=5&sort=-student_id",
+	})
+	expectStatus(t, w, http.StatusOK)
+}
+
+func TestGetAllQuestionsRejectsBadQueries(t *testing.T) {
+	for _, query := range []string{"course_id=seven", "student_id=x", "sort=question", "page=0"} {
+		t.Run(query, func(t *testing.T) {
+			db, _ := newFakeDB(t)
+
+			w := serve(t, NewQuestionController(db).GetAllQuestions, testRequest{
+				method: http.MethodGet,
+				query:  query,
+			})
+			expectStatus(t, w, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestGetUnansweredByCourseListsQuestionsWithoutAnswers(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(getCourseTeacherQuery, 7).returns(row(3))
+	// Answered questions are left out by the query itself
+	f.expect("AND NOT EXISTS (SELECT 1 FROM answers a WHERE a.question_id = q.id)\n\t\tORDER BY q.id ASC", 7, defaultPageSize, 0).
+		returns(row(3, 7, 5, "How do maps work?"))
+
+	w := serve(t, NewQuestionController(db).GetUnansweredByCourse, testRequest{
+		body:   `{"course_id": 7}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var questions []map[string]interface{}
+	decodeBody(t, w, &questions)
+	if len(questions) != 1 || questions[0]["ID"] != float64(3) {
+		t.Errorf("questions = %v, want only the unanswered question 3", questions)
+	}
+}
+
+func TestGetUnansweredByCourseEmpty(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+	f.expect(getCourseTeacherQuery, 7).returns(row(3))
+	f.expect(getUnansweredByCourseQuery, 7, defaultPageSize, 0)
+
+	w := serve(t, NewQuestionController(db).GetUnansweredByCourse, testRequest{
+		body:   `{"course_id": 7}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusOK)
+	if body := w.Body.String(); body != "[]" {
+		t.Errorf("body = %s, want an empty list", body)
+	}
+}
+
+func TestGetUnansweredByCourseRequiresOwner(t *testing.T) {
+	t.Run("other teacher", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+		f.expect(getCourseTeacherQuery, 7).returns(row(4))
+
+		w := serve(t, NewQuestionController(db).GetUnansweredByCourse, testRequest{
+			body:   `{"course_id": 7}`,
+			claims: teacherClaims("bob"),
+		})
+		expectStatus(t, w, http.StatusForbidden)
+	})
+
+	t.Run("student", func(t *testing.T) {
+		db, _ := newFakeDB(t)
+
+		w := serve(t, NewQuestionController(db).GetUnansweredByCourse, testRequest{
+			body:   `{"course_id": 7}`,
+			claims: studentClaims("alice"),
+		})
+		expectStatus(t, w, http.StatusForbidden)
+	})
+
+	t.Run("missing course", func(t *testing.T) {
+		db, f := newFakeDB(t)
+		f.expect(getTeacherIDByUsernameQuery, "bob").returns(row(3))
+		f.expect(getCourseTeacherQuery, 7)
+
+		w := serve(t, NewQuestionController(db).GetUnansweredByCourse, testRequest{
+			body:   `{"course_id": 7}`,
+			claims: teacherClaims("bob"),
+		})
+		expectStatus(t, w, http.StatusNotFound)
+	})
+}
+
+func TestGetCourseQuestionStats(t *testing.T) {
+	tests := []struct {
+		name string
+		row  []interface{}
+		want QuestionStats
+	}{
+		// Three questions, two of them answered with 2 and 3 answers
+		{"answered", row(3, 5, 2), QuestionStats{CourseID: 7, Questions: 3, Answers: 5, AnsweredQuestions: 2, AnsweredRate: 0.67}},
+		{"all answered", row(2, 2, 2), QuestionStats{CourseID: 7, Questions: 2, Answers: 2, AnsweredQuestions: 2, AnsweredRate: 1}},
+		{"no questions", row(0, 0, 0), QuestionStats{CourseID: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(courseExistsQuery, 7).returns(row(true))
+			f.expect(getCourseQuestionStatsQuery, 7).returns(tt.row)
+
+			w := serve(t, NewQuestionController(db).GetCourseQuestionStats, testRequest{body: `{"course_id": 7}`})
+			expectStatus(t, w, http.StatusOK)
+
+			var got QuestionStats
+			decodeBody(t, w, &got)
+			if got != tt.want {
+				t.Errorf("stats = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCourseQuestionStatsMissingCourse(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsQuery, 7).returns(row(false))
+
+	w := serve(t, NewQuestionController(db).GetCourseQuestionStats, testRequest{body: `{"course_id": 7}`})
+	expectStatus(t, w, http.StatusNotFound)
+}
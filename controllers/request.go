@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBulkIDs caps how many resources a single bulk request may target
+const maxBulkIDs = 100
+
+// IDsRequest identifies several resources of the same kind in a request body
+type IDsRequest struct {
+	IDs []uint `json:"ids"`
+}
+
+// IDRequest identifies a single resource in a request body
+type IDRequest struct {
+	ID uint `json:"id"`
+}
+
+// bindID reads the resource ID from a {"id": ...} request body. It writes a
+// 400 and returns false when the ID is missing or not a positive integer.
+func bindID(c *gin.Context) (uint, bool) {
+	var req IDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+			return 0, false
+		}
+		if errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+			return 0, false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, false
+	}
+	if req.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return 0, false
+	}
+	return req.ID, true
+}
+
+// bindIDs reads a non-empty {"ids": [...]} request body and returns the IDs as
+// int64 so they can be passed as a Postgres array. Duplicates are dropped. It
+// writes a 400 and returns false when the list is missing, too long or
+// contains an invalid ID.
+func bindIDs(c *gin.Context) ([]int64, bool) {
+	var req IDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return checkIDs(c, req.IDs)
+}
+
+// checkIDs validates and dedups IDs already read from a request body, the
+// same way bindIDs does
+func checkIDs(c *gin.Context, reqIDs []uint) ([]int64, bool) {
+	if len(reqIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "at least one ID is required"})
+		return nil, false
+	}
+	if len(reqIDs) > maxBulkIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("at most %d IDs are allowed", maxBulkIDs)})
+		return nil, false
+	}
+
+	seen := make(map[uint]bool, len(reqIDs))
+	ids := make([]int64, 0, len(reqIDs))
+	for _, id := range reqIDs {
+		if id == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "IDs must be positive integers"})
+			return nil, false
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, int64(id))
+		}
+	}
+	return ids, true
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBindID(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		error  string
+	}{
+		{"valid", `{"id": 4}`, http.StatusOK, ""},
+		{"missing field", `{}`, http.StatusBadRequest, "ID is required"},
+		{"empty body", ``, http.StatusBadRequest, "ID is required"},
+		{"non-numeric", `{"id": "four"}`, http.StatusBadRequest, "Invalid ID format"},
+		{"negative", `{"id": -4}`, http.StatusBadRequest, "Invalid ID format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(t, func(c *gin.Context) {
+				if id, ok := bindID(c); ok {
+					c.JSON(http.StatusOK, gin.H{"id": id})
+				}
+			}, testRequest{body: tt.body})
+			expectStatus(t, w, tt.status)
+
+			var got map[string]interface{}
+			decodeBody(t, w, &got)
+			if tt.error != "" && got["error"] != tt.error {
+				t.Errorf("error = %v, want %q", got["error"], tt.error)
+			}
+			if tt.error == "" && got["id"] != float64(4) {
+				t.Errorf("id = %v, want 4", got["id"])
+			}
+		})
+	}
+}
+
+func TestGetExamReadsIDFromBody(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getExamQuery, 4).returns(row(4, "Final", 7, false, []byte(`{"ID": 7, "Name": "Go"}`)))
+
+	w := serve(t, NewExamController(db).GetExam, testRequest{body: `{"id": 4}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var exam map[string]interface{}
+	decodeBody(t, w, &exam)
+	if exam["ID"] != float64(4) {
+		t.Errorf("exam = %v, want exam 4", exam)
+	}
+}
+
+func TestGetExamNotFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getExamQuery, 4)
+
+	w := serve(t, NewExamController(db).GetExam, testRequest{body: `{"id": 4}`})
+	expectStatus(t, w, http.StatusNotFound)
+}
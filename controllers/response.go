@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// envelopeProfile is the Accept profile clients send to get every successful
+// response wrapped in an Envelope, e.g. Accept: application/json; profile="envelope"
+const envelopeProfile = "envelope"
+
+// Envelope wraps a successful response for clients that asked for it
+type Envelope struct {
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data"`
+}
+
+// wantsEnvelope reports whether the client asked for the envelope profile
+func wantsEnvelope(c *gin.Context) bool {
+	for _, accept := range strings.Split(c.GetHeader("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && params["profile"] == envelopeProfile {
+			return true
+		}
+	}
+	return false
+}
+
+// respond writes a successful response, wrapped in an Envelope when the
+// client asked for it and bare otherwise
+func respond(c *gin.Context, status int, data interface{}) {
+	c.Writer.Header().Add("Vary", "Accept")
+	if wantsEnvelope(c) {
+		data = Envelope{Success: true, Data: data}
+	}
+	c.JSON(status, data)
+}
+
+// wantsDiff reports whether the client sent "Prefer: return=diff"
+func wantsDiff(c *gin.Context) bool {
+	for _, pref := range strings.Split(c.GetHeader("Prefer"), ",") {
+		if strings.TrimSpace(pref) == "return=diff" {
+			return true
+		}
+	}
+	return false
+}
+
+// changedFields returns the JSON fields of after whose values differ from before
+func changedFields(before, after interface{}) (map[string]interface{}, error) {
+	oldFields, err := toJSONFields(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := toJSONFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]interface{})
+	for key, value := range newFields {
+		if !reflect.DeepEqual(oldFields[key], value) {
+			diff[key] = value
+		}
+	}
+	return diff, nil
+}
+
+func toJSONFields(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// decodeJoined unmarshals a json_build_object column into dest, leaving dest
+// untouched when the join produced no row
+func decodeJoined(data []byte, dest interface{}) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// respondUpdated writes the updated resource, or only the fields that changed
+// compared to before when the client asked for it with "Prefer: return=diff"
+func respondUpdated(c *gin.Context, before, after interface{}) {
+	if !wantsDiff(c) {
+		respond(c, http.StatusOK, after)
+		return
+	}
+
+	diff, err := changedFields(before, after)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute changes"})
+		return
+	}
+
+	c.Header("Preference-Applied", "return=diff")
+	respond(c, http.StatusOK, diff)
+}
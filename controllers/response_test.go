@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestResponsesUseOneIDKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		expect  func(f *fakeDB)
+		handler func(db *sql.DB) gin.HandlerFunc
+	}{
+		{
+			"course",
+			func(f *fakeDB) {
+				f.expect(courseExistsQuery, 7).returns(row(true))
+				f.expect(getCourseDetailsQuery, 7).returns(courseDetailsRow())
+			},
+			func(db *sql.DB) gin.HandlerFunc { return NewCourseController(db).GetCourse },
+		},
+		{
+			"student",
+			func(f *fakeDB) {
+				f.expect(studentByIDInlineQuery, 7).returns(row(7, "Alice", "alice", "alice@example.com", "", ""))
+			},
+			func(db *sql.DB) gin.HandlerFunc { return NewStudentController(db).GetStudent },
+		},
+		{
+			"teacher",
+			func(f *fakeDB) {
+				f.expect(getTeacherQuery, 7).returns(row(7, "Bob", "bob", "bob@example.com", "", "", "Go", "MSc", "10y"))
+			},
+			func(db *sql.DB) gin.HandlerFunc { return NewTeacherController(db).GetTeacher },
+		},
+		{
+			"question",
+			func(f *fakeDB) {
+				f.expect(getQuestionQuery, 7).returns(row(7, 3, 5, "How do maps grow?"))
+			},
+			func(db *sql.DB) gin.HandlerFunc { return NewQuestionController(db).GetQuestion },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			tt.expect(f)
+
+			w := serve(t, tt.handler(db), testRequest{body: `{"id": 7}`})
+			expectStatus(t, w, http.StatusOK)
+
+			got := responseKeys(t, w.Body.Bytes())
+			if got["ID"] != float64(7) {
+				t.Errorf("response %v has no ID 7", got)
+			}
+			if _, ok := got["id"]; ok {
+				t.Errorf("response %v also has a lowercase id", got)
+			}
+		})
+	}
+}
+
+func TestWantsEnvelope(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"application/json", false},
+		{`application/json; profile="envelope"`, true},
+		{"application/json;profile=envelope", true},
+		{`text/html, application/json; profile="envelope"`, true},
+		{`application/json; profile="other"`, false},
+	}
+	for _, tt := range tests {
+		c := queryContext("")
+		c.Request.Header.Set("Accept", tt.accept)
+		if got := wantsEnvelope(c); got != tt.want {
+			t.Errorf("wantsEnvelope(%q) = %t, want %t", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestGetCourseEnvelope(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		wrapped bool
+	}{
+		{"bare by default", nil, false},
+		{"wrapped on request", map[string]string{"Accept": `application/json; profile="envelope"`}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(courseExistsQuery, 7).returns(row(true))
+			f.expect(getCourseDetailsQuery, 7).returns(courseDetailsRow())
+
+			w := serve(t, NewCourseController(db).GetCourse, testRequest{body: `{"id": 7}`, headers: tt.headers})
+			expectStatus(t, w, http.StatusOK)
+			if w.Header().Get("Vary") != "Accept" {
+				t.Errorf("Vary = %q, want Accept", w.Header().Get("Vary"))
+			}
+
+			course := responseKeys(t, w.Body.Bytes())
+			if tt.wrapped {
+				if course["success"] != true {
+					t.Errorf("envelope = %v, want success true", course)
+				}
+				course, _ = course["data"].(map[string]interface{})
+			}
+			if course["ID"] != float64(7) {
+				t.Errorf("course = %v, want course 7", course)
+			}
+		})
+	}
+}
+
+// Errors keep their usual shape, the envelope only wraps successes
+func TestGetCourseEnvelopeLeavesErrorsBare(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(courseExistsQuery, 7).returns(row(false))
+
+	w := serve(t, NewCourseController(db).GetCourse, testRequest{
+		body:    `{"id": 7}`,
+		headers: map[string]string{"Accept": `application/json; profile="envelope"`},
+	})
+	expectStatus(t, w, http.StatusNotFound)
+
+	got := responseKeys(t, w.Body.Bytes())
+	if _, ok := got["success"]; ok {
+		t.Errorf("error response %v is wrapped", got)
+	}
+}
@@ -4,26 +4,35 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/cuddest/dz-skills/certificate"
+	"github.com/cuddest/dz-skills/config"
+	"github.com/cuddest/dz-skills/mailer"
 	"github.com/cuddest/dz-skills/models"
 	"github.com/gin-gonic/gin"
 )
 
 const (
 	createStudentCourseQuery = `
-		INSERT INTO student_courses (student_id, course_id, grade, enrollment, certificate, issued) 
-		VALUES ($1, $2, $3, $4, $5, $6)`
+		INSERT INTO student_courses (student_id, course_id, grade, enrollment, access_expires_at, certificate, issued) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
 	getStudentCourseQuery = `
-		SELECT student_id, course_id, grade, enrollment, certificate, issued 
+		SELECT student_id, course_id, grade, enrollment, access_expires_at, certificate, issued, attempts 
 		FROM student_courses 
 		WHERE student_id = $1 AND course_id = $2`
 
 	getAllStudentCoursesQuery = `
-		SELECT student_id, course_id, grade, enrollment, certificate, issued 
+		SELECT student_id, course_id, grade, enrollment, access_expires_at, certificate, issued, attempts 
 		FROM student_courses`
 
 	updateStudentCourseQuery = `
@@ -31,6 +40,12 @@ const (
 		SET grade = $1, enrollment = $2, certificate = $3, issued = $4
 		WHERE student_id = $5 AND course_id = $6`
 
+	// Grading leaves the enrollment date alone, the access period counts from it
+	recordExamResultQuery = `
+		UPDATE student_courses 
+		SET grade = $1, certificate = $2, issued = $3
+		WHERE student_id = $4 AND course_id = $5`
+
 	deleteStudentCourseQuery = `
 		DELETE FROM student_courses 
 		WHERE student_id = $1 AND course_id = $2`
@@ -38,12 +53,255 @@ const (
 	getExamQuizzAnswerQuery = `
 		SELECT answer 
 		FROM exam_quizzes 
-		WHERE id = $1`
+		WHERE id = $1 AND exam_id = $2`
+
+	getCourseExamQuery = `
+		SELECT id, archived FROM exams WHERE course_id = $1`
+
+	countExamQuizzesQuery = `
+		SELECT COUNT(*) FROM exam_quizzes WHERE exam_id = $1`
+
+	// incrementAttemptsQuery only matches while attempts remain, so concurrent
+	// submissions can't go past the limit
+	incrementAttemptsQuery = `
+		UPDATE student_courses 
+		SET attempts = attempts + 1 
+		WHERE student_id = $1 AND course_id = $2 AND attempts < $3 
+		RETURNING attempts`
+
+	createExamAnswerQuery = `
+		INSERT INTO student_exam_answers (student_id, course_id, quizz_id, chosen_answer, correct, submitted_at) 
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	getLatestExamSubmissionQuery = `
+		SELECT id, student_id, course_id, quizz_id, chosen_answer, correct, submitted_at 
+		FROM student_exam_answers 
+		WHERE student_id = $1 AND course_id = $2 AND submitted_at = (
+			SELECT MAX(submitted_at) FROM student_exam_answers WHERE student_id = $1 AND course_id = $2
+		) 
+		ORDER BY id ASC`
+
+	createExamAttemptQuery = `
+		INSERT INTO exam_attempts (student_id, course_id, attempt, score, total, grade, passed, submitted_at, idempotency_key) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	getAttemptByIdempotencyKeyQuery = `
+		SELECT attempt, grade, passed FROM exam_attempts 
+		WHERE student_id = $1 AND course_id = $2 AND idempotency_key = $3`
+
+	lockStudentCourseQuery = `
+		SELECT 1 FROM student_courses WHERE student_id = $1 AND course_id = $2 FOR UPDATE`
+
+	getExamHistoryQuery = `
+		SELECT id, student_id, course_id, attempt, score, total, grade, passed, submitted_at 
+		FROM exam_attempts 
+		WHERE student_id = $1 AND course_id = $2 
+		ORDER BY submitted_at ASC, id ASC 
+		LIMIT $3 OFFSET $4`
+
+	// Each submitter is ranked on their latest attempt. cume_dist is the share
+	// of submitters scoring at or below the student, 1 for a lone submitter.
+	getExamStandingQuery = `
+		WITH latest AS (
+			SELECT DISTINCT ON (student_id) student_id, score, total, passed 
+			FROM exam_attempts 
+			WHERE course_id = $2 
+			ORDER BY student_id, attempt DESC
+		), ranked AS (
+			SELECT student_id, score, total, passed, 
+				cume_dist() OVER (ORDER BY COALESCE(score::float8 / NULLIF(total, 0), 0)) AS standing, 
+				COUNT(*) OVER () AS submitters 
+			FROM latest
+		)
+		SELECT score, total, passed, standing, submitters 
+		FROM ranked WHERE student_id = $1`
+
+	getEnrolledStudentsQuery = `
+		SELECT s.id, s.full_name, s.email, COALESCE(sc.grade, ''), sc.enrollment 
+		FROM student_courses sc 
+		JOIN students s ON s.id = sc.student_id 
+		WHERE sc.course_id = $1 
+		ORDER BY sc.enrollment ASC, s.id ASC 
+		LIMIT $2 OFFSET $3`
+
+	countEnrollmentsQuery = `
+		SELECT COUNT(*) FROM student_courses WHERE course_id = $1`
+
+	getMyCoursesQuery = `
+		SELECT sc.student_id, sc.course_id, sc.grade, sc.enrollment, sc.access_expires_at, sc.certificate, sc.issued, sc.attempts, 
+			c.id, c.name, c.description, c.pricing, c.duration, c.image, c.language, c.level, 
+			c.access_days, c.teacher_id, c.category_id, c.sub_cat_id, c.created_at, c.average_rating, c.ratings_count 
+		FROM student_courses sc 
+		JOIN courses c ON c.id = sc.course_id 
+		WHERE sc.student_id = $1 AND c.deleted_at IS NULL 
+		ORDER BY sc.enrollment DESC, c.id ASC`
+
+	// Certificates already earned stay available when their course is soft deleted
+	getCertificateDetailsQuery = `
+		SELECT sc.certificate, COALESCE(sc.grade, ''), s.full_name, c.name, 
+			COALESCE((SELECT MAX(ea.submitted_at) FROM exam_attempts ea 
+				WHERE ea.student_id = sc.student_id AND ea.course_id = sc.course_id AND ea.passed), NOW()) 
+		FROM student_courses sc 
+		JOIN students s ON s.id = sc.student_id 
+		JOIN courses c ON c.id = sc.course_id 
+		WHERE sc.student_id = $1 AND sc.course_id = $2 AND sc.issued = TRUE AND sc.certificate IS NOT NULL`
+
+	getCertificateRecipientQuery = `
+		SELECT sc.certificate, COALESCE(sc.grade, ''), s.full_name, s.email, c.name, 
+			COALESCE((SELECT MAX(ea.submitted_at) FROM exam_attempts ea 
+				WHERE ea.student_id = sc.student_id AND ea.course_id = sc.course_id AND ea.passed), NOW()) 
+		FROM student_courses sc 
+		JOIN students s ON s.id = sc.student_id 
+		JOIN courses c ON c.id = sc.course_id 
+		WHERE sc.student_id = $1 AND sc.course_id = $2 AND sc.issued = TRUE AND sc.certificate IS NOT NULL`
 )
 
+var (
+	errAttemptsExhausted = errors.New("no exam attempts left")
+	errAlreadySubmitted  = errors.New("exam already submitted with this idempotency key")
+)
+
+// maxIdempotencyKeyLength caps the Idempotency-Key header of exam submissions
+const maxIdempotencyKeyLength = 255
+
+// certificatePlaceholder is the certificate text given for a passed exam
+const certificatePlaceholder = "System of certificates available soon"
+
+// examPassPercent is the share of correct answers, in percent, needed to pass an exam
+const examPassPercent = 50
+
+// certificateResendCooldown is how long a student must wait between two
+// resends of the same certificate
+const certificateResendCooldown = 10 * time.Minute
+
 // StudentCourseController handles HTTP requests for StudentCourse operations
 type StudentCourseController struct {
-	db *sql.DB
+	db          *sql.DB
+	mailer      mailer.Mailer
+	maxAttempts int
+
+	// lastResend remembers when each student/course certificate was last
+	// resent, guarded by resendMu
+	resendMu   sync.Mutex
+	lastResend map[[2]uint]time.Time
+}
+
+// ExamResult is the outcome of a graded exam. Issued and Certificate mirror
+// the enrollment fields and are always present, Certificate being null on a fail.
+type ExamResult struct {
+	Grade             string  `json:"grade"`
+	Passed            bool    `json:"passed"`
+	Issued            bool    `json:"issued"`
+	Certificate       *string `json:"certificate"`
+	CertificateIssued bool    `json:"certificate_issued"` // kept for older clients, same as issued
+	Attempts          int     `json:"attempts"`
+	RemainingAttempts int     `json:"remaining_attempts"`
+}
+
+// examResult builds the result of a graded attempt
+func (h *StudentCourseController) examResult(grade string, passed bool, attempts int) ExamResult {
+	var certificate *string
+	if passed {
+		text := certificatePlaceholder
+		certificate = &text
+	}
+	return ExamResult{
+		Grade:             grade,
+		Passed:            passed,
+		Issued:            passed,
+		Certificate:       certificate,
+		CertificateIssued: passed,
+		Attempts:          attempts,
+		RemainingAttempts: h.maxAttempts - attempts,
+	}
+}
+
+// submittedAttempt looks up the result of the attempt submitted with key,
+// returning sql.ErrNoRows when there is none
+func submittedAttempt(ctx context.Context, q queryRower, studentID, courseID uint, key string) (grade string, passed bool, attempt int, err error) {
+	err = q.QueryRowContext(ctx, getAttemptByIdempotencyKeyQuery, studentID, courseID, key).Scan(&attempt, &grade, &passed)
+	return grade, passed, attempt, err
+}
+
+// StudentCourseKeyRequest identifies one enrollment by its student and course
+type StudentCourseKeyRequest struct {
+	StudentID uint `json:"student_id"`
+	CourseID  uint `json:"course_id"`
+}
+
+// ExamSubmissionRequest identifies whose exam submission to retrieve. Students
+// may leave StudentID out, teachers must name one of their course's students.
+type ExamSubmissionRequest struct {
+	CourseID  uint `json:"course_id"`
+	StudentID uint `json:"student_id"`
+}
+
+// ExamSubmission is the set of answers a student gave on one exam attempt
+type ExamSubmission struct {
+	StudentID   uint                       `json:"student_id"`
+	CourseID    uint                       `json:"course_id"`
+	SubmittedAt time.Time                  `json:"submitted_at"`
+	Answers     []models.StudentExamAnswer `json:"answers"`
+}
+
+// ExamHistoryRequest identifies the course whose exam attempts to list
+type ExamHistoryRequest struct {
+	CourseID uint `json:"course_id"`
+}
+
+// ExamStandingRequest identifies the course whose exam standing to compute
+type ExamStandingRequest struct {
+	CourseID uint `json:"course_id"`
+}
+
+// ExamStanding is how a student's latest exam attempt compares to the pass
+// mark and to the other students who took the exam
+type ExamStanding struct {
+	Score       int     `json:"score"`
+	Total       int     `json:"total"`
+	Percent     float64 `json:"percent"`
+	PassPercent int     `json:"pass_percent"`
+	Passed      bool    `json:"passed"`
+	Percentile  float64 `json:"percentile"` // share of submitters scoring at or below the student
+	Submitters  int     `json:"submitters"`
+}
+
+// StudentCourseStanding is an enrollment along with its exam standing, null
+// until the student submits the exam
+type StudentCourseStanding struct {
+	models.StudentCourse
+	Standing *ExamStanding `json:"standing"`
+}
+
+// EnrollmentRequest identifies the course whose enrollments to look up
+type EnrollmentRequest struct {
+	CourseID uint `json:"course_id"`
+}
+
+// EnrolledStudent is a student on a course roster
+type EnrolledStudent struct {
+	ID         uint      `json:"ID"`
+	FullName   string    `json:"FullName"`
+	Email      string    `json:"email"`
+	Grade      string    `json:"grade"`
+	Enrollment time.Time `json:"enrollment"`
+}
+
+// EnrollmentCount is the number of students enrolled in a course
+type EnrollmentCount struct {
+	CourseID uint `json:"course_id"`
+	Count    int  `json:"count"`
+}
+
+// MyCourse is one of the authenticated student's enrollments along with its course
+type MyCourse struct {
+	models.StudentCourse
+	Course models.Course `json:"course"`
+}
+
+// ResendCertificateRequest identifies the course whose certificate to resend
+type ResendCertificateRequest struct {
+	CourseID uint `json:"course_id"`
 }
 
 // ExamAnswer represents a student's answer to an exam question
@@ -52,9 +310,40 @@ type ExamAnswer struct {
 	Answer  uint `json:"answer"`
 }
 
+// SubmitExamRequest is one exam attempt, an answer per question of the course exam
+type SubmitExamRequest struct {
+	CourseID uint         `json:"course_id"`
+	Answers  []ExamAnswer `json:"answers"`
+}
+
 // NewStudentCourseController creates a new StudentCourseController instance
 func NewStudentCourseController(db *sql.DB) *StudentCourseController {
-	return &StudentCourseController{db: db}
+	return &StudentCourseController{
+		db:          db,
+		mailer:      mailer.FromEnv(),
+		maxAttempts: config.ExamMaxAttempts(),
+		lastResend:  make(map[[2]uint]time.Time),
+	}
+}
+
+// gradePattern matches the grades SubmitExamAnswers writes, correct/total
+var gradePattern = regexp.MustCompile(`^([0-9]+)/([0-9]+)$`)
+
+// validateGrade checks that a grade is empty or correct/total with correct <= total
+func validateGrade(grade string) error {
+	if grade == "" {
+		return nil
+	}
+	match := gradePattern.FindStringSubmatch(grade)
+	if match == nil {
+		return errors.New("grade must look like correct/total, e.g. 8/10")
+	}
+	correct, err1 := strconv.Atoi(match[1])
+	total, err2 := strconv.Atoi(match[2])
+	if err1 != nil || err2 != nil || total == 0 || correct > total {
+		return errors.New("grade must have a positive total no lower than the correct answers")
+	}
+	return nil
 }
 
 // validateStudentCourse performs validation on student course data
@@ -69,7 +358,7 @@ func (h *StudentCourseController) validateStudentCourse(sc *models.StudentCourse
 }
 
 // @Summary Create student course enrollment
-// @Description Create a new student course enrollment
+// @Description Create a new student course enrollment. Admins importing existing enrollments may set the grade, issued, certificate and enrollment date, for everyone else they start blank.
 // @Tags student-courses
 // @Accept json
 // @Produce json
@@ -94,12 +383,43 @@ func (h *StudentCourseController) CreateStudentCourse(c *gin.Context) {
 		return
 	}
 
-	// Set default values for new enrollment
-	sc.Enrollment = time.Now()
-	sc.Issued = false
+	// Time-limited courses grant access for a number of days from enrollment
+	var accessDays uint
+	err := h.db.QueryRowContext(ctx, getCourseAccessDaysQuery, sc.CourseID).Scan(&accessDays)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+
+	// Admins migrating enrollments keep the progress they import, anyone else
+	// starts a fresh enrollment
+	claims, _ := auth.ClaimsFromContext(c)
+	if claims != nil && claims.Role == auth.RoleAdmin {
+		if err := validateGrade(sc.Grade); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if sc.Issued != (sc.Certificate != nil) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "certificate must be given exactly when issued is true"})
+			return
+		}
+		if sc.Enrollment.IsZero() {
+			sc.Enrollment = now()
+		}
+	} else {
+		sc.Grade = ""
+		sc.Certificate = nil
+		sc.Issued = false
+		sc.Enrollment = now()
+	}
+	sc.AccessExpiresAt = accessExpiry(sc.Enrollment, accessDays)
 
-	_, err := h.db.ExecContext(ctx, createStudentCourseQuery,
-		sc.StudentID, sc.CourseID, sc.Grade, sc.Enrollment, sc.Certificate, sc.Issued)
+	_, err = h.db.ExecContext(ctx, createStudentCourseQuery,
+		sc.StudentID, sc.CourseID, sc.Grade, sc.Enrollment, sc.AccessExpiresAt, sc.Certificate, sc.Issued)
 
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create student course enrollment"})
@@ -114,8 +434,7 @@ func (h *StudentCourseController) CreateStudentCourse(c *gin.Context) {
 // @Tags student-courses
 // @Accept json
 // @Produce json
-// @Param studentId path int true "Student ID"
-// @Param courseId path int true "Course ID"
+// @Param request body StudentCourseKeyRequest true "Student and course IDs"
 // @Success 200 {object} models.StudentCourse
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -126,21 +445,20 @@ func (h *StudentCourseController) GetStudentCourse(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	studentID, err := strconv.ParseUint(c.Param("studentId"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID format"})
+	var req StudentCourseKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	courseID, err := strconv.ParseUint(c.Param("courseId"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
+	if req.StudentID == 0 || req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "student_id and course_id are required"})
 		return
 	}
 
 	var sc models.StudentCourse
-	err = h.db.QueryRowContext(ctx, getStudentCourseQuery, studentID, courseID).Scan(
-		&sc.StudentID, &sc.CourseID, &sc.Grade, &sc.Enrollment, &sc.Certificate, &sc.Issued,
+	err := h.db.QueryRowContext(ctx, getStudentCourseQuery, req.StudentID, req.CourseID).Scan(
+		&sc.StudentID, &sc.CourseID, &sc.Grade, &sc.Enrollment,
+		&sc.AccessExpiresAt, &sc.Certificate, &sc.Issued, &sc.Attempts,
 	)
 
 	if err == sql.ErrNoRows {
@@ -155,6 +473,77 @@ func (h *StudentCourseController) GetStudentCourse(c *gin.Context) {
 	c.JSON(http.StatusOK, sc)
 }
 
+// @Summary Get enrollment with exam standing
+// @Description Retrieve the authenticated student's enrollment in a course along with the score of their latest exam attempt, whether it passes, and their percentile among every student who took the exam
+// @Tags student-courses
+// @Accept json
+// @Produce json
+// @Param request body ExamStandingRequest true "Course ID"
+// @Success 200 {object} StudentCourseStanding
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /student_courses/standing [post]
+func (h *StudentCourseController) GetStudentCourseStanding(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	studentID, ok, err := authenticatedStudentID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only students can view their exam standing"})
+		return
+	}
+
+	var req ExamStandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course ID is required"})
+		return
+	}
+
+	var result StudentCourseStanding
+	sc := &result.StudentCourse
+	err = h.db.QueryRowContext(ctx, getStudentCourseQuery, studentID, req.CourseID).Scan(
+		&sc.StudentID, &sc.CourseID, &sc.Grade, &sc.Enrollment,
+		&sc.AccessExpiresAt, &sc.Certificate, &sc.Issued, &sc.Attempts,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student course enrollment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve student course enrollment"})
+		return
+	}
+
+	standing := ExamStanding{PassPercent: examPassPercent}
+	err = h.db.QueryRowContext(ctx, getExamStandingQuery, studentID, req.CourseID).Scan(
+		&standing.Score, &standing.Total, &standing.Passed, &standing.Percentile, &standing.Submitters,
+	)
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute exam standing"})
+		return
+	}
+	if err == nil {
+		if standing.Total > 0 {
+			standing.Percent = math.Round(10000*float64(standing.Score)/float64(standing.Total)) / 100
+		}
+		standing.Percentile = math.Round(10000*standing.Percentile) / 100
+		result.Standing = &standing
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // @Summary Get all student course enrollments
 // @Description Retrieve all student course enrollments
 // @Tags student-courses
@@ -180,8 +569,8 @@ func (h *StudentCourseController) GetAllStudentCourses(c *gin.Context) {
 	for rows.Next() {
 		var sc models.StudentCourse
 		if err := rows.Scan(
-			&sc.StudentID, &sc.CourseID, &sc.Grade,
-			&sc.Enrollment, &sc.Certificate, &sc.Issued,
+			&sc.StudentID, &sc.CourseID, &sc.Grade, &sc.Enrollment,
+			&sc.AccessExpiresAt, &sc.Certificate, &sc.Issued, &sc.Attempts,
 		); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process student course enrollments"})
 			return
@@ -198,15 +587,15 @@ func (h *StudentCourseController) GetAllStudentCourses(c *gin.Context) {
 }
 
 // @Summary Submit exam answers
-// @Description Submit and grade student exam answers
+// @Description Submit and grade exam answers for the authenticated student, one answer per question of the course exam. Passing requires at least half of the answers to be correct, and each student has a limited number of attempts (EXAM_MAX_ATTEMPTS, 3 by default). Retrying with the same Idempotency-Key returns the original result without using another attempt.
 // @Tags student-courses
 // @Accept json
 // @Produce json
-// @Param studentId path int true "Student ID"
-// @Param courseId path int true "Course ID"
-// @Param answers body []ExamAnswer true "Array of exam answers"
-// @Success 200 {object} models.Answer
+// @Param Idempotency-Key header string false "Unique key of this submission, reused on retries"
+// @Param request body SubmitExamRequest true "Course and its exam answers"
+// @Success 200 {object} ExamResult
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Security ApiKeyAuth
@@ -215,96 +604,203 @@ func (h *StudentCourseController) SubmitExamAnswers(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
 	defer cancel()
 
-	// Get student and course IDs from parameters
-	studentID, err := strconv.ParseUint(c.Param("studentId"), 10, 32)
+	// The student is always the one behind the token, never whoever the body names
+	studentID, ok, err := authenticatedStudentID(ctx, h.db, c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID format"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
 		return
 	}
-
-	courseID, err := strconv.ParseUint(c.Param("courseId"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only students can submit exam answers"})
 		return
 	}
 
-	// Get answers from request body
-	var answers []ExamAnswer
-	if err := c.ShouldBindJSON(&answers); err != nil {
+	var req SubmitExamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid course ID is required"})
+		return
+	}
+	courseID, answers := req.CourseID, req.Answers
 
-	// Validate number of answers
-	if len(answers) != 20 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Exactly 20 answers are required"})
+	// A retried submission gets the result of the original one
+	var idempotencyKey *string
+	if key := strings.TrimSpace(c.GetHeader("Idempotency-Key")); key != "" {
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength)})
+			return
+		}
+		idempotencyKey = &key
+
+		grade, passed, attempt, err := submittedAttempt(ctx, h.db, studentID, courseID, key)
+		if err == nil {
+			c.Header("Idempotent-Replayed", "true")
+			c.JSON(http.StatusOK, h.examResult(grade, passed, attempt))
+			return
+		}
+		if err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check previous submissions"})
+			return
+		}
+	}
+
+	// Exams can only be taken while the enrollment's access window is open
+	err = checkCourseAccess(ctx, h.db, studentID, courseID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Student course enrollment not found"})
+		return
+	}
+	if err == errAccessExpired {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Course access has expired"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course access"})
 		return
 	}
 
-	// Start transaction
-	tx, err := h.db.BeginTx(ctx, nil)
+	// The expected number of answers is the number of questions on the course's exam
+	var examID uint
+	var archived bool
+	err = h.db.QueryRowContext(ctx, getCourseExamQuery, courseID).Scan(&examID, &archived)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam not found"})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exam"})
+		return
+	}
+	if archived {
+		c.JSON(http.StatusConflict, gin.H{"error": "This exam has been archived"})
+		return
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countExamQuizzesQuery, examID).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count exam questions"})
+		return
+	}
+	if total == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam has no questions"})
 		return
 	}
-	defer tx.Rollback()
 
-	// Grade the exam
-	var correctAnswers uint = 0
+	// Validate number of answers
+	if len(answers) != total {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Exactly %d answers are required", total)})
+		return
+	}
+	answered := make(map[uint]bool, len(answers))
 	for _, answer := range answers {
-		var correctAnswer uint
-		err := tx.QueryRowContext(ctx, getExamQuizzAnswerQuery, answer.QuizzID).Scan(&correctAnswer)
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Question not found: " + strconv.FormatUint(uint64(answer.QuizzID), 10)})
+		if answered[answer.QuizzID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Question %d is answered more than once", answer.QuizzID)})
 			return
 		}
+		answered[answer.QuizzID] = true
+	}
+
+	var grade string
+	var passed bool
+	var certificate *string
+	var missingQuizzID uint
+	var attempts int
+	submittedAt := now()
+	err = withTx(ctx, h.db, func(tx *sql.Tx) error {
+		// A concurrent retry may have been graded since the check above, the
+		// enrollment lock makes them wait for each other
+		if idempotencyKey != nil {
+			if _, err := tx.ExecContext(ctx, lockStudentCourseQuery, studentID, courseID); err != nil {
+				return err
+			}
+			var err error
+			grade, passed, attempts, err = submittedAttempt(ctx, tx, studentID, courseID, *idempotencyKey)
+			if err == nil {
+				return errAlreadySubmitted
+			}
+			if err != sql.ErrNoRows {
+				return err
+			}
+		}
+
+		// Count the attempt first so it is rolled back if grading fails
+		err := tx.QueryRowContext(ctx, incrementAttemptsQuery, studentID, courseID, h.maxAttempts).Scan(&attempts)
+		if err == sql.ErrNoRows {
+			return errAttemptsExhausted
+		}
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve correct answer"})
-			return
+			return err
 		}
 
-		if answer.Answer == correctAnswer {
-			correctAnswers++
+		// Grade the exam, keeping every answer so the attempt can be reviewed
+		var correctAnswers uint = 0
+		for _, answer := range answers {
+			var correctAnswer uint
+			err := tx.QueryRowContext(ctx, getExamQuizzAnswerQuery, answer.QuizzID, examID).Scan(&correctAnswer)
+			if err == sql.ErrNoRows {
+				missingQuizzID = answer.QuizzID
+				return err
+			}
+			if err != nil {
+				return err
+			}
+
+			correct := answer.Answer == correctAnswer
+			if correct {
+				correctAnswers++
+			}
+
+			_, err = tx.ExecContext(ctx, createExamAnswerQuery,
+				studentID, courseID, answer.QuizzID, answer.Answer, correct, submittedAt)
+			if err != nil {
+				return err
+			}
 		}
-	}
 
-	// Calculate grade out of 20
-	grade := strconv.FormatUint(uint64(correctAnswers), 10) + "/20"
+		// Calculate grade out of the number of questions
+		grade = fmt.Sprintf("%d/%d", correctAnswers, total)
 
-	// Determine if student passed (>= 10/20)
-	passed := correctAnswers >= 10
-	var certificate *string
-	if passed {
-		certText := "System of certificates available soon"
-		certificate = &certText
-	}
+		// Determine if student passed (at least half of the answers are correct)
+		passed = 100*int(correctAnswers) >= examPassPercent*total
+		if passed {
+			certText := certificatePlaceholder
+			certificate = &certText
+		}
 
-	// Update student course record
-	_, err = tx.ExecContext(ctx, updateStudentCourseQuery,
-		grade, time.Now(), certificate, passed,
-		studentID, courseID)
+		// Keep the outcome of every attempt for the student's exam history
+		_, err = tx.ExecContext(ctx, createExamAttemptQuery,
+			studentID, courseID, attempts, correctAnswers, total, grade, passed, submittedAt, idempotencyKey)
+		if err != nil {
+			return err
+		}
 
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update student course record"})
+		// Update student course record
+		_, err = tx.ExecContext(ctx, recordExamResultQuery,
+			grade, certificate, passed, studentID, courseID)
+		return err
+	})
+	if err == errAlreadySubmitted {
+		c.Header("Idempotent-Replayed", "true")
+		c.JSON(http.StatusOK, h.examResult(grade, passed, attempts))
 		return
 	}
-
-	if err = tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+	if err == errAttemptsExhausted {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Maximum number of exam attempts (%d) reached", h.maxAttempts)})
 		return
 	}
-
-	// Prepare response
-	response := gin.H{
-		"grade":              grade,
-		"passed":             passed,
-		"certificate_issued": passed,
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Question not found: " + strconv.FormatUint(uint64(missingQuizzID), 10)})
+		return
 	}
-	if passed {
-		response["certificate"] = *certificate
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grade exam"})
+		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	c.JSON(http.StatusOK, h.examResult(grade, passed, attempts))
 }
 
 // @Summary Update student course enrollment
@@ -312,8 +808,6 @@ func (h *StudentCourseController) SubmitExamAnswers(c *gin.Context) {
 // @Tags student-courses
 // @Accept json
 // @Produce json
-// @Param studentId path int true "Student ID"
-// @Param courseId path int true "Course ID"
 // @Param studentCourse body models.StudentCourse true "Updated student course information"
 // @Success 200 {object} models.StudentCourse
 // @Failure 400 {object} map[string]interface{}
@@ -325,26 +819,32 @@ func (h *StudentCourseController) UpdateStudentCourse(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	studentID, err := strconv.ParseUint(c.Param("studentId"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID format"})
-		return
-	}
-
-	courseID, err := strconv.ParseUint(c.Param("courseId"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
-		return
-	}
-
 	var sc models.StudentCourse
 	if err := c.ShouldBindJSON(&sc); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if sc.StudentID == 0 || sc.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "student_id and course_id are required"})
+		return
+	}
 
-	sc.StudentID = uint(studentID)
-	sc.CourseID = uint(courseID)
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.StudentCourse
+	if wantsDiff(c) {
+		err := h.db.QueryRowContext(ctx, getStudentCourseQuery, sc.StudentID, sc.CourseID).Scan(
+			&before.StudentID, &before.CourseID, &before.Grade, &before.Enrollment,
+			&before.AccessExpiresAt, &before.Certificate, &before.Issued, &before.Attempts,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Student course enrollment not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve student course enrollment"})
+			return
+		}
+	}
 
 	result, err := h.db.ExecContext(ctx, updateStudentCourseQuery,
 		sc.Grade, sc.Enrollment, sc.Certificate, sc.Issued,
@@ -365,7 +865,7 @@ func (h *StudentCourseController) UpdateStudentCourse(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, sc)
+	respondUpdated(c, before, sc)
 }
 
 // @Summary Delete student course enrollment
@@ -415,3 +915,541 @@ func (h *StudentCourseController) DeleteStudentCourse(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Student course enrollment deleted successfully"})
 }
+
+// @Summary Resend a certificate by email
+// @Description Email the authenticated student the certificate they earned on a course again, attached as a PDF. A certificate can be resent at most once every 10 minutes.
+// @Tags student-courses
+// @Accept json
+// @Produce json
+// @Param request body ResendCertificateRequest true "Course ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /student_courses/resendCertificate [post]
+func (h *StudentCourseController) ResendCertificate(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	studentID, ok, err := authenticatedStudentID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only students can request their certificate"})
+		return
+	}
+
+	var req ResendCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course ID is required"})
+		return
+	}
+
+	var cert certificate.Certificate
+	var email string
+	err = h.db.QueryRowContext(ctx, getCertificateRecipientQuery, studentID, req.CourseID).Scan(
+		&cert.Text, &cert.Grade, &cert.StudentName, &email, &cert.CourseName, &cert.Date,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Certificate not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve certificate"})
+		return
+	}
+
+	if !h.allowResend(studentID, req.CourseID) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Certificate was resent recently, please try again later"})
+		return
+	}
+
+	// Attach the same PDF DownloadCertificate serves
+	err = h.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Your DZ Skills certificate for " + cert.CourseName,
+		Body:    "Hello " + cert.StudentName + ",\n\nYour certificate for " + cert.CourseName + " is attached.\n",
+		Attachments: []mailer.Attachment{{
+			Filename:    fmt.Sprintf("certificate-%d.pdf", req.CourseID),
+			ContentType: "application/pdf",
+			Data:        certificate.RenderPDF(cert),
+		}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send certificate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Certificate sent to " + email})
+}
+
+// allowResend records a resend attempt and reports whether the cooldown since
+// the previous one for the same certificate is over. Entries whose cooldown
+// has passed are dropped so the map only holds recent resends.
+func (h *StudentCourseController) allowResend(studentID, courseID uint) bool {
+	h.resendMu.Lock()
+	defer h.resendMu.Unlock()
+
+	current := now()
+	for key, last := range h.lastResend {
+		if current.Sub(last) >= certificateResendCooldown {
+			delete(h.lastResend, key)
+		}
+	}
+
+	key := [2]uint{studentID, courseID}
+	if _, ok := h.lastResend[key]; ok {
+		return false
+	}
+	h.lastResend[key] = current
+	return true
+}
+
+// @Summary Get an exam submission
+// @Description Retrieve the answers given on the latest exam attempt of a course. Students see their own submission, teachers the submissions of students on the courses they own.
+// @Tags student-courses
+// @Accept json
+// @Produce json
+// @Param request body ExamSubmissionRequest true "Course and student"
+// @Success 200 {object} ExamSubmission
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /student_courses/GetExamSubmission [post]
+func (h *StudentCourseController) GetExamSubmission(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req ExamSubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course ID is required"})
+		return
+	}
+
+	studentID, ok, err := h.submissionStudentID(ctx, c, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify caller"})
+		return
+	}
+	if !ok {
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getLatestExamSubmissionQuery, studentID, req.CourseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exam submission"})
+		return
+	}
+	defer rows.Close()
+
+	submission := ExamSubmission{StudentID: studentID, CourseID: req.CourseID}
+	for rows.Next() {
+		var answer models.StudentExamAnswer
+		if err := rows.Scan(
+			&answer.ID, &answer.StudentID, &answer.CourseID, &answer.QuizzID,
+			&answer.ChosenAnswer, &answer.Correct, &answer.SubmittedAt,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exam submission"})
+			return
+		}
+		submission.SubmittedAt = answer.SubmittedAt
+		submission.Answers = append(submission.Answers, answer)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing exam submission"})
+		return
+	}
+	if len(submission.Answers) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam submission not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, submission)
+}
+
+// submissionStudentID resolves whose submission the caller may read. It writes
+// the error response itself and returns ok false when the caller is not allowed.
+func (h *StudentCourseController) submissionStudentID(ctx context.Context, c *gin.Context, req ExamSubmissionRequest) (uint, bool, error) {
+	studentID, isStudent, err := authenticatedStudentID(ctx, h.db, c)
+	if err != nil {
+		return 0, false, err
+	}
+	if isStudent {
+		if req.StudentID != 0 && req.StudentID != studentID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Cannot view the exam submission of another student"})
+			return 0, false, nil
+		}
+		return studentID, true, nil
+	}
+
+	teacherID, isTeacher, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		return 0, false, err
+	}
+	if !isTeacher {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only students and teachers can view exam submissions"})
+		return 0, false, nil
+	}
+	if req.StudentID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "student ID is required"})
+		return 0, false, nil
+	}
+
+	var ownerID uint
+	err = h.db.QueryRowContext(ctx, getCourseTeacherQuery, req.CourseID).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	if ownerID != teacherID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the course teacher can view its exam submissions"})
+		return 0, false, nil
+	}
+	return req.StudentID, true, nil
+}
+
+// @Summary Get exam history
+// @Description Retrieve every exam attempt of the authenticated student on a course, oldest first
+// @Tags student-courses
+// @Accept json
+// @Produce json
+// @Param request body ExamHistoryRequest true "Course ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of attempts per page (max 100)"
+// @Success 200 {array} models.ExamAttempt
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /student_courses/examHistory [post]
+func (h *StudentCourseController) GetExamHistory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	studentID, ok, err := authenticatedStudentID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only students can view their exam history"})
+		return
+	}
+
+	var req ExamHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course ID is required"})
+		return
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getExamHistoryQuery, studentID, req.CourseID, page.PageSize, page.Offset())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve exam history"})
+		return
+	}
+	defer rows.Close()
+
+	attempts := []models.ExamAttempt{}
+	for rows.Next() {
+		var attempt models.ExamAttempt
+		if err := rows.Scan(
+			&attempt.ID, &attempt.StudentID, &attempt.CourseID, &attempt.Attempt,
+			&attempt.Score, &attempt.Total, &attempt.Grade, &attempt.Passed, &attempt.SubmittedAt,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process exam history"})
+			return
+		}
+		attempts = append(attempts, attempt)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing exam history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, attempts)
+}
+
+// @Summary Get enrolled students
+// @Description Retrieve the students enrolled in a course, in enrollment order. Only the course teacher can see its roster.
+// @Tags student-courses
+// @Accept json
+// @Produce json
+// @Param request body EnrollmentRequest true "Course ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of students per page (max 100)"
+// @Success 200 {object} PagedResponse{data=[]EnrolledStudent}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /student_courses/enrolledStudents [post]
+func (h *StudentCourseController) GetEnrolledStudents(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req EnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course ID is required"})
+		return
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	teacherID, ok, err := authenticatedTeacherID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify teacher"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only teachers can view course rosters"})
+		return
+	}
+
+	var ownerID uint
+	err = h.db.QueryRowContext(ctx, getCourseTeacherQuery, req.CourseID).Scan(&ownerID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if ownerID != teacherID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the course teacher can view its roster"})
+		return
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(ctx, countEnrollmentsQuery, req.CourseID).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count enrolled students"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getEnrolledStudentsQuery, req.CourseID, page.PageSize, page.Offset())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve enrolled students"})
+		return
+	}
+	defer rows.Close()
+
+	students := []EnrolledStudent{}
+	for rows.Next() {
+		var student EnrolledStudent
+		if err := rows.Scan(
+			&student.ID, &student.FullName, &student.Email, &student.Grade, &student.Enrollment,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process enrolled students"})
+			return
+		}
+		students = append(students, student)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing enrolled students"})
+		return
+	}
+
+	c.JSON(http.StatusOK, PagedResponse{Data: students, Pagination: page.Meta(total)})
+}
+
+// @Summary Get enrollment count
+// @Description Retrieve the number of students enrolled in a course
+// @Tags student-courses
+// @Accept json
+// @Produce json
+// @Param request body EnrollmentRequest true "Course ID"
+// @Success 200 {object} EnrollmentCount
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /student_courses/enrollmentCount [post]
+func (h *StudentCourseController) GetEnrollmentCount(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req EnrollmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CourseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "course ID is required"})
+		return
+	}
+
+	// Verify course exists
+	var exists bool
+	err := h.db.QueryRowContext(ctx, courseExistsQuery, req.CourseID).Scan(&exists)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Course not found"})
+		return
+	}
+
+	result := EnrollmentCount{CourseID: req.CourseID}
+	if err := h.db.QueryRowContext(ctx, countEnrollmentsQuery, req.CourseID).Scan(&result.Count); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count enrollments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Get my courses
+// @Description Retrieve the enrollments of the authenticated student with their course details, most recent first
+// @Tags student-courses
+// @Produce json
+// @Success 200 {array} MyCourse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /student_courses/mine [get]
+func (h *StudentCourseController) GetMyCourses(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	// The student comes from the token, clients never pass their own ID
+	studentID, ok, err := authenticatedStudentID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only students have enrollments"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getMyCoursesQuery, studentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve student course enrollments"})
+		return
+	}
+	defer rows.Close()
+
+	courses := []MyCourse{}
+	for rows.Next() {
+		var mc MyCourse
+		sc, course := &mc.StudentCourse, &mc.Course
+		if err := rows.Scan(
+			&sc.StudentID, &sc.CourseID, &sc.Grade, &sc.Enrollment,
+			&sc.AccessExpiresAt, &sc.Certificate, &sc.Issued, &sc.Attempts,
+			&course.ID, &course.Name, &course.Description,
+			&course.Pricing, &course.Duration, &course.Image,
+			&course.Language, &course.Level, &course.AccessDays,
+			&course.TeacherID, &course.CategoryID, &course.SubCatID, &course.CreatedAt,
+			&course.AverageRating, &course.RatingsCount,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process student course enrollments"})
+			return
+		}
+		course.AverageRating = roundRating(course.AverageRating)
+		courses = append(courses, mc)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing student course enrollments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, courses)
+}
+
+// @Summary Download a certificate
+// @Description Render the certificate the authenticated student earned on a course as a PDF, shown inline by default or downloaded with disposition=attachment
+// @Tags student-courses
+// @Produce application/pdf
+// @Param course_id query int true "Course ID"
+// @Param disposition query string false "inline (default) or attachment"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /student_courses/certificate [get]
+func (h *StudentCourseController) DownloadCertificate(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	disposition := c.DefaultQuery("disposition", "inline")
+	if disposition != "inline" && disposition != "attachment" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "disposition must be inline or attachment"})
+		return
+	}
+
+	courseID, err := strconv.ParseUint(c.Query("course_id"), 10, 32)
+	if err != nil || courseID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid course ID format"})
+		return
+	}
+
+	studentID, ok, err := authenticatedStudentID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only students can download their certificate"})
+		return
+	}
+
+	var cert certificate.Certificate
+	err = h.db.QueryRowContext(ctx, getCertificateDetailsQuery, studentID, courseID).Scan(
+		&cert.Text, &cert.Grade, &cert.StudentName, &cert.CourseName, &cert.Date,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Certificate not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve certificate"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename=\"certificate-%d.pdf\"", disposition, courseID))
+	c.Data(http.StatusOK, "application/pdf", certificate.RenderPDF(cert))
+}
@@ -0,0 +1,758 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cuddest/dz-skills/mailer"
+)
+
+// expectGrading scripts a two question exam on course 7 for student 5 where
+// the first answer is right and the second wrong
+func expectGrading(f *fakeDB, maxAttempts int) {
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+	f.expect(getCourseExamQuery, 7).returns(row(3, false))
+	f.expect(countExamQuizzesQuery, 3).returns(row(2))
+	f.expect(incrementAttemptsQuery, 5, 7, maxAttempts).returns(row(1))
+	f.expect(getExamQuizzAnswerQuery, 1, 3).returns(row(2))
+	f.expect(createExamAnswerQuery)
+	f.expect(getExamQuizzAnswerQuery, 2, 3).returns(row(1))
+	f.expect(createExamAnswerQuery)
+	f.expect(createExamAttemptQuery)
+	f.expect(recordExamResultQuery)
+}
+
+func TestSubmitExamAnswersGradesBody(t *testing.T) {
+	db, f := newFakeDB(t)
+	h := NewStudentCourseController(db)
+
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	expectGrading(f, h.maxAttempts)
+
+	w := serve(t, h.SubmitExamAnswers, testRequest{
+		body:   `{"course_id": 7, "answers": [{"quizz_id": 1, "answer": 2}, {"quizz_id": 2, "answer": 3}]}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var result ExamResult
+	decodeBody(t, w, &result)
+	if result.Grade != "1/2" || !result.Passed || result.Attempts != 1 {
+		t.Errorf("result = %+v, want grade 1/2 passed on attempt 1", result)
+	}
+	if f.commits != 1 {
+		t.Errorf("commits = %d, want 1", f.commits)
+	}
+}
+
+// The access period counts from the enrollment date, a graded exam must not
+// restart it
+func TestRecordExamResultKeepsEnrollment(t *testing.T) {
+	if strings.Contains(recordExamResultQuery, "enrollment") {
+		t.Errorf("recordExamResultQuery changes the enrollment date: %s", recordExamResultQuery)
+	}
+}
+
+func TestSubmitExamAnswersIgnoresOtherStudentID(t *testing.T) {
+	db, f := newFakeDB(t)
+	h := NewStudentCourseController(db)
+
+	// Every statement is scripted for student 5, the token's student
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	expectGrading(f, h.maxAttempts)
+
+	w := serve(t, h.SubmitExamAnswers, testRequest{
+		body:   `{"course_id": 7, "student_id": 99, "answers": [{"quizz_id": 1, "answer": 2}, {"quizz_id": 2, "answer": 3}]}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+}
+
+func TestSubmitExamAnswersRejectsBadRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"missing course", `{"answers": [{"quizz_id": 1, "answer": 2}]}`, http.StatusBadRequest},
+		{"non-numeric course", `{"course_id": "seven", "answers": []}`, http.StatusBadRequest},
+		{"answers not a list", `{"course_id": 7, "answers": {"quizz_id": 1}}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+
+			w := serve(t, NewStudentCourseController(db).SubmitExamAnswers, testRequest{
+				body:   tt.body,
+				claims: studentClaims("alice"),
+			})
+			expectStatus(t, w, tt.status)
+		})
+	}
+}
+
+func TestSubmitExamAnswersRequiresStudent(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	w := serve(t, NewStudentCourseController(db).SubmitExamAnswers, testRequest{
+		body:   `{"course_id": 7, "answers": []}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusForbidden)
+}
+
+func TestUpdateStudentCourseTakesKeysFromBody(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(updateStudentCourseQuery, "A", time.Time{}, nil, true, 5, 7).affects(1)
+
+	w := serve(t, NewStudentCourseController(db).UpdateStudentCourse, testRequest{
+		method: http.MethodPut,
+		body:   `{"student_id": 5, "course_id": 7, "grade": "A", "issued": true}`,
+	})
+	expectStatus(t, w, http.StatusOK)
+}
+
+func TestUpdateStudentCourseRequiresKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing student", `{"course_id": 7, "grade": "A"}`},
+		{"missing course", `{"student_id": 5, "grade": "A"}`},
+		{"non-numeric course", `{"student_id": 5, "course_id": "seven"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newFakeDB(t)
+
+			w := serve(t, NewStudentCourseController(db).UpdateStudentCourse, testRequest{
+				method: http.MethodPut,
+				body:   tt.body,
+			})
+			expectStatus(t, w, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestGetStudentCourseReadsBody(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentCourseQuery, 5, 7).returns(row(5, 7, "A", time.Time{}, nil, nil, true, 2))
+
+	w := serve(t, NewStudentCourseController(db).GetStudentCourse, testRequest{
+		body: `{"student_id": 5, "course_id": 7}`,
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var got map[string]interface{}
+	decodeBody(t, w, &got)
+	if got["student_id"] != float64(5) || got["course_id"] != float64(7) || got["grade"] != "A" {
+		t.Errorf("response = %v, want student 5 on course 7 graded A", got)
+	}
+}
+
+func TestGetStudentCourseStanding(t *testing.T) {
+	tests := []struct {
+		name           string
+		standing       []interface{}
+		wantPercent    float64
+		wantPercentile float64
+		wantPassed     bool
+	}{
+		// Third of five submitters, three of them score at or below the student
+		{"mid ranked", row(6, 10, true, 0.6, 5), 60, 60, true},
+		{"lone submitter", row(3, 10, false, 1.0, 1), 30, 100, false},
+		{"ties rounded", row(2, 3, true, 2.0/3, 3), 66.67, 66.67, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+			f.expect(getStudentCourseQuery, 5, 7).returns(row(5, 7, "6/10", time.Time{}, nil, nil, true, 1))
+			f.expect(getExamStandingQuery, 5, 7).returns(tt.standing)
+
+			w := serve(t, NewStudentCourseController(db).GetStudentCourseStanding, testRequest{
+				body:   `{"course_id": 7}`,
+				claims: studentClaims("alice"),
+			})
+			expectStatus(t, w, http.StatusOK)
+
+			var got StudentCourseStanding
+			decodeBody(t, w, &got)
+			if got.Standing == nil {
+				t.Fatalf("standing is missing from %+v", got)
+			}
+			if got.Standing.Percent != tt.wantPercent || got.Standing.Percentile != tt.wantPercentile || got.Standing.Passed != tt.wantPassed {
+				t.Errorf("standing = %+v, want %v%% at percentile %v passed %v", *got.Standing, tt.wantPercent, tt.wantPercentile, tt.wantPassed)
+			}
+			if got.Standing.PassPercent != examPassPercent {
+				t.Errorf("pass percent = %d, want %d", got.Standing.PassPercent, examPassPercent)
+			}
+		})
+	}
+}
+
+func TestGetStudentCourseStandingBeforeExam(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getStudentCourseQuery, 5, 7).returns(row(5, 7, "", time.Time{}, nil, nil, false, 0))
+	f.expect(getExamStandingQuery, 5, 7)
+
+	w := serve(t, NewStudentCourseController(db).GetStudentCourseStanding, testRequest{
+		body:   `{"course_id": 7}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	got := responseKeys(t, w.Body.Bytes())
+	if standing, ok := got["standing"]; !ok || standing != nil {
+		t.Errorf("standing = %v, want null until the exam is submitted", standing)
+	}
+}
+
+func TestGetStudentCourseStandingNotEnrolled(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getStudentCourseQuery, 5, 7)
+
+	w := serve(t, NewStudentCourseController(db).GetStudentCourseStanding, testRequest{
+		body:   `{"course_id": 7}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestGetStudentCourseRejectsBadBodies(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"missing student", `{"course_id": 7}`},
+		{"missing course", `{"student_id": 5}`},
+		{"non-numeric student", `{"student_id": "five", "course_id": 7}`},
+		{"empty body", ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newFakeDB(t)
+
+			w := serve(t, NewStudentCourseController(db).GetStudentCourse, testRequest{body: tt.body})
+			expectStatus(t, w, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestGetStudentCourseNotFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentCourseQuery, 5, 7)
+
+	w := serve(t, NewStudentCourseController(db).GetStudentCourse, testRequest{
+		body: `{"student_id": 5, "course_id": 7}`,
+	})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestCreateStudentCourseRejectsDeletedCourse(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect("FROM courses WHERE id = $1 AND deleted_at IS NULL", 7)
+
+	w := serve(t, NewStudentCourseController(db).CreateStudentCourse, testRequest{
+		body: `{"student_id": 5, "course_id": 7}`,
+	})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+// recordingMailer keeps the messages it is asked to send
+type recordingMailer struct {
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// expectCertificate scripts the lookups of student 5's certificate on course 7
+func expectCertificate(f *fakeDB) {
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getCertificateRecipientQuery, 5, 7).returns(
+		row("DZ-7-5", "2/2", "Alice", "alice@example.com", "Go", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)),
+	)
+}
+
+func TestResendCertificateAttachesPDF(t *testing.T) {
+	db, f := newFakeDB(t)
+	h := NewStudentCourseController(db)
+	m := &recordingMailer{}
+	h.mailer = m
+	expectCertificate(f)
+
+	w := serve(t, h.ResendCertificate, testRequest{
+		body:   `{"course_id": 7}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	if len(m.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(m.sent))
+	}
+	msg := m.sent[0]
+	if msg.To != "alice@example.com" || len(msg.Attachments) != 1 {
+		t.Fatalf("message = %+v, want one attachment to alice", msg)
+	}
+	a := msg.Attachments[0]
+	if a.Filename != "certificate-7.pdf" || a.ContentType != "application/pdf" || !bytes.HasPrefix(a.Data, []byte("%PDF")) {
+		t.Errorf("attachment %s (%s) is not the certificate PDF", a.Filename, a.ContentType)
+	}
+}
+
+func TestResendCertificateNotFound(t *testing.T) {
+	db, f := newFakeDB(t)
+	h := NewStudentCourseController(db)
+	m := &recordingMailer{}
+	h.mailer = m
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getCertificateRecipientQuery, 5, 7)
+
+	w := serve(t, h.ResendCertificate, testRequest{
+		body:   `{"course_id": 7}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusNotFound)
+	if len(m.sent) != 0 {
+		t.Errorf("sent %d messages without a certificate", len(m.sent))
+	}
+}
+
+func TestResendCertificateCooldown(t *testing.T) {
+	clock := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now })
+
+	db, f := newFakeDB(t)
+	h := NewStudentCourseController(db)
+	h.mailer = &recordingMailer{}
+	resend := func(status int) {
+		t.Helper()
+		expectCertificate(f)
+		w := serve(t, h.ResendCertificate, testRequest{
+			body:   `{"course_id": 7}`,
+			claims: studentClaims("alice"),
+		})
+		expectStatus(t, w, status)
+	}
+
+	resend(http.StatusOK)
+	clock = clock.Add(certificateResendCooldown - time.Second)
+	resend(http.StatusTooManyRequests)
+	clock = clock.Add(time.Second)
+	resend(http.StatusOK)
+}
+
+func TestAllowResendEvictsExpiredEntries(t *testing.T) {
+	clock := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now })
+
+	h := NewStudentCourseController(nil)
+	for course := uint(1); course <= 3; course++ {
+		h.allowResend(5, course)
+	}
+	clock = clock.Add(certificateResendCooldown)
+	h.allowResend(6, 1)
+
+	if len(h.lastResend) != 1 {
+		t.Errorf("lastResend holds %d entries, want only the fresh one", len(h.lastResend))
+	}
+}
+
+func TestSubmitExamAnswersAfterExpiry(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC)
+	setClock(t, expiresAt.Add(time.Minute))
+
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(expiresAt))
+
+	w := serve(t, NewStudentCourseController(db).SubmitExamAnswers, testRequest{
+		body:   `{"course_id": 7, "answers": [{"quizz_id": 1, "answer": 2}]}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusForbidden)
+}
+
+func TestSubmitExamAnswersArchivedExam(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+	f.expect(getCourseExamQuery, 7).returns(row(3, true))
+
+	// No attempt is scripted, so starting one fails the test
+	w := serve(t, NewStudentCourseController(db).SubmitExamAnswers, testRequest{
+		body:   `{"course_id": 7, "answers": [{"quizz_id": 1, "answer": 2}]}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusConflict)
+}
+
+func TestGetExamHistoryOfArchivedExam(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	// History is read from the attempts alone, the exam's archived flag is never consulted
+	f.expect(getExamHistoryQuery).returns(row(11, 5, 7, 1, 3, 4, "B", true, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)))
+
+	w := serve(t, NewStudentCourseController(db).GetExamHistory, testRequest{
+		body:   `{"course_id": 7}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var history []map[string]interface{}
+	decodeBody(t, w, &history)
+	if len(history) != 1 || history[0]["passed"] != true {
+		t.Errorf("history = %v, want the past passed attempt", history)
+	}
+}
+
+func TestSubmitExamAnswersStoresIdempotencyKey(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	setClock(t, at)
+
+	db, f := newFakeDB(t)
+	h := NewStudentCourseController(db)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAttemptByIdempotencyKeyQuery, 5, 7, "retry-1")
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+	f.expect(getCourseExamQuery, 7).returns(row(3, false))
+	f.expect(countExamQuizzesQuery, 3).returns(row(1))
+	f.expect(lockStudentCourseQuery, 5, 7).affects(1)
+	f.expect(getAttemptByIdempotencyKeyQuery, 5, 7, "retry-1")
+	f.expect(incrementAttemptsQuery, 5, 7, h.maxAttempts).returns(row(1))
+	f.expect(getExamQuizzAnswerQuery, 1, 3).returns(row(2))
+	f.expect(createExamAnswerQuery)
+	f.expect(createExamAttemptQuery, 5, 7, 1, 1, 1, "1/1", true, at, "retry-1")
+	f.expect(recordExamResultQuery, "1/1", certificatePlaceholder, true, 5, 7)
+
+	w := serve(t, h.SubmitExamAnswers, testRequest{
+		body:    `{"course_id": 7, "answers": [{"quizz_id": 1, "answer": 2}]}`,
+		headers: map[string]string{"Idempotency-Key": "retry-1"},
+		claims:  studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+	if w.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("first submission is marked as replayed")
+	}
+}
+
+func TestSubmitExamAnswersReplaysDuplicateKey(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAttemptByIdempotencyKeyQuery, 5, 7, "retry-1").returns(row(1, "1/2", true))
+
+	// Nothing else is scripted, so grading again or counting an attempt fails the test
+	w := serve(t, NewStudentCourseController(db).SubmitExamAnswers, testRequest{
+		body:    `{"course_id": 7, "answers": [{"quizz_id": 1, "answer": 1}, {"quizz_id": 2, "answer": 1}]}`,
+		headers: map[string]string{"Idempotency-Key": "retry-1"},
+		claims:  studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var result ExamResult
+	decodeBody(t, w, &result)
+	if result.Grade != "1/2" || !result.Passed || result.Attempts != 1 {
+		t.Errorf("result = %+v, want the original 1/2 passed on attempt 1", result)
+	}
+	if w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("replayed result is not marked as replayed")
+	}
+}
+
+func TestSubmitExamAnswersReplaysConcurrentDuplicate(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getAttemptByIdempotencyKeyQuery, 5, 7, "retry-1")
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+	f.expect(getCourseExamQuery, 7).returns(row(3, false))
+	f.expect(countExamQuizzesQuery, 3).returns(row(1))
+	f.expect(lockStudentCourseQuery, 5, 7).affects(1)
+	// The other request graded the exam while this one waited for the lock
+	f.expect(getAttemptByIdempotencyKeyQuery, 5, 7, "retry-1").returns(row(2, "0/1", false))
+
+	w := serve(t, NewStudentCourseController(db).SubmitExamAnswers, testRequest{
+		body:    `{"course_id": 7, "answers": [{"quizz_id": 1, "answer": 2}]}`,
+		headers: map[string]string{"Idempotency-Key": "retry-1"},
+		claims:  studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var result ExamResult
+	decodeBody(t, w, &result)
+	if result.Grade != "0/1" || result.Attempts != 2 {
+		t.Errorf("result = %+v, want the concurrent 0/1 on attempt 2", result)
+	}
+	if f.commits != 0 || f.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want the transaction rolled back", f.commits, f.rollbacks)
+	}
+}
+
+func TestSubmitExamAnswersRejectsLongIdempotencyKey(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+
+	w := serve(t, NewStudentCourseController(db).SubmitExamAnswers, testRequest{
+		body:    `{"course_id": 7, "answers": [{"quizz_id": 1, "answer": 2}]}`,
+		headers: map[string]string{"Idempotency-Key": strings.Repeat("k", maxIdempotencyKeyLength+1)},
+		claims:  studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestResendCertificateWithNoopMailer(t *testing.T) {
+	t.Setenv("SMTP_HOST", "")
+	db, f := newFakeDB(t)
+	expectCertificate(f)
+
+	h := NewStudentCourseController(db)
+	if _, ok := h.mailer.(mailer.NoopMailer); !ok {
+		t.Fatalf("mailer = %T, want the no-op mailer without SMTP_HOST", h.mailer)
+	}
+	w := serve(t, h.ResendCertificate, testRequest{
+		body:   `{"course_id": 7}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+}
+
+// responseKeys decodes a JSON object response and returns its keys
+func responseKeys(t *testing.T, body []byte) map[string]interface{} {
+	t.Helper()
+	var got map[string]interface{}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("decode %s: %v", body, err)
+	}
+	return got
+}
+
+func TestExamResultShapeIsStable(t *testing.T) {
+	submit := func(answers string) map[string]interface{} {
+		t.Helper()
+		db, f := newFakeDB(t)
+		h := NewStudentCourseController(db)
+		f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+		expectGrading(f, h.maxAttempts)
+
+		w := serve(t, h.SubmitExamAnswers, testRequest{
+			body:   `{"course_id": 7, "answers": ` + answers + `}`,
+			claims: studentClaims("alice"),
+		})
+		expectStatus(t, w, http.StatusOK)
+		return responseKeys(t, w.Body.Bytes())
+	}
+
+	passed := submit(`[{"quizz_id": 1, "answer": 2}, {"quizz_id": 2, "answer": 3}]`)
+	failed := submit(`[{"quizz_id": 1, "answer": 3}, {"quizz_id": 2, "answer": 3}]`)
+
+	if passed["passed"] != true || failed["passed"] != false {
+		t.Fatalf("passed = %v, failed = %v, want one pass and one fail", passed, failed)
+	}
+	for key := range passed {
+		if _, ok := failed[key]; !ok {
+			t.Errorf("failed result is missing %q", key)
+		}
+	}
+	if len(passed) != len(failed) {
+		t.Errorf("keys differ: passed %v, failed %v", passed, failed)
+	}
+	if failed["issued"] != false || failed["certificate"] != nil {
+		t.Errorf("failed result = %v, want issued false and a null certificate", failed)
+	}
+	if passed["issued"] != true || passed["certificate"] == nil {
+		t.Errorf("passed result = %v, want issued true and a certificate", passed)
+	}
+}
+
+func TestEnrollmentShapeIsStable(t *testing.T) {
+	// A fresh enrollment has no certificate yet
+	db, f := newFakeDB(t)
+	f.expect(getCourseAccessDaysQuery, 7).returns(row(0))
+	f.expect(createStudentCourseQuery).affects(1)
+	w := serve(t, NewStudentCourseController(db).CreateStudentCourse, testRequest{
+		body: `{"student_id": 5, "course_id": 7}`,
+	})
+	expectStatus(t, w, http.StatusCreated)
+	created := responseKeys(t, w.Body.Bytes())
+
+	// A passed one read back has
+	db, f = newFakeDB(t)
+	f.expect(getStudentCourseQuery, 5, 7).returns(row(5, 7, "2/2", time.Time{}, nil, "DZ-7-5", true, 1))
+	w = serve(t, NewStudentCourseController(db).GetStudentCourse, testRequest{
+		body: `{"student_id": 5, "course_id": 7}`,
+	})
+	expectStatus(t, w, http.StatusOK)
+	issued := responseKeys(t, w.Body.Bytes())
+
+	for _, key := range []string{"issued", "certificate"} {
+		if _, ok := created[key]; !ok {
+			t.Errorf("created enrollment is missing %q", key)
+		}
+		if _, ok := issued[key]; !ok {
+			t.Errorf("issued enrollment is missing %q", key)
+		}
+	}
+	if created["certificate"] != nil || issued["certificate"] != "DZ-7-5" {
+		t.Errorf("certificates = %v and %v, want null and DZ-7-5", created["certificate"], issued["certificate"])
+	}
+	if len(created) != len(issued) {
+		t.Errorf("keys differ: created %v, issued %v", created, issued)
+	}
+}
+
+func TestGetExamHistoryIsChronological(t *testing.T) {
+	first := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getExamHistoryQuery, 5, 7, 2, 2).returns(
+		row(11, 5, 7, 3, 1, 4, "F", false, first),
+		row(12, 5, 7, 4, 3, 4, "B", true, first.Add(48*time.Hour)),
+	)
+
+	w := serve(t, NewStudentCourseController(db).GetExamHistory, testRequest{
+		body:   `{"course_id": 7}`,
+		query:  "page=2&page_size=2",
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var history []struct {
+		Attempt     int       `json:"attempt"`
+		Score       int       `json:"score"`
+		Passed      bool      `json:"passed"`
+		SubmittedAt time.Time `json:"submitted_at"`
+	}
+	decodeBody(t, w, &history)
+	if len(history) != 2 {
+		t.Fatalf("got %d attempts, want 2", len(history))
+	}
+	if history[0].Attempt != 3 || history[0].Passed || history[1].Attempt != 4 || !history[1].Passed {
+		t.Errorf("history = %+v, want a failed attempt 3 then a passed attempt 4", history)
+	}
+	if !history[0].SubmittedAt.Before(history[1].SubmittedAt) {
+		t.Errorf("history is not oldest first: %v then %v", history[0].SubmittedAt, history[1].SubmittedAt)
+	}
+}
+
+func TestGetExamHistoryRequiresStudent(t *testing.T) {
+	db, _ := newFakeDB(t)
+	w := serve(t, NewStudentCourseController(db).GetExamHistory, testRequest{
+		body:   `{"course_id": 7}`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusForbidden)
+}
+
+func TestDownloadCertificateDisposition(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"course_id=7", `inline; filename="certificate-7.pdf"`},
+		{"course_id=7&disposition=inline", `inline; filename="certificate-7.pdf"`},
+		{"course_id=7&disposition=attachment", `attachment; filename="certificate-7.pdf"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+			f.expect(getCertificateDetailsQuery, 5, 7).returns(
+				row("DZ-7-5", "2/2", "Alice", "Go", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)),
+			)
+
+			w := serve(t, NewStudentCourseController(db).DownloadCertificate, testRequest{
+				method: http.MethodGet,
+				query:  tt.query,
+				claims: studentClaims("alice"),
+			})
+			expectStatus(t, w, http.StatusOK)
+			if got := w.Header().Get("Content-Type"); got != "application/pdf" {
+				t.Errorf("Content-Type = %q, want application/pdf", got)
+			}
+			if got := w.Header().Get("Content-Disposition"); got != tt.want {
+				t.Errorf("Content-Disposition = %q, want %q", got, tt.want)
+			}
+			if !bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-")) {
+				t.Errorf("body does not start like a PDF: %.20q", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDownloadCertificateRejectsUnknownDisposition(t *testing.T) {
+	db, _ := newFakeDB(t)
+	w := serve(t, NewStudentCourseController(db).DownloadCertificate, testRequest{
+		method: http.MethodGet,
+		query:  "course_id=7&disposition=preview",
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestCreateStudentCourseAdminImportKeepsProgress(t *testing.T) {
+	setClock(t, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC))
+	enrolled := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	db, f := newFakeDB(t)
+	f.expect(getCourseAccessDaysQuery, 7).returns(row(0))
+	f.expect(createStudentCourseQuery, 5, 7, "8/10", enrolled, nil, "DZ-7-5", true).affects(1)
+
+	w := serve(t, NewStudentCourseController(db).CreateStudentCourse, testRequest{
+		body: `{"student_id": 5, "course_id": 7, "grade": "8/10", "issued": true,
+			"certificate": "DZ-7-5", "enrollment": "2025-09-01T00:00:00Z"}`,
+		claims: adminClaims("root"),
+	})
+	expectStatus(t, w, http.StatusCreated)
+
+	var got map[string]interface{}
+	decodeBody(t, w, &got)
+	if got["grade"] != "8/10" || got["issued"] != true || got["certificate"] != "DZ-7-5" {
+		t.Errorf("enrollment = %v, want the imported grade and certificate", got)
+	}
+}
+
+func TestCreateStudentCourseResetsProgress(t *testing.T) {
+	at := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	setClock(t, at)
+	db, f := newFakeDB(t)
+	f.expect(getCourseAccessDaysQuery, 7).returns(row(30))
+	f.expect(createStudentCourseQuery, 5, 7, "", at, at.AddDate(0, 0, 30), nil, false).affects(1)
+
+	w := serve(t, NewStudentCourseController(db).CreateStudentCourse, testRequest{
+		body: `{"student_id": 5, "course_id": 7, "grade": "8/10", "issued": true,
+			"certificate": "DZ-7-5", "enrollment": "2025-09-01T00:00:00Z"}`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusCreated)
+
+	var got map[string]interface{}
+	decodeBody(t, w, &got)
+	if got["grade"] != "" || got["issued"] != false || got["certificate"] != nil {
+		t.Errorf("enrollment = %v, want a fresh enrollment", got)
+	}
+}
+
+func TestCreateStudentCourseAdminImportValidatesGrade(t *testing.T) {
+	for _, body := range []string{
+		`{"student_id": 5, "course_id": 7, "grade": "eight"}`,
+		`{"student_id": 5, "course_id": 7, "grade": "11/10"}`,
+		`{"student_id": 5, "course_id": 7, "grade": "8/10", "issued": true}`,
+	} {
+		db, f := newFakeDB(t)
+		f.expect(getCourseAccessDaysQuery, 7).returns(row(0))
+
+		w := serve(t, NewStudentCourseController(db).CreateStudentCourse, testRequest{body: body, claims: adminClaims("root")})
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+}
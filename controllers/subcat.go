@@ -35,6 +35,13 @@ const (
 		SET name = $1, category_id = $2 
 		WHERE id = $3`
 
+	// Names are unique per category, ignoring case; $3 excludes the subcategory being updated
+	subCatNameTakenQuery = `
+		SELECT EXISTS(
+			SELECT 1 FROM sub_cats 
+			WHERE category_id = $1 AND LOWER(name) = LOWER($2) AND id <> $3
+		)`
+
 	deleteSubCatQuery = `
 		DELETE FROM sub_cats WHERE id = $1`
 )
@@ -57,6 +64,13 @@ func (h *SubCatController) validateSubCat(subcat *models.SubCat) error {
 	return nil
 }
 
+// nameTaken reports whether another subcategory of the category already uses the name
+func (h *SubCatController) nameTaken(ctx context.Context, subcat *models.SubCat, excludeID uint) (bool, error) {
+	var taken bool
+	err := h.db.QueryRowContext(ctx, subCatNameTakenQuery, subcat.CategoryID, subcat.Name, excludeID).Scan(&taken)
+	return taken, err
+}
+
 // @Summary Create a new subcategory
 // @Description Create a new subcategory with the provided information
 // @Tags subcategories
@@ -66,6 +80,7 @@ func (h *SubCatController) validateSubCat(subcat *models.SubCat) error {
 // @Success 201 {object} models.SubCat
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /subcats/createSubCat [post]
 func (h *SubCatController) CreateSubCat(c *gin.Context) {
@@ -95,25 +110,23 @@ func (h *SubCatController) CreateSubCat(c *gin.Context) {
 		return
 	}
 
-	// Create subcategory
-	tx, err := h.db.BeginTx(ctx, nil)
+	taken, err := h.nameTaken(ctx, &subcat, 0)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify subcategory name"})
 		return
 	}
-	defer tx.Rollback()
-
-	// Insert subcategory
-	err = tx.QueryRowContext(ctx, createSubCatQuery,
-		subcat.Name, subcat.CategoryID).Scan(&subcat.ID)
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create subcategory"})
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "A subcategory with this name already exists in the category"})
 		return
 	}
 
-	if err = tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+	// Create subcategory
+	err = withTx(ctx, h.db, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, createSubCatQuery,
+			subcat.Name, subcat.CategoryID).Scan(&subcat.ID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create subcategory"})
 		return
 	}
 
@@ -125,7 +138,7 @@ func (h *SubCatController) CreateSubCat(c *gin.Context) {
 // @Tags subcategories
 // @Accept json
 // @Produce json
-// @Param id path int true "Subcategory ID"
+// @Param request body IDRequest true "Subcategory ID"
 // @Success 200 {object} models.SubCat
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -135,14 +148,13 @@ func (h *SubCatController) GetSubCat(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var subcat models.SubCat
-	err = h.db.QueryRowContext(ctx, getSubCatQuery, id).Scan(
+	err := h.db.QueryRowContext(ctx, getSubCatQuery, id).Scan(
 		&subcat.ID, &subcat.Name, &subcat.CategoryID,
 	)
 
@@ -262,28 +274,27 @@ func (h *SubCatController) GetSubCatsByCategory(c *gin.Context) {
 // @Tags subcategories
 // @Accept json
 // @Produce json
-// @Param id path int true "Subcategory ID"
 // @Param subcategory body models.SubCat true "Updated subcategory information"
 // @Success 200 {object} models.SubCat
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /subcats/updateSubCat [put]
 func (h *SubCatController) UpdateSubCat(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var subcat models.SubCat
 	if err := c.ShouldBindJSON(&subcat); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if subcat.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := subcat.ID
 
 	if err := h.validateSubCat(&subcat); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -292,7 +303,7 @@ func (h *SubCatController) UpdateSubCat(c *gin.Context) {
 
 	// Verify category exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)", subcat.CategoryID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)", subcat.CategoryID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify category"})
 		return
@@ -302,6 +313,32 @@ func (h *SubCatController) UpdateSubCat(c *gin.Context) {
 		return
 	}
 
+	taken, err := h.nameTaken(ctx, &subcat, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify subcategory name"})
+		return
+	}
+	if taken {
+		c.JSON(http.StatusConflict, gin.H{"error": "A subcategory with this name already exists in the category"})
+		return
+	}
+
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.SubCat
+	if wantsDiff(c) {
+		err = h.db.QueryRowContext(ctx, getSubCatQuery, id).Scan(
+			&before.ID, &before.Name, &before.CategoryID,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Subcategory not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve subcategory"})
+			return
+		}
+	}
+
 	result, err := h.db.ExecContext(ctx, updateSubCatQuery,
 		subcat.Name, subcat.CategoryID, id)
 
@@ -320,8 +357,7 @@ func (h *SubCatController) UpdateSubCat(c *gin.Context) {
 		return
 	}
 
-	subcat.ID = uint(id)
-	c.JSON(http.StatusOK, subcat)
+	respondUpdated(c, before, subcat)
 }
 
 // @Summary Delete a subcategory
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCreateSubCatRejectsDuplicateName(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(categoryExistsInlineQuery, 2).returns(row(true))
+	f.expect(subCatNameTakenQuery, 2, "backend", 0).returns(row(true))
+
+	w := serve(t, NewSubCatController(db).CreateSubCat, testRequest{body: `{"Name": "backend", "category_id": 2}`})
+	expectStatus(t, w, http.StatusConflict)
+}
+
+func TestCreateSubCatAllowsNameUnderOtherCategory(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(categoryExistsInlineQuery, 3).returns(row(true))
+	f.expect(subCatNameTakenQuery, 3, "Backend", 0).returns(row(false))
+	f.expect(createSubCatQuery, "Backend", 3).returns(row(8))
+
+	w := serve(t, NewSubCatController(db).CreateSubCat, testRequest{body: `{"Name": "Backend", "category_id": 3}`})
+	expectStatus(t, w, http.StatusCreated)
+}
+
+func TestUpdateSubCatExcludesItselfFromNameCheck(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(categoryExistsInlineQuery, 2).returns(row(true))
+	f.expect(subCatNameTakenQuery, 2, "Backend", 8).returns(row(false))
+	f.expect(updateSubCatQuery, "Backend", 2, 8).affects(1)
+
+	w := serve(t, NewSubCatController(db).UpdateSubCat, testRequest{
+		method: http.MethodPut,
+		body:   `{"ID": 8, "Name": "Backend", "category_id": 2}`,
+	})
+	expectStatus(t, w, http.StatusOK)
+}
+
+func TestUpdateSubCatRejectsDuplicateName(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(categoryExistsInlineQuery, 2).returns(row(true))
+	f.expect(subCatNameTakenQuery, 2, "Backend", 8).returns(row(true))
+
+	w := serve(t, NewSubCatController(db).UpdateSubCat, testRequest{
+		method: http.MethodPut,
+		body:   `{"ID": 8, "Name": "Backend", "category_id": 2}`,
+	})
+	expectStatus(t, w, http.StatusConflict)
+}
@@ -4,10 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/mail"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/cuddest/dz-skills/config"
 	"github.com/cuddest/dz-skills/models"
 	"github.com/gin-gonic/gin"
 )
@@ -42,8 +48,37 @@ const (
 
 	checkEmailQuery = `
 		SELECT EXISTS(SELECT 1 FROM teachers WHERE email = $1 AND id != $2)`
+
+	getTeachersByCategoryQuery = `
+		SELECT t.id, t.full_name, t.username, t.picture, t.skills, t.degrees, t.experience, 
+			COALESCE(AVG(r.rating), 0), COUNT(r.rating) 
+		FROM teachers t 
+		LEFT JOIN courses tc ON tc.teacher_id = t.id AND tc.deleted_at IS NULL 
+		LEFT JOIN cratings r ON r.course_id = tc.id 
+		WHERE EXISTS(SELECT 1 FROM courses c WHERE c.teacher_id = t.id AND c.category_id = $1 AND c.deleted_at IS NULL) 
+		GROUP BY t.id 
+		ORDER BY t.id ASC 
+		LIMIT $2 OFFSET $3`
 )
 
+// TeachersByCategoryRequest identifies the category to list teachers for
+type TeachersByCategoryRequest struct {
+	CategoryID uint `json:"category_id"`
+}
+
+// CategoryTeacher is the public profile of a teacher with the rating of all their courses
+type CategoryTeacher struct {
+	ID            uint    `json:"ID"`
+	FullName      string  `json:"FullName"`
+	Username      string  `json:"username"`
+	Picture       string  `json:"Picture"`
+	Skills        string  `json:"Skills"`
+	Degrees       string  `json:"Degree"`
+	Experience    string  `json:"Experience"`
+	AverageRating float64 `json:"average_rating"` // rounded to 2 decimals
+	TotalRatings  int     `json:"total_ratings"`
+}
+
 // TeacherController handles HTTP requests for Teacher operations
 type TeacherController struct {
 	db *sql.DB
@@ -62,13 +97,57 @@ func (h *TeacherController) validateTeacher(teacher *models.Teacher) error {
 	if teacher.Username == "" {
 		return errors.New("username is required")
 	}
-	if teacher.Email == "" {
+	if err := validateEmail(teacher.Email); err != nil {
+		return err
+	}
+	if teacher.ID == 0 { // Only require password for new teachers
+		if err := validatePassword(teacher.Password); err != nil {
+			return err
+		}
+	}
+	// Add more validation as needed
+	return nil
+}
+
+// validateEmail checks that email is a bare address such as name@example.com,
+// without a display name or surrounding whitespace
+func validateEmail(email string) error {
+	if email == "" {
 		return errors.New("email is required")
 	}
-	if teacher.Password == "" && teacher.ID == 0 { // Only require password for new teachers
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return errors.New("email must be a valid address such as name@example.com")
+	}
+	return nil
+}
+
+// validatePassword checks a new password against the strength rules, naming
+// every rule it breaks so the UI can show them all at once
+func validatePassword(password string) error {
+	if password == "" {
 		return errors.New("password is required")
 	}
-	// Add more validation as needed
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+
+	var failed []string
+	if minLength := config.PasswordMinLength(); utf8.RuneCountInString(password) < minLength {
+		failed = append(failed, fmt.Sprintf("be at least %d characters long", minLength))
+	}
+	if !hasLetter {
+		failed = append(failed, "contain a letter")
+	}
+	if !hasDigit {
+		failed = append(failed, "contain a digit")
+	}
+	if len(failed) > 0 {
+		return errors.New("password must " + strings.Join(failed, ", "))
+	}
 	return nil
 }
 
@@ -133,27 +212,17 @@ func (h *TeacherController) CreateTeacher(c *gin.Context) {
 		return
 	}
 
-	tx, err := h.db.BeginTx(ctx, nil)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
-	defer tx.Rollback()
-
-	err = tx.QueryRowContext(ctx, createTeacherQuery,
-		teacher.FullName, teacher.Username, teacher.Email,
-		teacher.Password, teacher.Picture, teacher.Skills,
-		teacher.Degrees, teacher.Experience).Scan(&teacher.ID)
+	err := withTx(ctx, h.db, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, createTeacherQuery,
+			teacher.FullName, teacher.Username, teacher.Email,
+			teacher.Password, teacher.Picture, teacher.Skills,
+			teacher.Degrees, teacher.Experience).Scan(&teacher.ID)
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create teacher"})
 		return
 	}
 
-	if err = tx.Commit(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
-		return
-	}
-
 	// Clear sensitive data before sending response
 	teacher.Password = ""
 	c.JSON(http.StatusCreated, teacher)
@@ -164,7 +233,7 @@ func (h *TeacherController) CreateTeacher(c *gin.Context) {
 // @Tags teachers
 // @Accept json
 // @Produce json
-// @Param id path int true "Teacher ID"
+// @Param request body IDRequest true "Teacher ID"
 // @Security ApiKeyAuth
 // @Success 200 {object} models.Teacher
 // @Failure 400 {object} map[string]interface{}
@@ -175,14 +244,13 @@ func (h *TeacherController) GetTeacher(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var teacher models.Teacher
-	err = h.db.QueryRowContext(ctx, getTeacherQuery, id).Scan(
+	err := h.db.QueryRowContext(ctx, getTeacherQuery, id).Scan(
 		&teacher.ID, &teacher.FullName, &teacher.Username,
 		&teacher.Email, &teacher.Password, &teacher.Picture,
 		&teacher.Skills, &teacher.Degrees, &teacher.Experience,
@@ -203,26 +271,68 @@ func (h *TeacherController) GetTeacher(c *gin.Context) {
 }
 
 // @Summary Get all teachers
-// @Description Retrieve all teachers
+// @Description Retrieve a page of teachers, optionally filtered by name, skill and whether they give any course
 // @Tags teachers
 // @Accept json
 // @Produce json
+// @Param name query string false "Part of the teacher's full name, case insensitive"
+// @Param skill query string false "Part of the teacher's skills, case insensitive"
+// @Param has_courses query bool false "Only teachers with (true) or without (false) a course"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of teachers per page (max 100)"
 // @Security ApiKeyAuth
 // @Success 200 {array} models.Teacher
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /teachers/all [get]
 func (h *TeacherController) GetAllTeachers(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	rows, err := h.db.QueryContext(ctx, getAllTeachersQuery)
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var conditions []string
+	var args []interface{}
+	if name := strings.TrimSpace(c.Query("name")); name != "" {
+		args = append(args, name)
+		conditions = append(conditions, fmt.Sprintf("full_name ILIKE '%%' || $%d || '%%'", len(args)))
+	}
+	if skill := strings.TrimSpace(c.Query("skill")); skill != "" {
+		args = append(args, skill)
+		conditions = append(conditions, fmt.Sprintf("skills ILIKE '%%' || $%d || '%%'", len(args)))
+	}
+	if raw := c.Query("has_courses"); raw != "" {
+		hasCourses, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "has_courses must be true or false"})
+			return
+		}
+		exists := "EXISTS(SELECT 1 FROM courses c WHERE c.teacher_id = teachers.id AND c.deleted_at IS NULL)"
+		if !hasCourses {
+			exists = "NOT " + exists
+		}
+		conditions = append(conditions, exists)
+	}
+
+	query := getAllTeachersQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, page.PageSize, page.Offset())
+	query += fmt.Sprintf(" ORDER BY id ASC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := h.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve teachers"})
 		return
 	}
 	defer rows.Close()
 
-	var teachers []models.Teacher
+	teachers := []models.Teacher{}
 	for rows.Next() {
 		var teacher models.Teacher
 		if err := rows.Scan(
@@ -263,19 +373,17 @@ func (h *TeacherController) UpdateTeacher(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var teacher models.Teacher
 	if err := c.ShouldBindJSON(&teacher); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if teacher.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := teacher.ID
 
-	teacher.ID = uint(id)
 	if err := h.validateTeacher(&teacher); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -297,6 +405,25 @@ func (h *TeacherController) UpdateTeacher(c *gin.Context) {
 		teacher.Password = currentPassword
 	}
 
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Teacher
+	if wantsDiff(c) {
+		err := h.db.QueryRowContext(ctx, getTeacherQuery, id).Scan(
+			&before.ID, &before.FullName, &before.Username,
+			&before.Email, &before.Password, &before.Picture,
+			&before.Skills, &before.Degrees, &before.Experience,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Teacher not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve teacher"})
+			return
+		}
+		before.Password = ""
+	}
+
 	result, err := h.db.ExecContext(ctx, updateTeacherQuery,
 		teacher.FullName, teacher.Username, teacher.Email,
 		teacher.Password, teacher.Picture, teacher.Skills,
@@ -318,7 +445,7 @@ func (h *TeacherController) UpdateTeacher(c *gin.Context) {
 	}
 
 	teacher.Password = "" // Clear sensitive data
-	c.JSON(http.StatusOK, teacher)
+	respondUpdated(c, before, teacher)
 }
 
 // @Summary Delete a teacher
@@ -361,3 +488,79 @@ func (h *TeacherController) DeleteTeacher(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Teacher deleted successfully"})
 }
+
+// @Summary Get teachers by category
+// @Description List the teachers with at least one course in a category, along with the average rating of all their courses
+// @Tags teachers
+// @Accept json
+// @Produce json
+// @Param request body TeachersByCategoryRequest true "Category ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param page_size query int false "Number of teachers per page (max 100)"
+// @Success 200 {array} CategoryTeacher
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /teachers/byCategory [post]
+func (h *TeacherController) GetTeachersByCategory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	var req TeachersByCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.CategoryID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "valid category ID is required"})
+		return
+	}
+
+	page, err := parsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Verify category exists
+	var exists bool
+	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)", req.CategoryID).Scan(&exists)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify category"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, getTeachersByCategoryQuery, req.CategoryID, page.PageSize, page.Offset())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve teachers"})
+		return
+	}
+	defer rows.Close()
+
+	teachers := []CategoryTeacher{}
+	for rows.Next() {
+		var teacher CategoryTeacher
+		if err := rows.Scan(
+			&teacher.ID, &teacher.FullName, &teacher.Username,
+			&teacher.Picture, &teacher.Skills, &teacher.Degrees, &teacher.Experience,
+			&teacher.AverageRating, &teacher.TotalRatings,
+		); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process teachers"})
+			return
+		}
+		teacher.AverageRating = roundRating(teacher.AverageRating)
+		teachers = append(teachers, teacher)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error processing teachers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, teachers)
+}
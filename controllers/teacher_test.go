@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const categoryExistsInlineQuery = "SELECT EXISTS(SELECT 1 FROM categories WHERE id = $1)"
+
+func TestGetTeachersByCategoryListsEveryTeacher(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(categoryExistsInlineQuery, 2).returns(row(true))
+	f.expect(getTeachersByCategoryQuery, 2, defaultPageSize, 0).returns(
+		row(3, "Bob Teacher", "bob", "bob.png", "Go", "MSc", "10 years", 4.6666, 3),
+		row(4, "Carol Teacher", "carol", "", "Rust", "PhD", "5 years", 0, 0),
+	)
+
+	w := serve(t, NewTeacherController(db).GetTeachersByCategory, testRequest{body: `{"category_id": 2}`})
+	expectStatus(t, w, http.StatusOK)
+
+	var teachers []CategoryTeacher
+	decodeBody(t, w, &teachers)
+	if len(teachers) != 2 {
+		t.Fatalf("got %d teachers, want 2", len(teachers))
+	}
+	if teachers[0].Username != "bob" || teachers[0].AverageRating != 4.67 || teachers[0].TotalRatings != 3 {
+		t.Errorf("first teacher = %+v, want bob rated 4.67 over 3 ratings", teachers[0])
+	}
+	if teachers[1].Username != "carol" || teachers[1].TotalRatings != 0 {
+		t.Errorf("second teacher = %+v, want carol without ratings", teachers[1])
+	}
+}
+
+func TestGetTeachersByCategoryEmpty(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(categoryExistsInlineQuery, 2).returns(row(true))
+	f.expect(getTeachersByCategoryQuery, 2, defaultPageSize, 0)
+
+	w := serve(t, NewTeacherController(db).GetTeachersByCategory, testRequest{body: `{"category_id": 2}`})
+	expectStatus(t, w, http.StatusOK)
+	if body := w.Body.String(); body != "[]" {
+		t.Errorf("body = %s, want an empty list", body)
+	}
+}
+
+func TestGetTeachersByCategoryMissingCategory(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(categoryExistsInlineQuery, 2).returns(row(false))
+
+	w := serve(t, NewTeacherController(db).GetTeachersByCategory, testRequest{body: `{"category_id": 2}`})
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		valid bool
+	}{
+		{"alice@example.com", true},
+		{"alice.smith+dz@mail.example.com", true},
+		{"", false},
+		{"not-an-email", false},
+		{"alice.example.com", false},
+		{"alice@", false},
+		{"@example.com", false},
+		{" alice@example.com", false},
+		{"alice@example.com ", false},
+		{"Alice <alice@example.com>", false},
+	}
+	for _, tt := range tests {
+		if err := validateEmail(tt.email); (err == nil) != tt.valid {
+			t.Errorf("validateEmail(%q) = %v, want valid %t", tt.email, err, tt.valid)
+		}
+	}
+}
+
+func TestCreateAccountsRejectMalformedEmail(t *testing.T) {
+	db, _ := newFakeDB(t)
+	handlers := map[string]gin.HandlerFunc{
+		"teacher": NewTeacherController(db).CreateTeacher,
+		"student": NewStudentController(db).CreateStudent,
+	}
+	for name, handler := range handlers {
+		w := serve(t, handler, testRequest{
+			body: `{"FullName": "Alice", "username": "alice", "email": "alice@", "Password": "secret123"}`,
+		})
+		expectStatus(t, w, http.StatusBadRequest)
+		if got := responseKeys(t, w.Body.Bytes())["error"]; got != "email must be a valid address such as name@example.com" {
+			t.Errorf("%s error = %v, want the email format message", name, got)
+		}
+	}
+}
+
+const teacherHasCoursesCondition = "EXISTS(SELECT 1 FROM courses c WHERE c.teacher_id = teachers.id AND c.deleted_at IS NULL)"
+
+func TestGetAllTeachersFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		where string
+		args  []interface{}
+	}{
+		{"none", "", " ORDER BY id ASC LIMIT $1 OFFSET $2", []interface{}{defaultPageSize, 0}},
+		{"name", "name=ada", " WHERE full_name ILIKE '%' || $1 || '%' ORDER BY", []interface{}{"ada", defaultPageSize, 0}},
+		{"skill", "skill=%20go%20", " WHERE skills ILIKE '%' || $1 || '%' ORDER BY", []interface{}{"go", defaultPageSize, 0}},
+		{"has courses", "has_courses=true", " WHERE " + teacherHasCoursesCondition + " ORDER BY", []interface{}{defaultPageSize, 0}},
+		{"without courses", "has_courses=false", " WHERE NOT " + teacherHasCoursesCondition + " ORDER BY", []interface{}{defaultPageSize, 0}},
+		{
+			"combined", "name=ada&skill=go&has_courses=true&page=2&page_size=5",
+			" WHERE full_name ILIKE '%' || $1 || '%' AND skills ILIKE '%' || $2 || '%' AND " + teacherHasCoursesCondition +
+				" ORDER BY id ASC LIMIT $3 OFFSET $4",
+			[]interface{}{"ada", "go", 5, 5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(strings.TrimSpace(getAllTeachersQuery)+tt.where, tt.args...).returns(
+				row(3, "Ada Lovelace", "ada", "ada@example.com", "$2a$14$hash", "", "go, math", "PhD", "10y"),
+			)
+
+			w := serve(t, NewTeacherController(db).GetAllTeachers, testRequest{method: http.MethodGet, query: tt.query})
+			expectStatus(t, w, http.StatusOK)
+
+			var teachers []map[string]interface{}
+			decodeBody(t, w, &teachers)
+			if len(teachers) != 1 {
+				t.Fatalf("got %d teachers, want 1", len(teachers))
+			}
+			if teachers[0]["Password"] != "" {
+				t.Errorf("teacher leaks its password %q", teachers[0]["Password"])
+			}
+		})
+	}
+}
+
+func TestGetAllTeachersRejectsBadHasCourses(t *testing.T) {
+	db, _ := newFakeDB(t)
+	w := serve(t, NewTeacherController(db).GetAllTeachers, testRequest{method: http.MethodGet, query: "has_courses=maybe"})
+	expectStatus(t, w, http.StatusBadRequest)
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/cuddest/dz-skills/auth"
 	"github.com/cuddest/dz-skills/config"
@@ -9,6 +10,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// TokenStatus describes a token that is still valid
+type TokenStatus struct {
+	Valid     bool      `json:"valid"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 type TokenRequest struct {
 	Identifier string `json:"email"` // Can be either email or username
 	Password   string `json:"password"`
@@ -16,7 +25,7 @@ type TokenRequest struct {
 }
 
 // @Summary User login
-// @Description Authenticate a user (teacher or student) and generate an access token
+// @Description Authenticate a user (teacher, student or admin) and generate an access token
 // @Tags authentication
 // @Accept json
 // @Produce json
@@ -36,7 +45,7 @@ func GenerateToken(context *gin.Context) {
 	}
 
 	// Validate role
-	if input.Role != "teacher" && input.Role != "student" {
+	if !auth.IsValidRole(input.Role) {
 		context.JSON(http.StatusBadRequest, gin.H{"error": "invalid role specified"})
 		context.Abort()
 		return
@@ -44,7 +53,7 @@ func GenerateToken(context *gin.Context) {
 
 	var user models.User
 	var userID uint
-	if input.Role == "teacher" {
+	if input.Role == auth.RoleTeacher {
 		var teacher models.Teacher
 		record := config.DB.Where("email = ? OR username = ?", input.Identifier, input.Identifier).First(&teacher)
 		if record.Error != nil {
@@ -54,7 +63,18 @@ func GenerateToken(context *gin.Context) {
 		}
 		user = &teacher
 		userID = teacher.ID
-	} else {
+	} else if input.Role == auth.RoleAdmin {
+		// Admins are teacher accounts flagged in the database
+		var teacher models.Teacher
+		record := config.DB.Where("(email = ? OR username = ?) AND is_admin = ?", input.Identifier, input.Identifier, true).First(&teacher)
+		if record.Error != nil {
+			context.JSON(http.StatusUnauthorized, gin.H{"error": "user not found or invalid credentials"})
+			context.Abort()
+			return
+		}
+		user = &teacher
+		userID = teacher.ID
+	} else if input.Role == auth.RoleStudent {
 		var student models.Student
 		record := config.DB.Where("email = ? OR username = ?", input.Identifier, input.Identifier).First(&student)
 		if record.Error != nil {
@@ -64,6 +84,10 @@ func GenerateToken(context *gin.Context) {
 		}
 		user = &student
 		userID = student.ID
+	} else {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "login is not supported for this role"})
+		context.Abort()
+		return
 	}
 
 	credentialError := models.CheckPassword(user, input.Password)
@@ -97,3 +121,32 @@ func GenerateToken(context *gin.Context) {
 		"userID":   userID,
 	})
 }
+
+// @Summary Validate a token
+// @Description Check whether the token in the Authorization header is still valid, without touching any protected resource
+// @Tags authentication
+// @Produce json
+// @Success 200 {object} TokenStatus
+// @Failure 401 {object} map[string]interface{}
+// @Security ApiKeyAuth
+// @Router /auth/validate [get]
+func ValidateToken(context *gin.Context) {
+	tokenString := context.GetHeader("Authorization")
+	if tokenString == "" {
+		context.JSON(http.StatusUnauthorized, gin.H{"valid": false, "error": "request does not contain an access token"})
+		return
+	}
+
+	claims, err := auth.ValidateToken(tokenString)
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"valid": false, "error": err.Error()})
+		return
+	}
+
+	context.JSON(http.StatusOK, TokenStatus{
+		Valid:     true,
+		Username:  claims.Username,
+		Role:      claims.Role,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+	})
+}
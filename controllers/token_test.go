@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/cuddest/dz-skills/config"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// useGormDB points config.DB at db for the rest of the test
+func useGormDB(t *testing.T, db *sql.DB) {
+	t.Helper()
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open gorm: %v", err)
+	}
+	previous := config.DB
+	config.DB = gdb
+	t.Cleanup(func() { config.DB = previous })
+}
+
+// expectStudentLogin seeds the student alice with password secret
+func expectStudentLogin(t *testing.T, f *fakeDB) {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.expect(`FROM "students" WHERE email = $1 OR username = $2`).
+		named("id", "full_name", "username", "email", "password", "picture").
+		returns(row(5, "Alice", "alice", "alice@example.com", string(hash), ""))
+}
+
+func TestGenerateTokenForStudent(t *testing.T) {
+	db, f := newFakeDB(t)
+	useGormDB(t, db)
+	expectStudentLogin(t, f)
+
+	w := serve(t, GenerateToken, testRequest{
+		body: `{"email": "alice", "password": "secret", "role": "student"}`,
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var got struct {
+		Token string `json:"token"`
+	}
+	decodeBody(t, w, &got)
+	claims, err := auth.ValidateToken(got.Token)
+	if err != nil {
+		t.Fatalf("token does not validate: %v", err)
+	}
+	if claims.Role != auth.RoleStudent || claims.Username != "alice" {
+		t.Errorf("claims = %+v, want student alice", claims)
+	}
+}
+
+func TestGenerateTokenWrongPassword(t *testing.T) {
+	db, f := newFakeDB(t)
+	useGormDB(t, db)
+	expectStudentLogin(t, f)
+
+	w := serve(t, GenerateToken, testRequest{
+		body: `{"email": "alice", "password": "guess", "role": "student"}`,
+	})
+	expectStatus(t, w, http.StatusUnauthorized)
+}
+
+func TestGenerateTokenRejectsUnknownRole(t *testing.T) {
+	w := serve(t, GenerateToken, testRequest{
+		body: `{"email": "alice", "password": "secret", "role": "root"}`,
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+}
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+)
+
+// queryRower runs single row queries, inside a transaction or not
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// withTx runs fn inside a transaction, committing when fn returns nil and
+// rolling back when it returns an error or panics
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
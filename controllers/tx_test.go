@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestWithTxCommitsOnSuccess(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect("UPDATE courses").affects(1)
+
+	err := withTx(context.Background(), db, func(tx *sql.Tx) error {
+		_, err := tx.Exec("UPDATE courses SET name = 'Go'")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("withTx() = %v", err)
+	}
+	if f.commits != 1 || f.rollbacks != 0 {
+		t.Errorf("commits = %d, rollbacks = %d, want one commit", f.commits, f.rollbacks)
+	}
+}
+
+func TestWithTxRollsBackOnError(t *testing.T) {
+	db, f := newFakeDB(t)
+	errFailed := errors.New("failed")
+
+	err := withTx(context.Background(), db, func(tx *sql.Tx) error {
+		return errFailed
+	})
+	if err != errFailed {
+		t.Errorf("withTx() = %v, want the callback error", err)
+	}
+	if f.commits != 0 || f.rollbacks != 1 {
+		t.Errorf("commits = %d, rollbacks = %d, want one rollback", f.commits, f.rollbacks)
+	}
+}
+
+func TestWithTxRollsBackOnPanic(t *testing.T) {
+	db, f := newFakeDB(t)
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("recovered %v, want the callback panic", p)
+		}
+		if f.commits != 0 || f.rollbacks != 1 {
+			t.Errorf("commits = %d, rollbacks = %d, want one rollback", f.commits, f.rollbacks)
+		}
+	}()
+	withTx(context.Background(), db, func(tx *sql.Tx) error {
+		panic("boom")
+	})
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/cuddest/dz-skills/config"
+	"github.com/gin-gonic/gin"
+)
+
+// VersionInfo describes the running build
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// WelcomeInfo is the response of the root route
+type WelcomeInfo struct {
+	Message string `json:"message"`
+	DocsURL string `json:"docs_url"`
+	Version string `json:"version"`
+}
+
+// @Summary Welcome
+// @Description Greet API clients and point them at the documentation of this deployment, built from API_BASE_URL
+// @Tags version
+// @Produce json
+// @Success 200 {object} WelcomeInfo
+// @Router / [get]
+func Welcome(c *gin.Context) {
+	docsURL := config.DocsURL()
+	c.JSON(http.StatusOK, WelcomeInfo{
+		Message: "Welcome to the Dz Skills API, go to " + docsURL + " for documentation, good to see you :D",
+		DocsURL: docsURL,
+		Version: config.Version,
+	})
+}
+
+// @Summary Get server version
+// @Description Return the build version, git commit and build time of the running server
+// @Tags version
+// @Produce json
+// @Success 200 {object} VersionInfo
+// @Router /version [get]
+func GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, VersionInfo{
+		Version:   config.Version,
+		Commit:    config.Commit,
+		BuildTime: config.BuildTime,
+	})
+}
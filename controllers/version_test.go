@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestGetVersionDefaults(t *testing.T) {
+	w := serve(t, GetVersion, testRequest{method: http.MethodGet})
+	expectStatus(t, w, http.StatusOK)
+
+	var got map[string]string
+	decodeBody(t, w, &got)
+	want := map[string]string{"version": "dev", "commit": "dev", "build_time": "dev"}
+	for field, value := range want {
+		if got[field] != value {
+			t.Errorf("%s = %q, want %q", field, got[field], value)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("response = %v, want only %v", got, want)
+	}
+}
+
+func TestWelcomeUsesConfiguredBaseURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		want    string
+	}{
+		{"", "http://localhost:8080/docs/index.html#/"},
+		{"https://staging.dzskills.com/", "https://staging.dzskills.com/docs/index.html#/"},
+		{" https://api.dzskills.com ", "https://api.dzskills.com/docs/index.html#/"},
+	}
+	for _, tt := range tests {
+		t.Setenv("API_BASE_URL", tt.baseURL)
+
+		w := serve(t, Welcome, testRequest{method: http.MethodGet})
+		expectStatus(t, w, http.StatusOK)
+
+		var got WelcomeInfo
+		decodeBody(t, w, &got)
+		if got.DocsURL != tt.want {
+			t.Errorf("API_BASE_URL %q: docs_url = %q, want %q", tt.baseURL, got.DocsURL, tt.want)
+		}
+		if !strings.Contains(got.Message, tt.want) {
+			t.Errorf("API_BASE_URL %q: message %q does not link %q", tt.baseURL, got.Message, tt.want)
+		}
+		if got.Version != "dev" {
+			t.Errorf("version = %q, want dev", got.Version)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -37,8 +38,27 @@ const (
 
 	deleteVideoQuery = `
 		DELETE FROM videos WHERE id = $1`
+
+	getVideoCourseQuery = `
+		SELECT course_id FROM videos WHERE id = $1`
+
+	upsertVideoProgressQuery = `
+		INSERT INTO video_progresses (student_id, video_id, watched, last_position, updated_at) 
+		VALUES ($1, $2, $3, $4, $5) 
+		ON CONFLICT (student_id, video_id) 
+		DO UPDATE SET watched = EXCLUDED.watched, last_position = EXCLUDED.last_position, updated_at = EXCLUDED.updated_at`
 )
 
+// maxProgressBatchSize caps how many watch events a single sync may submit
+const maxProgressBatchSize = 500
+
+// VideoProgressEntry is a single watch event submitted by a syncing client
+type VideoProgressEntry struct {
+	VideoID      uint `json:"video_id"`
+	Watched      bool `json:"watched"`
+	LastPosition uint `json:"last_position"`
+}
+
 type VideoController struct {
 	db *sql.DB
 }
@@ -88,7 +108,7 @@ func (h *VideoController) CreateVideo(c *gin.Context) {
 
 	// Verify course exists
 	var exists bool
-	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", video.CourseID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", video.CourseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -114,7 +134,7 @@ func (h *VideoController) CreateVideo(c *gin.Context) {
 // @Tags videos
 // @Accept json
 // @Produce json
-// @Param id path int true "Video ID"
+// @Param request body IDRequest true "Video ID"
 // @Success 200 {object} models.Video
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
@@ -124,14 +144,13 @@ func (h *VideoController) GetVideo(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+	id, ok := bindID(c)
+	if !ok {
 		return
 	}
 
 	var video models.Video
-	err = h.db.QueryRowContext(ctx, getVideoQuery, id).Scan(
+	err := h.db.QueryRowContext(ctx, getVideoQuery, id).Scan(
 		&video.ID, &video.Title, &video.Link, &video.CourseID,
 	)
 
@@ -194,6 +213,7 @@ func (h *VideoController) GetAllVideos(c *gin.Context) {
 // @Param courseId path int true "Course ID"
 // @Success 200 {array} models.Video
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /videos/GetVideosByCourse [post]
@@ -207,9 +227,14 @@ func (h *VideoController) GetVideosByCourse(c *gin.Context) {
 		return
 	}
 
+	// Students lose access to course material once their enrollment expires
+	if denyExpiredAccess(ctx, h.db, c, uint(courseID)) {
+		return
+	}
+
 	// Verify course exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", courseID).Scan(&exists)
+	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", courseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -251,7 +276,6 @@ func (h *VideoController) GetVideosByCourse(c *gin.Context) {
 // @Tags videos
 // @Accept json
 // @Produce json
-// @Param id path int true "Video ID"
 // @Param video body models.Video true "Updated video information"
 // @Success 200 {object} models.Video
 // @Failure 400 {object} map[string]interface{}
@@ -262,17 +286,16 @@ func (h *VideoController) UpdateVideo(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
-		return
-	}
-
 	var video models.Video
 	if err := c.ShouldBindJSON(&video); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if video.ID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID is required"})
+		return
+	}
+	id := video.ID
 
 	if err := h.validateVideo(&video); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -281,7 +304,7 @@ func (h *VideoController) UpdateVideo(c *gin.Context) {
 
 	// Verify course exists
 	var exists bool
-	err = h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1)", video.CourseID).Scan(&exists)
+	err := h.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM courses WHERE id = $1 AND deleted_at IS NULL)", video.CourseID).Scan(&exists)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course"})
 		return
@@ -291,6 +314,22 @@ func (h *VideoController) UpdateVideo(c *gin.Context) {
 		return
 	}
 
+	// Snapshot the current row so the response can be limited to changed fields
+	var before models.Video
+	if wantsDiff(c) {
+		err = h.db.QueryRowContext(ctx, getVideoQuery, id).Scan(
+			&before.ID, &before.Title, &before.Link, &before.CourseID,
+		)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Video not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve video"})
+			return
+		}
+	}
+
 	result, err := h.db.ExecContext(ctx, updateVideoQuery,
 		video.Title, video.Link, video.CourseID, id)
 
@@ -309,8 +348,7 @@ func (h *VideoController) UpdateVideo(c *gin.Context) {
 		return
 	}
 
-	video.ID = uint(id)
-	c.JSON(http.StatusOK, video)
+	respondUpdated(c, before, video)
 }
 
 // @Summary Delete a video
@@ -352,3 +390,108 @@ func (h *VideoController) DeleteVideo(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Video deleted successfully"})
 }
+
+// @Summary Record video progress in batch
+// @Description Upsert the watch progress of several videos for the authenticated student in a single transaction. Every video must belong to a course the student is enrolled in, otherwise the whole batch is rejected.
+// @Tags videos
+// @Accept json
+// @Produce json
+// @Param progress body []VideoProgressEntry true "Watch events"
+// @Success 200 {array} models.VideoProgress
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /videos/progress/batch [post]
+func (h *VideoController) BatchUpdateProgress(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Second)
+	defer cancel()
+
+	studentID, ok, err := authenticatedStudentID(ctx, h.db, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify student"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only students can record video progress"})
+		return
+	}
+
+	var entries []VideoProgressEntry
+	if err := c.ShouldBindJSON(&entries); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(entries) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one progress entry is required"})
+		return
+	}
+	if len(entries) > maxProgressBatchSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d progress entries are allowed", maxProgressBatchSize)})
+		return
+	}
+
+	// Validate every entry before writing anything so a bad batch changes nothing
+	checkedCourses := make(map[uint]bool)
+	for _, entry := range entries {
+		if entry.VideoID == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "valid video ID is required"})
+			return
+		}
+
+		var courseID uint
+		err := h.db.QueryRowContext(ctx, getVideoCourseQuery, entry.VideoID).Scan(&courseID)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Video %d not found", entry.VideoID)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify video"})
+			return
+		}
+
+		if checkedCourses[courseID] {
+			continue
+		}
+		err = checkCourseAccess(ctx, h.db, studentID, courseID)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Not enrolled in the course of video %d", entry.VideoID)})
+			return
+		}
+		if err == errAccessExpired {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Course access has expired"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify course access"})
+			return
+		}
+		checkedCourses[courseID] = true
+	}
+
+	updatedAt := now()
+	progress := make([]models.VideoProgress, 0, len(entries))
+	err = withTx(ctx, h.db, func(tx *sql.Tx) error {
+		for _, entry := range entries {
+			_, err := tx.ExecContext(ctx, upsertVideoProgressQuery,
+				studentID, entry.VideoID, entry.Watched, entry.LastPosition, updatedAt)
+			if err != nil {
+				return err
+			}
+			progress = append(progress, models.VideoProgress{
+				StudentID:    studentID,
+				VideoID:      entry.VideoID,
+				Watched:      entry.Watched,
+				LastPosition: entry.LastPosition,
+				UpdatedAt:    updatedAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save video progress"})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBatchUpdateProgressSavesEveryEntry(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	setClock(t, updatedAt)
+
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getVideoCourseQuery, 1).returns(row(7))
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+	// Video 2 is on the same course, its access is only checked once
+	f.expect(getVideoCourseQuery, 2).returns(row(7))
+	f.expect(upsertVideoProgressQuery, 5, 1, true, 300, updatedAt).affects(1)
+	f.expect(upsertVideoProgressQuery, 5, 2, false, 42, updatedAt).affects(1)
+
+	w := serve(t, NewVideoController(db).BatchUpdateProgress, testRequest{
+		body: `[{"video_id": 1, "watched": true, "last_position": 300},
+			{"video_id": 2, "watched": false, "last_position": 42}]`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var progress []map[string]interface{}
+	decodeBody(t, w, &progress)
+	if len(progress) != 2 {
+		t.Errorf("got %d progress entries, want 2", len(progress))
+	}
+	if f.commits != 1 {
+		t.Errorf("commits = %d, want 1", f.commits)
+	}
+}
+
+func TestBatchUpdateProgressRejectsUnenrolledCourse(t *testing.T) {
+	db, f := newFakeDB(t)
+	f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+	f.expect(getVideoCourseQuery, 1).returns(row(7))
+	f.expect(getAccessExpiryQuery, 5, 7).returns(row(nil))
+	f.expect(getVideoCourseQuery, 3).returns(row(8))
+	f.expect(getAccessExpiryQuery, 5, 8)
+
+	w := serve(t, NewVideoController(db).BatchUpdateProgress, testRequest{
+		body:   `[{"video_id": 1, "watched": true}, {"video_id": 3, "watched": true}]`,
+		claims: studentClaims("alice"),
+	})
+	expectStatus(t, w, http.StatusForbidden)
+
+	// Nothing is written when one entry is rejected
+	if f.commits != 0 || f.rollbacks != 0 {
+		t.Errorf("commits = %d, rollbacks = %d, want no transaction", f.commits, f.rollbacks)
+	}
+}
+
+func TestBatchUpdateProgressRejectsBadBatches(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty", `[]`},
+		{"not a list", `{"video_id": 1}`},
+		{"missing video", `[{"watched": true}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, f := newFakeDB(t)
+			f.expect(getStudentIDByUsernameQuery, "alice").returns(row(5))
+
+			w := serve(t, NewVideoController(db).BatchUpdateProgress, testRequest{
+				body:   tt.body,
+				claims: studentClaims("alice"),
+			})
+			expectStatus(t, w, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestBatchUpdateProgressRequiresStudent(t *testing.T) {
+	db, _ := newFakeDB(t)
+
+	w := serve(t, NewVideoController(db).BatchUpdateProgress, testRequest{
+		body:   `[{"video_id": 1, "watched": true}]`,
+		claims: teacherClaims("bob"),
+	})
+	expectStatus(t, w, http.StatusForbidden)
+}
@@ -4539,6 +4539,9 @@ const docTemplate = `{
         "models.Question": {
             "type": "object",
             "properties": {
+                "ID": {
+                    "type": "integer"
+                },
                 "answers": {
                     "type": "array",
                     "items": {
@@ -4548,9 +4551,6 @@ const docTemplate = `{
                 "course_id": {
                     "type": "integer"
                 },
-                "id": {
-                    "type": "integer"
-                },
                 "rating": {
                     "type": "string"
                 },
@@ -4606,7 +4606,7 @@ const docTemplate = `{
                 "certificate": {
                     "type": "string"
                 },
-                "courseID": {
+                "course_id": {
                     "type": "integer"
                 },
                 "enrollment": {
@@ -4618,7 +4618,7 @@ const docTemplate = `{
                 "issued": {
                     "type": "boolean"
                 },
-                "studentID": {
+                "student_id": {
                     "type": "integer"
                 }
             }
@@ -0,0 +1,133 @@
+package mailer
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// Message is a plain text email with optional attachments
+type Message struct {
+	To          string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// boundary separates the parts of messages with attachments
+const boundary = "dz-skills-mail-boundary"
+
+// Mailer sends emails on behalf of the platform
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// FromEnv returns an SMTP mailer when SMTP_HOST is set, and a no-op mailer
+// that only logs otherwise so development setups don't need a mail server.
+func FromEnv() Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return NoopMailer{}
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	return &SMTPMailer{
+		Addr:     host + ":" + port,
+		Host:     host,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	}
+}
+
+// NoopMailer logs messages instead of sending them
+type NoopMailer struct{}
+
+func (NoopMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("mailer: not sending %q to %s with %d attachment(s), SMTP is not configured", msg.Subject, msg.To, len(msg.Attachments))
+	return nil
+}
+
+// SMTPMailer sends messages through an SMTP server with PLAIN auth
+type SMTPMailer struct {
+	Addr     string
+	Host     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := m.build(msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	return smtp.SendMail(m.Addr, auth, m.From, []string{msg.To}, data)
+}
+
+// build formats msg as a MIME message, a plain text one when there are no
+// attachments and a multipart/mixed one otherwise
+func (m *SMTPMailer) build(msg Message) ([]byte, error) {
+	headers := msg.To + msg.Subject
+	for _, a := range msg.Attachments {
+		headers += a.Filename + a.ContentType
+	}
+	if strings.ContainsAny(headers, "\r\n\"") {
+		return nil, fmt.Errorf("mailer: header values cannot contain line breaks or quotes")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if len(msg.Attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		b.WriteString(msg.Body)
+		return []byte(b.String()), nil
+	}
+
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+	b.WriteString("\r\n")
+	for _, a := range msg.Attachments {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		fmt.Fprintf(&b, "Content-Type: %s\r\n", a.ContentType)
+		b.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&b, "Content-Disposition: attachment; filename=%q\r\n\r\n", a.Filename)
+
+		// RFC 2045 caps base64 lines at 76 characters
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			b.WriteString(encoded[:76] + "\r\n")
+			encoded = encoded[76:]
+		}
+		b.WriteString(encoded + "\r\n")
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,80 @@
+package mailer
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestBuildPlainMessage(t *testing.T) {
+	m := &SMTPMailer{From: "noreply@example.com"}
+	data, err := m.build(Message{To: "alice@example.com", Subject: "Hi", Body: "Hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if !strings.Contains(got, "Content-Type: text/plain; charset=UTF-8\r\n\r\nHello") {
+		t.Errorf("message is not plain text:\n%s", got)
+	}
+	if strings.Contains(got, "multipart") {
+		t.Errorf("message without attachments is multipart:\n%s", got)
+	}
+}
+
+func TestBuildMessageWithAttachment(t *testing.T) {
+	pdf := []byte(strings.Repeat("%PDF-1.4 certificate ", 10))
+	m := &SMTPMailer{From: "noreply@example.com"}
+	data, err := m.build(Message{
+		To:      "alice@example.com",
+		Subject: "Your certificate",
+		Body:    "Attached",
+		Attachments: []Attachment{
+			{Filename: "certificate-7.pdf", ContentType: "application/pdf", Data: pdf},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+
+	for _, want := range []string{
+		`Content-Type: multipart/mixed; boundary="` + boundary + `"`,
+		"Content-Type: application/pdf\r\n",
+		`Content-Disposition: attachment; filename="certificate-7.pdf"`,
+		"--" + boundary + "--\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message is missing %q:\n%s", want, got)
+		}
+	}
+
+	// The attachment decodes back to the original bytes
+	start := strings.Index(got, "filename=\"certificate-7.pdf\"\r\n\r\n") + len("filename=\"certificate-7.pdf\"\r\n\r\n")
+	end := strings.Index(got, "--"+boundary+"--")
+	encoded := strings.ReplaceAll(got[start:end], "\r\n", "")
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("decode attachment: %v", err)
+	}
+	if string(decoded) != string(pdf) {
+		t.Errorf("attachment = %q, want %q", decoded, pdf)
+	}
+	for _, l := range strings.Split(got[start:end], "\r\n") {
+		if len(l) > 76 {
+			t.Errorf("base64 line is %d characters long", len(l))
+		}
+	}
+}
+
+func TestBuildRejectsHeaderInjection(t *testing.T) {
+	m := &SMTPMailer{}
+	tests := []Message{
+		{To: "alice@example.com\r\nBcc: eve@example.com"},
+		{To: "alice@example.com", Attachments: []Attachment{{Filename: "a\".pdf", ContentType: "application/pdf"}}},
+	}
+	for _, msg := range tests {
+		if _, err := m.build(msg); err == nil {
+			t.Errorf("build(%+v) succeeded, want an error", msg)
+		}
+	}
+}
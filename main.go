@@ -2,9 +2,13 @@ package main
 
 import (
 	"log"
+	"strings"
 
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/cuddest/dz-skills/certificate"
 	"github.com/cuddest/dz-skills/config"
 	_ "github.com/cuddest/dz-skills/docs"
+	"github.com/cuddest/dz-skills/middlewares"
 	"github.com/cuddest/dz-skills/routes"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -43,18 +47,47 @@ func main() {
 		log.Fatalf("Could not extract *sql.DB from *gorm.DB: %v", err)
 	}
 
+	origins := config.CORSOrigins([]string{"http://localhost:5173","https://dz-skill-plateforme.vercel.app"})
+	corsConfig, err := config.LoadCORSConfig(origins)
+	if err != nil {
+		log.Fatalf("Invalid CORS configuration: %v", err)
+	}
+
+	// AUTH_DISABLED lets integration tests skip tokens, never in release mode
+	identity, err := config.LoadAuthBypass()
+	if err != nil {
+		log.Fatalf("Invalid auth configuration: %v", err)
+	}
+	if identity != nil {
+		claims := &auth.JWTClaim{Username: identity.Username, Email: identity.Email, Role: identity.Role}
+		if err := middlewares.DisableAuth(claims); err != nil {
+			log.Fatalf("Could not disable authentication: %v", err)
+		}
+		log.Println("********************************************************************")
+		log.Printf("WARNING: AUTHENTICATION IS DISABLED, every request runs as %s %q", identity.Role, identity.Username)
+		log.Println("WARNING: unset AUTH_DISABLED before exposing this server to anyone")
+		log.Println("********************************************************************")
+	}
+
+	// A broken certificate template should stop startup, not the first download
+	certTemplate, err := config.LoadCertificateTemplate()
+	if err != nil {
+		log.Fatalf("Invalid certificate template: %v", err)
+	}
+	if err := certificate.SetTemplate(certTemplate); err != nil {
+		log.Fatalf("Invalid certificate template: %v", err)
+	}
+
 	router := gin.New()
-	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:5173","https://dz-skill-plateforme.vercel.app"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		AllowCredentials: true,
-	}))
+	router.Use(middlewares.Recovery())
+	router.Use(cors.New(corsConfig))
 
 	routes.InitRoutes(router, sqlDB)
 
-	log.Println("Server running on port 8080...")
-	if err := router.Run(":8080"); err != nil {
+	port := config.Port()
+	log.Printf("CORS allowed origins: %s", strings.Join(origins, ", "))
+	log.Printf("Server running on port %s...", port)
+	if err := router.Run(":" + port); err != nil {
 		log.Fatalf("Failed to run the server: %v", err)
 	}
 }
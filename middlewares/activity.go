@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+)
+
+// Only writes when the stored timestamp is older than the interval, so
+// several instances don't all write for the same student
+const touchStudentQuery = `
+	UPDATE students SET last_active_at = NOW() 
+	WHERE username = $1 AND (last_active_at IS NULL OR last_active_at < NOW() - make_interval(secs => $2))`
+
+// activityTracker records when students were last active, writing at most
+// once per interval for each of them
+type activityTracker struct {
+	db       *sql.DB
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastSeen map[string]time.Time
+}
+
+// tracker is set by TrackStudentActivity, nil while activity isn't tracked
+var tracker *activityTracker
+
+// TrackStudentActivity makes AuthMiddleware update last_active_at of the
+// authenticated student, at most once per interval
+func TrackStudentActivity(db *sql.DB, interval time.Duration) {
+	tracker = &activityTracker{db: db, interval: interval, lastSeen: make(map[string]time.Time)}
+}
+
+// due reports whether username wasn't recorded during the last interval, and
+// if so counts it as recorded now
+func (t *activityTracker) due(username string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.lastSeen[username]; ok && now.Sub(last) < t.interval {
+		return false
+	}
+	t.lastSeen[username] = now
+	return true
+}
+
+// touch records activity of username without holding up the request
+func (t *activityTracker) touch(username string) {
+	if !t.due(username, time.Now()) {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := t.db.ExecContext(ctx, touchStudentQuery, username, t.interval.Seconds()); err != nil {
+			log.Printf("Failed to record activity of student %q: %v", username, err)
+		}
+	}()
+}
@@ -0,0 +1,122 @@
+package middlewares
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// execRecorder is a database/sql connector that reports the arguments of
+// every statement executed on it
+type execRecorder struct{ execs chan []driver.NamedValue }
+
+func (r execRecorder) Connect(context.Context) (driver.Conn, error) { return r, nil }
+func (r execRecorder) Driver() driver.Driver                        { return nil }
+func (r execRecorder) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (r execRecorder) Close() error { return nil }
+func (r execRecorder) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (r execRecorder) ExecContext(_ context.Context, _ string, args []driver.NamedValue) (driver.Result, error) {
+	r.execs <- args
+	return driver.RowsAffected(1), nil
+}
+
+// trackActivity turns activity tracking on for the test and returns the
+// channel the writes arrive on
+func trackActivity(t *testing.T, interval time.Duration) <-chan []driver.NamedValue {
+	t.Helper()
+	recorder := execRecorder{execs: make(chan []driver.NamedValue, 10)}
+	db := sql.OpenDB(recorder)
+	TrackStudentActivity(db, interval)
+	t.Cleanup(func() {
+		tracker = nil
+		db.Close()
+	})
+	return recorder.execs
+}
+
+// runAuthenticated runs AuthMiddleware with a valid token for username
+func runAuthenticated(t *testing.T, username, role string) {
+	t.Helper()
+	token, err := auth.GenerateJWT(username+"@example.com", username, role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+token)
+
+	AuthMiddleware()(c)
+	if c.IsAborted() {
+		t.Fatalf("request as %s was rejected with %d", username, w.Code)
+	}
+}
+
+func TestAuthenticatedRequestTouchesStudent(t *testing.T) {
+	execs := trackActivity(t, 5*time.Minute)
+
+	runAuthenticated(t, "alice", auth.RoleStudent)
+	select {
+	case args := <-execs:
+		if len(args) != 2 || args[0].Value != "alice" || args[1].Value != float64(300) {
+			t.Errorf("write args = %v, want alice and a 300s interval", args)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("last_active_at was not written")
+	}
+
+	// Repeats within the interval don't write again
+	runAuthenticated(t, "alice", auth.RoleStudent)
+	runAuthenticated(t, "alice", auth.RoleStudent)
+	select {
+	case args := <-execs:
+		t.Errorf("repeat request wrote again: %v", args)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTeacherRequestsAreNotTracked(t *testing.T) {
+	execs := trackActivity(t, 5*time.Minute)
+
+	runAuthenticated(t, "bob", auth.RoleTeacher)
+	select {
+	case args := <-execs:
+		t.Errorf("teacher request wrote student activity: %v", args)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestActivityTrackerDue(t *testing.T) {
+	at := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	tr := &activityTracker{interval: 5 * time.Minute, lastSeen: make(map[string]time.Time)}
+
+	steps := []struct {
+		username string
+		at       time.Time
+		want     bool
+	}{
+		{"alice", at, true},
+		{"alice", at.Add(time.Minute), false},
+		{"carol", at.Add(time.Minute), true},
+		{"alice", at.Add(5*time.Minute - time.Second), false},
+		{"alice", at.Add(5 * time.Minute), true},
+	}
+	for _, step := range steps {
+		if got := tr.due(step.username, step.at); got != step.want {
+			t.Errorf("due(%s, %s) = %t, want %t", step.username, step.at.Format(time.Kitchen), got, step.want)
+		}
+	}
+}
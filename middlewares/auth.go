@@ -1,12 +1,38 @@
 package middlewares
 
 import (
+	"fmt"
+
 	"github.com/cuddest/dz-skills/auth"
 	"github.com/gin-gonic/gin"
 )
 
+// testClaims is the fixed identity AuthMiddleware injects once authentication
+// has been disabled for local testing, nil otherwise
+var testClaims *auth.JWTClaim
+
+// DisableAuth makes AuthMiddleware skip token validation and treat every
+// request as claims. It is meant for local integration testing only and
+// refuses to run in release mode.
+func DisableAuth(claims *auth.JWTClaim) error {
+	if gin.Mode() == gin.ReleaseMode {
+		return fmt.Errorf("authentication cannot be disabled in %s mode", gin.ReleaseMode)
+	}
+	if !auth.IsValidRole(claims.Role) {
+		return auth.ErrInvalidRole{Role: claims.Role}
+	}
+	testClaims = claims
+	return nil
+}
+
 func AuthMiddleware() gin.HandlerFunc {
 	return func(context *gin.Context) {
+		if testClaims != nil {
+			context.Set(auth.ClaimsKey, testClaims)
+			context.Next()
+			return
+		}
+
 		tokenString := context.GetHeader("Authorization")
 		if tokenString == "" {
 			context.JSON(401, gin.H{"error": "request does not contain an access token"})
@@ -15,12 +41,16 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Assuming ValidateToken returns both the token and an error
-		_, err := auth.ValidateToken(tokenString) // Handling both return values
+		claims, err := auth.ValidateToken(tokenString) // Handling both return values
 		if err != nil {
 			context.JSON(401, gin.H{"error": err.Error()})
 			context.Abort()
 			return
 		}
+		context.Set(auth.ClaimsKey, claims)
+		if tracker != nil && claims.Role == auth.RoleStudent {
+			tracker.touch(claims.Username)
+		}
 
 		context.Next()
 	}
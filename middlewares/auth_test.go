@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// runAuth runs AuthMiddleware on a request without a token and returns the
+// status it answers, 200 when it lets the request through, along with the
+// claims it set
+func runAuth() (int, *auth.JWTClaim) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	AuthMiddleware()(c)
+	claims, _ := auth.ClaimsFromContext(c)
+	if c.IsAborted() {
+		return w.Code, claims
+	}
+	return http.StatusOK, claims
+}
+
+func TestAuthMiddlewareRequiresToken(t *testing.T) {
+	if got, _ := runAuth(); got != http.StatusUnauthorized {
+		t.Errorf("request without a token answered %d, want 401", got)
+	}
+}
+
+func TestDisableAuthInjectsIdentity(t *testing.T) {
+	t.Cleanup(func() { testClaims = nil })
+	identity := &auth.JWTClaim{Username: "test", Role: auth.RoleTeacher}
+	if err := DisableAuth(identity); err != nil {
+		t.Fatal(err)
+	}
+
+	got, claims := runAuth()
+	if got != http.StatusOK {
+		t.Fatalf("request without a token answered %d with auth disabled", got)
+	}
+	if claims == nil || claims.Username != "test" || claims.Role != auth.RoleTeacher {
+		t.Errorf("claims = %+v, want the test teacher", claims)
+	}
+}
+
+func TestDisableAuthRefusedInReleaseMode(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	t.Cleanup(func() {
+		gin.SetMode(gin.TestMode)
+		testClaims = nil
+	})
+
+	if err := DisableAuth(&auth.JWTClaim{Username: "test", Role: auth.RoleTeacher}); err == nil {
+		t.Fatal("DisableAuth succeeded in release mode")
+	}
+	if got, _ := runAuth(); got != http.StatusUnauthorized {
+		t.Errorf("request without a token answered %d, want 401", got)
+	}
+}
+
+func TestDisableAuthRejectsUnknownRole(t *testing.T) {
+	t.Cleanup(func() { testClaims = nil })
+	if err := DisableAuth(&auth.JWTClaim{Username: "test", Role: "root"}); err == nil {
+		t.Error("DisableAuth accepted an unknown role")
+	}
+}
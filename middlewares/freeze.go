@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// contentFrozen is the content freeze switch admins flip during grading
+// windows. It lives in memory, so it is per instance and off after a restart.
+var contentFrozen atomic.Bool
+
+// SetContentFreeze turns the content freeze on or off
+func SetContentFreeze(enabled bool) {
+	contentFrozen.Store(enabled)
+}
+
+// ContentFrozen reports whether the content freeze is on
+func ContentFrozen() bool {
+	return contentFrozen.Load()
+}
+
+// BlockWhenFrozen rejects teachers with 423 Locked while the content freeze is
+// on. It goes on the routes that change course content; reads, student
+// activity such as exam submissions, and admins are never blocked. It must
+// run after AuthMiddleware.
+func BlockWhenFrozen() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		if !ContentFrozen() {
+			context.Next()
+			return
+		}
+
+		claims, ok := auth.ClaimsFromContext(context)
+		if ok && claims.Role == auth.RoleTeacher {
+			context.JSON(http.StatusLocked, gin.H{"error": "content edits are frozen during the grading window"})
+			context.Abort()
+			return
+		}
+		context.Next()
+	}
+}
@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// freeze turns the content freeze on for the test
+func freeze(t *testing.T) {
+	t.Helper()
+	SetContentFreeze(true)
+	t.Cleanup(func() { SetContentFreeze(false) })
+}
+
+// runFrozen runs BlockWhenFrozen for a request carrying claims and returns
+// the status it answers, 200 when it lets the request through
+func runFrozen(claims *auth.JWTClaim) int {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+	if claims != nil {
+		c.Set(auth.ClaimsKey, claims)
+	}
+
+	BlockWhenFrozen()(c)
+	if c.IsAborted() {
+		return w.Code
+	}
+	return http.StatusOK
+}
+
+func TestBlockWhenFrozenBlocksTeachers(t *testing.T) {
+	freeze(t)
+	if got := runFrozen(&auth.JWTClaim{Username: "bob", Role: auth.RoleTeacher}); got != http.StatusLocked {
+		t.Errorf("teacher edit while frozen answered %d, want 423", got)
+	}
+}
+
+func TestBlockWhenFrozenLetsStudentsAndAdminsThrough(t *testing.T) {
+	freeze(t)
+	for _, role := range []string{auth.RoleStudent, auth.RoleAdmin} {
+		if got := runFrozen(&auth.JWTClaim{Username: "alice", Role: role}); got != http.StatusOK {
+			t.Errorf("%s request while frozen answered %d, want it let through", role, got)
+		}
+	}
+}
+
+func TestBlockWhenFrozenIsOffByDefault(t *testing.T) {
+	if ContentFrozen() {
+		t.Fatal("content freeze is on by default")
+	}
+	if got := runFrozen(&auth.JWTClaim{Username: "bob", Role: auth.RoleTeacher}); got != http.StatusOK {
+		t.Errorf("teacher edit without a freeze answered %d", got)
+	}
+}
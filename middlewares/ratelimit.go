@@ -0,0 +1,148 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow counts the requests of one key in the current window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter is a fixed window limiter, each key may make limit requests per window
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// allow records a request for key and reports whether it is within the limit,
+// along with how long until the key's window resets
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current := l.now()
+
+	// Drop windows that are over so idle keys don't pile up
+	if current.Sub(l.lastSweep) >= l.window {
+		for k, w := range l.windows {
+			if current.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = current
+	}
+
+	w, ok := l.windows[key]
+	if !ok || current.Sub(w.start) >= l.window {
+		w = &rateWindow{start: current}
+		l.windows[key] = w
+	}
+	w.count++
+	return w.count <= l.limit, w.start.Add(l.window).Sub(current)
+}
+
+// rejectTooMany writes a 429 telling the client when to retry
+func rejectTooMany(context *gin.Context, retryAfter time.Duration) {
+	context.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	context.JSON(http.StatusTooManyRequests, gin.H{"error": "too many requests, please try again later"})
+	context.Abort()
+}
+
+func (l *rateLimiter) middleware(key func(*gin.Context) string) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		allowed, retryAfter := l.allow(key(context))
+		if !allowed {
+			rejectTooMany(context, retryAfter)
+			return
+		}
+		context.Next()
+	}
+}
+
+// RateLimit allows each client IP limit requests per window
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	return newRateLimiter(limit, window).middleware(func(context *gin.Context) string {
+		return "ip:" + context.ClientIP()
+	})
+}
+
+// RateLimitPerUser allows each authenticated user limit requests per window,
+// so users behind a shared proxy don't eat into each other's budget. Requests
+// without claims, on routes without AuthMiddleware, are limited by IP.
+func RateLimitPerUser(limit int, window time.Duration) gin.HandlerFunc {
+	return newRateLimiter(limit, window).middleware(func(context *gin.Context) string {
+		if claims, ok := auth.ClaimsFromContext(context); ok && claims.Username != "" {
+			return "user:" + claims.Role + ":" + claims.Username
+		}
+		return "ip:" + context.ClientIP()
+	})
+}
+
+// maxLoginBodySize caps how much of a login body is read to find the identifier
+const maxLoginBodySize = 1 << 16
+
+// loginIdentifier reads the account identifier of a login request, leaving
+// the body in place for the handler. It returns "" when there is none.
+func loginIdentifier(context *gin.Context) string {
+	body, err := io.ReadAll(io.LimitReader(context.Request.Body, maxLoginBodySize))
+	context.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var login struct {
+		Identifier string `json:"email"`
+	}
+	if json.Unmarshal(body, &login) != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(login.Identifier))
+}
+
+// LoginRateLimit slows down password guessing: each client IP may try perIP
+// logins per window, and each account identifier perIdentifier, whatever IP
+// the attempts come from. Share one instance between the login routes.
+func LoginRateLimit(perIP, perIdentifier int, window time.Duration) gin.HandlerFunc {
+	byIP := newRateLimiter(perIP, window)
+	byIdentifier := newRateLimiter(perIdentifier, window)
+	return func(context *gin.Context) {
+		if allowed, retryAfter := byIP.allow("ip:" + context.ClientIP()); !allowed {
+			rejectTooMany(context, retryAfter)
+			return
+		}
+		if identifier := loginIdentifier(context); identifier != "" {
+			if allowed, retryAfter := byIdentifier.allow("login:" + identifier); !allowed {
+				rejectTooMany(context, retryAfter)
+				return
+			}
+		}
+		context.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// runLimited runs limit for a request from ip carrying claims and returns the
+// status it answers, 200 when it lets the request through
+func runLimited(limit gin.HandlerFunc, ip string, claims *auth.JWTClaim) int {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	c.Request.RemoteAddr = ip + ":1234"
+	if claims != nil {
+		c.Set(auth.ClaimsKey, claims)
+	}
+
+	limit(c)
+	if c.IsAborted() {
+		return w.Code
+	}
+	return http.StatusOK
+}
+
+func TestRateLimitPerUserKeepsUsersApart(t *testing.T) {
+	limit := RateLimitPerUser(2, time.Minute)
+	alice := &auth.JWTClaim{Username: "alice", Role: auth.RoleStudent}
+	bob := &auth.JWTClaim{Username: "bob", Role: auth.RoleStudent}
+
+	// Both users sit behind the same proxy
+	for i := 0; i < 2; i++ {
+		if got := runLimited(limit, "10.0.0.1", alice); got != http.StatusOK {
+			t.Fatalf("alice request %d answered %d", i+1, got)
+		}
+	}
+	if got := runLimited(limit, "10.0.0.1", alice); got != http.StatusTooManyRequests {
+		t.Errorf("alice over the limit answered %d, want 429", got)
+	}
+	if got := runLimited(limit, "10.0.0.1", bob); got != http.StatusOK {
+		t.Errorf("bob answered %d after alice used up her budget", got)
+	}
+}
+
+func TestRateLimitPerUserFallsBackToIP(t *testing.T) {
+	limit := RateLimitPerUser(1, time.Minute)
+
+	if got := runLimited(limit, "10.0.0.1", nil); got != http.StatusOK {
+		t.Fatalf("first anonymous request answered %d", got)
+	}
+	if got := runLimited(limit, "10.0.0.1", nil); got != http.StatusTooManyRequests {
+		t.Errorf("second anonymous request from the same IP answered %d, want 429", got)
+	}
+	if got := runLimited(limit, "10.0.0.2", nil); got != http.StatusOK {
+		t.Errorf("anonymous request from another IP answered %d", got)
+	}
+}
+
+func TestRateLimiterWindowResets(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newRateLimiter(1, time.Minute)
+	l.now = func() time.Time { return at }
+
+	if ok, _ := l.allow("user:alice"); !ok {
+		t.Fatal("first request was limited")
+	}
+	ok, retryAfter := l.allow("user:alice")
+	if ok || retryAfter != time.Minute {
+		t.Errorf("allow() = %v, %v, want limited for a minute", ok, retryAfter)
+	}
+
+	at = at.Add(time.Minute)
+	if ok, _ := l.allow("user:alice"); !ok {
+		t.Error("request in the next window was limited")
+	}
+}
@@ -0,0 +1,50 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestID returns the X-Request-ID the client sent, or a random one so a
+// report from the client can be matched with the server logs
+func requestID(c *gin.Context) string {
+	if id := c.GetHeader("X-Request-ID"); id != "" && len(id) <= 64 {
+		return id
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// Recovery turns a panicking handler into a 500 JSON error instead of taking
+// the whole process down. The stack trace is only logged, never sent back.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			// net/http uses this panic to abort a response on purpose
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+
+			id := requestID(c)
+			log.Printf("panic serving %s %s (request %s): %v\n%s", c.Request.Method, c.Request.URL.Path, id, err, debug.Stack())
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "request_id": id})
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// runPanicking serves a request with header to a handler that dereferences a
+// nil pointer behind Recovery
+func runPanicking(header string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/", func(c *gin.Context) {
+		var claims *struct{ Username string }
+		c.String(http.StatusOK, claims.Username)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set("X-Request-ID", header)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRecoveryAnswersPanicWith500(t *testing.T) {
+	w := runPanicking("")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("panicking handler answered %d, want 500", w.Code)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	if body["error"] != "internal server error" || body["request_id"] == "" {
+		t.Errorf("body = %v, want the generic error and a request id", body)
+	}
+	if strings.Contains(w.Body.String(), "nil pointer") || strings.Contains(w.Body.String(), "goroutine") {
+		t.Errorf("body %q exposes the panic", w.Body.String())
+	}
+}
+
+func TestRecoveryEchoesRequestID(t *testing.T) {
+	w := runPanicking("req-42")
+
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	if body["request_id"] != "req-42" {
+		t.Errorf("request_id = %q, want the client's req-42", body["request_id"])
+	}
+}
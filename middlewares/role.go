@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole only lets through requests whose token carries one of roles. It
+// must run after AuthMiddleware, and panics at route setup on a role unknown to
+// the auth package so the two can't drift apart.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	for _, role := range roles {
+		if !auth.IsValidRole(role) {
+			panic(auth.ErrInvalidRole{Role: role})
+		}
+	}
+
+	return func(context *gin.Context) {
+		claims, ok := auth.ClaimsFromContext(context)
+		if !ok {
+			context.JSON(http.StatusUnauthorized, gin.H{"error": "request does not contain an access token"})
+			context.Abort()
+			return
+		}
+
+		for _, role := range roles {
+			if claims.Role == role {
+				context.Next()
+				return
+			}
+		}
+
+		context.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		context.Abort()
+	}
+}
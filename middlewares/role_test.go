@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// runRole runs RequireRole(roles...) for a request carrying claims and
+// returns the status it answers, 200 when it lets the request through
+func runRole(claims *auth.JWTClaim, roles ...string) int {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if claims != nil {
+		c.Set(auth.ClaimsKey, claims)
+	}
+
+	RequireRole(roles...)(c)
+	if c.IsAborted() {
+		return w.Code
+	}
+	return http.StatusOK
+}
+
+func TestRequireRoleAcceptsEveryCentralRole(t *testing.T) {
+	for _, role := range auth.Roles() {
+		if got := runRole(&auth.JWTClaim{Role: role}, role); got != http.StatusOK {
+			t.Errorf("RequireRole(%s) answered %d for a %s token", role, got, role)
+		}
+	}
+}
+
+func TestRequireRoleRejectsUnknownRole(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(auth.ErrInvalidRole); !ok {
+			t.Error("RequireRole with an unknown role did not panic with ErrInvalidRole")
+		}
+	}()
+	RequireRole("root")
+}
+
+func TestRequireRoleForbidsOtherRoles(t *testing.T) {
+	if got := runRole(&auth.JWTClaim{Role: auth.RoleStudent}, auth.RoleTeacher, auth.RoleAdmin); got != http.StatusForbidden {
+		t.Errorf("student on a teacher route answered %d, want 403", got)
+	}
+	if got := runRole(nil, auth.RoleTeacher); got != http.StatusUnauthorized {
+		t.Errorf("request without a token answered %d, want 401", got)
+	}
+}
@@ -20,7 +20,9 @@ CREATE TABLE teachers (
     picture VARCHAR(255),
     skills TEXT,
     degrees VARCHAR(255),
-    experience TEXT
+    experience TEXT,
+    active BOOLEAN DEFAULT TRUE,
+    is_admin BOOLEAN DEFAULT FALSE
 );
 
 
@@ -30,7 +32,8 @@ CREATE TABLE students (
     username VARCHAR(255) UNIQUE NOT NULL,
     email VARCHAR(255) UNIQUE NOT NULL,
     password VARCHAR(255) NOT NULL,
-    picture VARCHAR(255)
+    picture VARCHAR(255),
+    last_active_at TIMESTAMP
 );
 
 
@@ -43,17 +46,27 @@ CREATE TABLE courses (
     image VARCHAR(255),
     language VARCHAR(50),
     level VARCHAR(50),
+    access_days INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    average_rating DOUBLE PRECISION DEFAULT 0,
+    ratings_count INTEGER DEFAULT 0,
     teacher_id INTEGER REFERENCES teachers(id),
-    category_id INTEGER REFERENCES categories(id)
+    category_id INTEGER REFERENCES categories(id),
+    sub_cat_id INTEGER REFERENCES sub_cats(id) ON DELETE SET NULL,
+    deleted_at TIMESTAMP
 );
 
+CREATE INDEX idx_courses_deleted_at ON courses (deleted_at);
+
 CREATE TABLE student_courses (
     student_id INTEGER REFERENCES students(id),
     course_id INTEGER REFERENCES courses(id),
     grade VARCHAR(10),
     enrollment TIMESTAMP,
+    access_expires_at TIMESTAMP,
     certificate VARCHAR(255),
     issued BOOLEAN DEFAULT FALSE,
+    attempts INTEGER DEFAULT 0,
     PRIMARY KEY (student_id, course_id)
 );
 
@@ -61,9 +74,10 @@ CREATE TABLE student_courses (
 CREATE TABLE cratings (
     course_id INTEGER REFERENCES courses(id) ON DELETE CASCADE,
     student_id INTEGER REFERENCES students(id) ON DELETE CASCADE,
-    rating DECIMAL(3,2),
+    rating DECIMAL(3,2) CONSTRAINT chk_cratings_rating CHECK (rating BETWEEN 1 AND 5),
     PRIMARY KEY (course_id, student_id)
 );
+CREATE UNIQUE INDEX idx_cratings_course_student ON cratings (course_id, student_id);
 
 
 CREATE TABLE articles (
@@ -71,7 +85,8 @@ CREATE TABLE articles (
     title VARCHAR(255) NOT NULL,
     link VARCHAR(255),
     description TEXT,
-    course_id INTEGER REFERENCES courses(id) ON DELETE CASCADE
+    course_id INTEGER REFERENCES courses(id) ON DELETE CASCADE,
+    position INTEGER DEFAULT 0
 );
 
 CREATE TABLE videos (
@@ -81,12 +96,22 @@ CREATE TABLE videos (
     course_id INTEGER REFERENCES courses(id) ON DELETE CASCADE
 );
 
+CREATE TABLE video_progresses (
+    student_id INTEGER REFERENCES students(id) ON DELETE CASCADE,
+    video_id INTEGER REFERENCES videos(id) ON DELETE CASCADE,
+    watched BOOLEAN DEFAULT FALSE,
+    last_position INTEGER DEFAULT 0,
+    updated_at TIMESTAMP,
+    PRIMARY KEY (student_id, video_id)
+);
+
 
 CREATE TABLE feedbacks (
     id SERIAL PRIMARY KEY,
     description TEXT,
-    review INTEGER,
-    student_id INTEGER REFERENCES students(id) ON DELETE CASCADE
+    review INTEGER CONSTRAINT chk_feedbacks_review CHECK (review BETWEEN 1 AND 5),
+    student_id INTEGER REFERENCES students(id) ON DELETE CASCADE,
+    course_id INTEGER REFERENCES courses(id) ON DELETE CASCADE
 );
 
 
@@ -100,12 +125,15 @@ CREATE TABLE questions (
 CREATE TABLE answers (
     id SERIAL PRIMARY KEY,
     answer TEXT NOT NULL,
-    question_id INTEGER REFERENCES questions(id) ON DELETE CASCADE
+    question_id INTEGER REFERENCES questions(id) ON DELETE CASCADE,
+    is_instructor BOOLEAN DEFAULT FALSE,
+    author_teacher_id INTEGER REFERENCES teachers(id) ON DELETE SET NULL
 );
 
 CREATE TABLE exams (
     id SERIAL PRIMARY KEY,
     description TEXT,
+    archived BOOLEAN DEFAULT FALSE,
     course_id INTEGER REFERENCES courses(id) ON DELETE CASCADE UNIQUE
 );
 
@@ -130,4 +158,50 @@ CREATE TABLE exam_quizzes (
     option3 VARCHAR(255) NOT NULL,
     option4 VARCHAR(255) NOT NULL,
     answer INTEGER NOT NULL,
-    exam_id INTEGER REFERENCES exams(id) ON DELETE CASCADE
\ No newline at end of file
+    exam_id INTEGER REFERENCES exams(id) ON DELETE CASCADE
+);
+
+
+CREATE TABLE student_exam_answers (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER REFERENCES students(id) ON DELETE CASCADE,
+    course_id INTEGER REFERENCES courses(id) ON DELETE CASCADE,
+    quizz_id INTEGER REFERENCES exam_quizzes(id) ON DELETE CASCADE,
+    chosen_answer INTEGER NOT NULL,
+    correct BOOLEAN NOT NULL,
+    submitted_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_exam_answers_student_course ON student_exam_answers (student_id, course_id);
+
+
+CREATE TABLE exam_attempts (
+    id SERIAL PRIMARY KEY,
+    student_id INTEGER REFERENCES students(id) ON DELETE CASCADE,
+    course_id INTEGER REFERENCES courses(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL,
+    score INTEGER NOT NULL,
+    total INTEGER NOT NULL,
+    grade VARCHAR(50),
+    passed BOOLEAN NOT NULL,
+    submitted_at TIMESTAMP NOT NULL,
+    idempotency_key VARCHAR(255)
+);
+
+CREATE INDEX idx_exam_attempts_student_course ON exam_attempts (student_id, course_id);
+CREATE UNIQUE INDEX idx_exam_attempts_idempotency ON exam_attempts (student_id, course_id, idempotency_key);
+
+
+CREATE TABLE audit_logs (
+    id SERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+
+CREATE TABLE schema_migrations (
+    version INTEGER PRIMARY KEY,
+    applied_at TIMESTAMP
+);
@@ -1,7 +1,7 @@
 package models
 
 type Crating struct {
-	CourseID uint    `json:"course_id"`
-	StudentID uint   `json:"student_id"`
+	CourseID  uint    `gorm:"uniqueIndex:idx_cratings_course_student" json:"course_id"`
+	StudentID uint    `gorm:"uniqueIndex:idx_cratings_course_student" json:"student_id"`
 	Rating    float64 `json:"rating"`
-}
\ No newline at end of file
+}
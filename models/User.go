@@ -1,17 +1,25 @@
 package models
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"time"
 
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Student is the only student account model; the controllers scan the
+// students table columns into it directly
 type Student struct {
-	ID        uint       `gorm:"primaryKey" json:"ID"`
-	FullName  string     `json:"FullName"`
-	Username  string     `gorm:"unique" json:"username"`
-	Email     string     `gorm:"unique" json:"email"`
-	Password  string     `json:"Password"`
-	Picture   string     `json:"Picture"`
-	Courses   []Course   `gorm:"many2many:student_courses;"`
-	Feedback  []Feedback `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE"`
-	Questions []Question `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE"`
+	ID       uint   `gorm:"primaryKey" json:"ID"`
+	FullName string `json:"FullName"`
+	Username string `gorm:"unique" json:"username"`
+	Email    string `gorm:"unique" json:"email"`
+	Password string `json:"Password"`
+	Picture  string `json:"Picture"`
+	// LastActiveAt is refreshed by authenticated requests, at most every few minutes
+	LastActiveAt *time.Time `json:"last_active_at,omitempty"`
+	Courses      []Course   `gorm:"many2many:student_courses;"`
+	Feedback     []Feedback `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE"`
+	Questions    []Question `gorm:"foreignKey:StudentID;constraint:OnDelete:CASCADE"`
 }
 
 func (s *Student) GetPassword() string {
@@ -22,6 +30,7 @@ func (s *Student) SetPassword(password string) {
 	s.Password = password
 }
 
+// Teacher is the only teacher account model, admins included
 type Teacher struct {
 	ID         uint     `gorm:"primaryKey" json:"ID"`
 	FullName   string   `json:"FullName"`
@@ -32,6 +41,8 @@ type Teacher struct {
 	Skills     string   `json:"Skills"`
 	Degrees    string   `json:"Degree"`
 	Experience string   `json:"Experience"`
+	Active     bool     `gorm:"default:true" json:"-"`  // inactive teachers can't receive courses
+	IsAdmin    bool     `gorm:"default:false" json:"-"` // granted directly in the database
 	Courses    []Course `gorm:"foreignKey:TeacherID;constraint:OnDelete:CASCADE"`
 }
 
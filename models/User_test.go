@@ -0,0 +1,38 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The controllers scan the account columns straight into these fields, so
+// this only compiles while each model still has them
+var (
+	_ = Student{ID: 1, FullName: "", Username: "", Email: "", Password: "", Picture: ""}
+	_ = Teacher{ID: 1, FullName: "", Username: "", Email: "", Password: "", Picture: "", Skills: "", Degrees: "", Experience: ""}
+
+	_ User = (*Student)(nil)
+	_ User = (*Teacher)(nil)
+)
+
+// jsonTags maps each field of v's type to its json tag
+func jsonTags(v interface{}) map[string]string {
+	typ := reflect.TypeOf(v)
+	tags := make(map[string]string, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		tags[typ.Field(i).Name] = typ.Field(i).Tag.Get("json")
+	}
+	return tags
+}
+
+func TestAccountModelsShareJSONTags(t *testing.T) {
+	student, teacher := jsonTags(Student{}), jsonTags(Teacher{})
+	for _, field := range []string{"ID", "FullName", "Username", "Email", "Password", "Picture"} {
+		if student[field] == "" || student[field] != teacher[field] {
+			t.Errorf("%s json tag: student %q, teacher %q, want the same non-empty tag", field, student[field], teacher[field])
+		}
+	}
+	if student["Username"] != "username" {
+		t.Errorf("Username json tag = %q, want username", student["Username"])
+	}
+}
@@ -1,8 +1,13 @@
 package models
 
 type Answer struct {
-	ID         uint     `gorm:"primaryKey" json:"ID"`
-	Answer     string   `json:"Answer"`
-	QuestionID uint     `json:"question_id"`
-	Question   Question `gorm:"foreignKey:QuestionID" json:"question"`
+	ID           uint     `gorm:"primaryKey" json:"ID"`
+	Answer       string   `json:"Answer"`
+	QuestionID   uint     `json:"question_id"`
+	IsInstructor bool     `gorm:"default:false" json:"is_instructor"` // set when a teacher wrote the answer
+	Question     Question `gorm:"foreignKey:QuestionID" json:"question"`
+
+	// The teacher who wrote the answer, nil for answers from students
+	AuthorTeacherID *uint    `json:"author_teacher_id"`
+	AuthorTeacher   *Teacher `gorm:"foreignKey:AuthorTeacherID;constraint:OnDelete:SET NULL" json:"-"`
 }
@@ -6,5 +6,6 @@ type Article struct {
 	Link        string `json:"Link"`
 	Description string `json:"Description"`
 	CourseID    uint   `json:"course_id"`
+	Position    uint   `gorm:"default:0" json:"position"` // reading order within the course
 	Course      Course `gorm:"foreignKey:CourseID"`
 }
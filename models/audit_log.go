@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey" json:"ID"`
+	Actor     string    `gorm:"not null" json:"actor"`
+	Action    string    `gorm:"not null" json:"action"`
+	Details   string    `json:"details"`
+	CreatedAt time.Time `json:"created_at"`
+}
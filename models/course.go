@@ -1,5 +1,11 @@
 package models
 
+import (
+    "time"
+
+    "gorm.io/gorm"
+)
+
 type Course struct {
     ID          uint   `gorm:"primaryKey" json:"ID"`
     Name        string `json:"Name"`
@@ -9,11 +15,21 @@ type Course struct {
     Image       string `json:"Image"`
     Language    string `json:"Language"`
     Level       string `json:"Level"`
+    AccessDays  uint   `gorm:"default:0" json:"access_days"` // 0 means unlimited access
+    CreatedAt   *time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at,omitempty"`
+    // Denormalized from cratings, kept in sync on every rating change
+    AverageRating float64 `gorm:"default:0" json:"average_rating"`
+    RatingsCount  uint    `gorm:"default:0" json:"total_ratings"`
     TeacherID   uint   `json:"teacher_id"`
     CategoryID  uint   `json:"category_id"`
     Category    Category    `gorm:"foreignKey:CategoryID"`
+    // Optional, must belong to the course category
+    SubCatID    *uint       `json:"sub_cat_id"`
+    SubCat      *SubCat     `gorm:"foreignKey:SubCatID;constraint:OnDelete:SET NULL" json:"SubCat,omitempty"`
     Articles    []Article   `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
     Videos      []Video     `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
     Questions   []Question  `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
     Crating     []Crating   `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+    // Set when the course is deleted, the row is kept so it can be restored
+    DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 }
\ No newline at end of file
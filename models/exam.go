@@ -3,6 +3,7 @@ package models
 type Exam struct {
 	ID          uint        `gorm:"primaryKey" json:"ID"`
 	Description string      `json:"Description"`
+	Archived    bool        `gorm:"default:false" json:"archived"` // archived exams can't be taken, past results are kept
 	ExamQuizzes []ExamQuizz `gorm:"foreignKey:ExamID;constraint:OnDelete:CASCADE"`
 	CourseID    uint        `gorm:"unique" json:"course_id"`                          // Ensure that each exam is linked to one course
 	Course      Course      `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE;"` // One-to-one relationship with Course
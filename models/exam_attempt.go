@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// ExamAttempt is the outcome of one graded exam attempt, kept as history
+type ExamAttempt struct {
+	ID          uint      `gorm:"primaryKey" json:"ID"`
+	StudentID   uint      `gorm:"index:idx_exam_attempts_student_course;uniqueIndex:idx_exam_attempts_idempotency" json:"student_id"`
+	CourseID    uint      `gorm:"index:idx_exam_attempts_student_course;uniqueIndex:idx_exam_attempts_idempotency" json:"course_id"`
+	Attempt     int       `json:"attempt"`
+	Score       int       `json:"score"`
+	Total       int       `json:"total"`
+	Grade       string    `json:"grade"`
+	Passed      bool      `json:"passed"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	// IdempotencyKey is the Idempotency-Key the attempt was submitted with, if any
+	IdempotencyKey *string `gorm:"uniqueIndex:idx_exam_attempts_idempotency" json:"-"`
+}
@@ -6,4 +6,7 @@ type Feedback struct {
 	Review     uint   `json:"Review"`
 	StudentID  uint   `json:"student_id"`
 	Student    Student `gorm:"foreignKey:StudentID"`
+	// Optional, general feedback about the platform has no course
+	CourseID   *uint   `json:"course_id"`
+	Course     *Course `gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE" json:"-"`
 }
\ No newline at end of file
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Every model with an ID serializes it under the same key
+func TestModelsUseOneIDKey(t *testing.T) {
+	models := []interface{}{
+		Answer{}, Article{}, AuditLog{}, Category{}, Course{}, CourseQuizz{},
+		Exam{}, ExamAttempt{}, ExamQuizz{}, Feedback{}, Question{},
+		Student{}, StudentExamAnswer{}, SubCat{}, Teacher{}, Video{},
+	}
+	for _, model := range models {
+		typ := reflect.TypeOf(model)
+		field, ok := typ.FieldByName("ID")
+		if !ok {
+			t.Errorf("%s has no ID field", typ.Name())
+			continue
+		}
+		if tag := field.Tag.Get("json"); tag != "ID" {
+			t.Errorf("%s.ID json tag = %q, want ID", typ.Name(), tag)
+		}
+	}
+}
@@ -1,7 +1,7 @@
 package models
 
 type Question struct {
-	ID        uint     `gorm:"primaryKey" json:"id"`
+	ID        uint     `gorm:"primaryKey" json:"ID"`
 	CourseID  uint     `json:"course_id"`
 	StudentID uint     `json:"student_id"`
 	Question  string   `json:"rating"`
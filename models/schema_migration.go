@@ -0,0 +1,10 @@
+package models
+
+import (
+	"time"
+)
+
+type SchemaMigration struct {
+	Version   uint      `gorm:"primaryKey" json:"version"`
+	AppliedAt time.Time `json:"applied_at"`
+}
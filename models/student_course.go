@@ -5,10 +5,12 @@ import (
 )
 
 type StudentCourse struct {
-	StudentID   uint      `gorm:"primaryKey"`
-	CourseID    uint      `gorm:"primaryKey"`
-	Grade       string    `json:"grade"`
-	Enrollment  time.Time `json:"enrollment"`
-	Certificate *string   `json:"certificate"`
-	Issued      bool      `json:"issued"`
+	StudentID       uint       `gorm:"primaryKey" json:"student_id"`
+	CourseID        uint       `gorm:"primaryKey" json:"course_id"`
+	Grade           string     `json:"grade"`
+	Enrollment      time.Time  `json:"enrollment"`
+	AccessExpiresAt *time.Time `json:"access_expires_at"`
+	Certificate     *string    `json:"certificate"`
+	Issued          bool       `json:"issued"`
+	Attempts        uint       `gorm:"default:0" json:"attempts"`
 }
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+type StudentExamAnswer struct {
+	ID           uint      `gorm:"primaryKey" json:"ID"`
+	StudentID    uint      `gorm:"index:idx_exam_answers_student_course" json:"student_id"`
+	CourseID     uint      `gorm:"index:idx_exam_answers_student_course" json:"course_id"`
+	QuizzID      uint      `json:"quizz_id"`
+	ChosenAnswer uint      `json:"chosen_answer"`
+	Correct      bool      `json:"correct"`
+	SubmittedAt  time.Time `json:"submitted_at"`
+}
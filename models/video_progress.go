@@ -0,0 +1,13 @@
+package models
+
+import (
+	"time"
+)
+
+type VideoProgress struct {
+	StudentID    uint      `gorm:"primaryKey" json:"student_id"`
+	VideoID      uint      `gorm:"primaryKey" json:"video_id"`
+	Watched      bool      `json:"watched"`
+	LastPosition uint      `json:"last_position"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -2,7 +2,10 @@ package routes
 
 import (
 	"database/sql"
+	"time"
 
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/cuddest/dz-skills/config"
 	"github.com/cuddest/dz-skills/controllers"
 	"github.com/cuddest/dz-skills/middlewares"
 	"github.com/gin-gonic/gin"
@@ -10,26 +13,44 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// Write-heavy endpoints accept at most writeRateLimit requests per user every writeRateWindow
+const (
+	writeRateLimit  = 20
+	writeRateWindow = time.Minute
+)
+
+// studentActivityInterval is how often a student's last_active_at may be written
+const studentActivityInterval = 5 * time.Minute
+
 func InitRoutes(router *gin.Engine, db *sql.DB) {
+	// Record when students were last active, for the admin activity listing
+	middlewares.TrackStudentActivity(db, studentActivityInterval)
+
+	// Login attempts are limited per IP and per account, shared by every login route
+	loginLimits := config.LoadLoginRateLimits()
+	loginLimit := middlewares.LoginRateLimit(loginLimits.PerIP, loginLimits.PerIdentifier, loginLimits.Window)
+
+	// Content edits are blocked for teachers while an admin has frozen them
+	frozen := middlewares.BlockWhenFrozen()
+
 	// swagger docs route
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	//base routes
-	router.GET("/", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "Welcome to the Dz Skills API, go to https://dzskiils-production.up.railway.app/docs/index.html#/ for documentation, good to see you :D",
-		})
-	})
+	router.GET("/", controllers.Welcome)
+	router.GET("/version", controllers.GetVersion)
+	router.GET("/health", controllers.NewHealthController(db).Health)
+	router.GET("/auth/validate", controllers.ValidateToken)
 	// Answer Routes
 	answerController := controllers.NewAnswerController(db)
 	answerGroup := router.Group("/answers")
 	answerGroup.Use(middlewares.AuthMiddleware())
 	{
 
-		answerGroup.POST("/CreateAnswer", answerController.CreateAnswer)
+		answerGroup.POST("/CreateAnswer", frozen, answerController.CreateAnswer)
 		answerGroup.POST("/GetAnswer", answerController.GetAnswer)
 		answerGroup.GET("/GetAllAnswer", answerController.GetAllAnswers)
-		answerGroup.PUT("/UpdateAnswer", answerController.UpdateAnswer)
-		answerGroup.DELETE("/DeleteAnswer", answerController.DeleteAnswer)
+		answerGroup.PUT("/UpdateAnswer", frozen, answerController.UpdateAnswer)
+		answerGroup.DELETE("/DeleteAnswer", frozen, answerController.DeleteAnswer)
 		answerGroup.POST("/GetAnswersByQuestion", answerController.GetAnswersByQuestion)
 	}
 	// Article Routes
@@ -40,9 +61,10 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 		ArticleGroup.GET("/all", articleController.GetAllArticles)
 		ArticleGroup.POST("/get", articleController.GetArticle)
 		ArticleGroup.POST("/GetArticlesByCourse", articleController.GetArticlesByCourse)
-		ArticleGroup.POST("/createArticle", articleController.CreateArticle)
-		ArticleGroup.PUT("/updateArticle", articleController.UpdateArticle)
-		ArticleGroup.DELETE("/DeleteArticle", articleController.DeleteArticle)
+		ArticleGroup.POST("/createArticle", frozen, articleController.CreateArticle)
+		ArticleGroup.PUT("/updateArticle", frozen, articleController.UpdateArticle)
+		ArticleGroup.PUT("/reorder", frozen, articleController.ReorderArticles)
+		ArticleGroup.DELETE("/DeleteArticle", frozen, articleController.DeleteArticle)
 	}
 	// Category Routes
 	CategoryController := controllers.NewCategoryController(db)
@@ -51,9 +73,10 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 	{
 		CategoryGroup.GET("/all", CategoryController.GetAllCategories)
 		CategoryGroup.POST("/get/:id", CategoryController.GetCategory)
-		CategoryGroup.POST("/createCategory", CategoryController.CreateCategory)
-		CategoryGroup.PUT("/updateCategory", CategoryController.UpdateCategory)
-		CategoryGroup.DELETE("/DeleteCategory", CategoryController.DeleteCategory)
+		CategoryGroup.POST("/createCategory", frozen, CategoryController.CreateCategory)
+		CategoryGroup.POST("/bulk", middlewares.RequireRole(auth.RoleAdmin), CategoryController.CreateCategoriesBulk)
+		CategoryGroup.PUT("/updateCategory", frozen, CategoryController.UpdateCategory)
+		CategoryGroup.DELETE("/DeleteCategory", frozen, CategoryController.DeleteCategory)
 	}
 	// Course Routes
 	CourseController := controllers.NewCourseController(db)
@@ -62,9 +85,23 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 	CoursesGroup.Use(middlewares.AuthMiddleware())
 	{
 		CoursesGroup.GET("/all", CourseController.GetAllCourses)
-		CoursesGroup.POST("/createCourse", CourseController.CreateCourse)
-		CoursesGroup.PUT("/updateCourse", CourseController.UpdateCourse)
-		CoursesGroup.DELETE("/DeleteCourse/:id", CourseController.DeleteCourse)
+		CoursesGroup.GET("/search", CourseController.SearchCourses)
+		CoursesGroup.POST("/searchContent", CourseController.SearchCourseContent)
+		CoursesGroup.GET("/filter", CourseController.GetCoursesFiltered)
+		CoursesGroup.GET("/facets", CourseController.GetCourseFacets)
+		CoursesGroup.GET("/bySubCategory", CourseController.GetCoursesBySubCategory)
+		CoursesGroup.GET("/topRated", CourseController.GetTopRatedCourses)
+		CoursesGroup.POST("/manage", CourseController.GetManagedCourses)
+		CoursesGroup.POST("/byTeacher", CourseController.GetCoursesByTeacher)
+		CoursesGroup.POST("/get", CourseController.GetCourse)
+		CoursesGroup.POST("/full", CourseController.GetCourseFull)
+		CoursesGroup.POST("/createCourse", frozen, CourseController.CreateCourse)
+		CoursesGroup.PUT("/updateCourse", frozen, CourseController.UpdateCourse)
+		CoursesGroup.DELETE("/DeleteCourse/:id", frozen, CourseController.DeleteCourse)
+		CoursesGroup.POST("/restore", frozen, CourseController.RestoreCourse)
+		CoursesGroup.DELETE("/purge", frozen, middlewares.RequireRole(auth.RoleTeacher, auth.RoleAdmin), CourseController.PurgeCourse)
+		CoursesGroup.POST("/transferOwnership", middlewares.RequireRole(auth.RoleAdmin), CourseController.TransferOwnership)
+		CoursesGroup.POST("/bulkPricing", frozen, middlewares.RequireRole(auth.RoleTeacher, auth.RoleAdmin), CourseController.BulkUpdatePricing)
 
 	}
 	// coursequizz Routes
@@ -74,10 +111,13 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 	{
 		CourseQuizzGroup.GET("/all", CourseQuizzController.GetAllQuizzes)
 		CourseQuizzGroup.POST("/get", CourseQuizzController.GetQuizz)
-		CourseQuizzGroup.POST("/createCourseQuizz", CourseQuizzController.CreateQuizz)
-		CourseQuizzGroup.PUT("/updateCourseQuizz", CourseQuizzController.UpdateQuizz)
-		CourseQuizzGroup.DELETE("/DeleteCourseQuizz", CourseQuizzController.DeleteQuizz)
-		ArticleGroup.POST("/GetQuizzesByCourse", CourseQuizzController.GetQuizzesByCourse)
+		CourseQuizzGroup.POST("/createCourseQuizz", frozen, CourseQuizzController.CreateQuizz)
+		CourseQuizzGroup.PUT("/updateCourseQuizz", frozen, CourseQuizzController.UpdateQuizz)
+		CourseQuizzGroup.DELETE("/DeleteCourseQuizz", frozen, CourseQuizzController.DeleteQuizz)
+		CourseQuizzGroup.DELETE("/bulk", frozen, CourseQuizzController.DeleteQuizzesBulk)
+		CourseQuizzGroup.POST("/practice", CourseQuizzController.GetPracticeQuizzes)
+		CourseQuizzGroup.POST("/practice/grade", CourseQuizzController.GradePractice)
+		CourseQuizzGroup.POST("/GetQuizzesByCourse", CourseQuizzController.GetQuizzesByCourse)
 	}
 	// crating Routes
 	CratingController := controllers.NewCratingController(db)
@@ -86,7 +126,7 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 	{
 		CratingGroup.POST("/GetCratingsByCourse", CratingController.GetCratingsByCourse)
 		CratingGroup.POST("/GetCratingsByStudent", CratingController.GetCratingsByStudent)
-		CratingGroup.POST("/GetCratingByCourseAndStudent", CratingController.GetCratingsByStudent)
+		CratingGroup.POST("/GetCratingByCourseAndStudent", CratingController.GetCratingByCourseAndStudent)
 		CratingGroup.POST("/createCrating", CratingController.CreateCrating)
 		CratingGroup.PUT("/updateCrating", CratingController.UpdateCrating)
 		CratingGroup.DELETE("/DeleteCrating", CratingController.DeleteCrating)
@@ -100,10 +140,13 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 	{
 		ExamGroup.GET("/all", ExamController.GetAllExams)
 		ExamGroup.POST("/get", ExamController.GetExam)
-		ExamGroup.POST("/createExam", ExamController.CreateExam)
-		ExamGroup.PUT("/updateExam", ExamController.UpdateExam)
-		ExamGroup.DELETE("/DeleteExam", ExamController.DeleteExam)
+		ExamGroup.POST("/createExam", frozen, ExamController.CreateExam)
+		ExamGroup.PUT("/updateExam", frozen, ExamController.UpdateExam)
+		ExamGroup.DELETE("/DeleteExam", frozen, ExamController.DeleteExam)
 		ExamGroup.POST("/GetExamsByCourse", ExamController.GetExamsByCourse)
+		ExamGroup.POST("/preview", ExamController.PreviewExam)
+		ExamGroup.POST("/archive", frozen, ExamController.ArchiveExam)
+		ExamGroup.POST("/unarchive", frozen, ExamController.UnarchiveExam)
 	}
 
 	// ExamQuiz Routes
@@ -114,9 +157,12 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 		ExamQuizGroup.GET("/all", ExamQuizController.GetAllExamQuizzes)
 		ExamQuizGroup.POST("/get", ExamQuizController.GetExamQuizz)
 		ExamQuizGroup.POST("/GetExamQuizzesByExam", ExamQuizController.GetExamQuizzesByExam)
-		ExamQuizGroup.POST("/createExamQuiz", ExamQuizController.CreateExamQuizz)
-		ExamQuizGroup.PUT("/updateExamQuiz", ExamQuizController.UpdateExamQuizz)
-		ExamQuizGroup.DELETE("/DeleteExamQuiz", ExamQuizController.DeleteExamQuizz)
+		ExamQuizGroup.POST("/createExamQuiz", frozen, ExamQuizController.CreateExamQuizz)
+		ExamQuizGroup.POST("/createExamQuizzesBulk", frozen, ExamQuizController.CreateExamQuizzesBulk)
+		ExamQuizGroup.POST("/importCSV", frozen, ExamQuizController.ImportExamQuizzesCSV)
+		ExamQuizGroup.PUT("/updateExamQuiz", frozen, ExamQuizController.UpdateExamQuizz)
+		ExamQuizGroup.DELETE("/DeleteExamQuiz", frozen, ExamQuizController.DeleteExamQuizz)
+		ExamQuizGroup.DELETE("/bulk", frozen, ExamQuizController.DeleteExamQuizzesBulk)
 	}
 	// feedback Routes
 	FeedbackQuizController := controllers.NewFeedbackController(db)
@@ -126,10 +172,24 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 		FeedbackGroup.GET("/all", FeedbackQuizController.GetAllFeedbacks)
 		FeedbackGroup.POST("/get", FeedbackQuizController.GetFeedback)
 		FeedbackGroup.POST("/getFeedbacksByStudent", FeedbackQuizController.GetFeedbacksByStudent)
-		FeedbackGroup.POST("/createFeedback", FeedbackQuizController.CreateFeedback)
+		FeedbackGroup.POST("/getFeedbacksByCourse", FeedbackQuizController.GetFeedbacksByCourse)
+		FeedbackGroup.POST("/createFeedback", middlewares.RateLimitPerUser(writeRateLimit, writeRateWindow), FeedbackQuizController.CreateFeedback)
 		FeedbackGroup.PUT("/updateFeedback", FeedbackQuizController.UpdateFeedback)
 		FeedbackGroup.DELETE("/DeleteFeedback", FeedbackQuizController.DeleteFeedback)
 	}
+	// Video Routes
+	VideoController := controllers.NewVideoController(db)
+	VideoGroup := router.Group("/videos")
+	VideoGroup.Use(middlewares.AuthMiddleware())
+	{
+		VideoGroup.GET("/all", VideoController.GetAllVideos)
+		VideoGroup.POST("/get", VideoController.GetVideo)
+		VideoGroup.POST("/GetVideosByCourse", VideoController.GetVideosByCourse)
+		VideoGroup.POST("/createVideo", frozen, VideoController.CreateVideo)
+		VideoGroup.PUT("/updateVideo", frozen, VideoController.UpdateVideo)
+		VideoGroup.DELETE("/DeleteVideo", frozen, VideoController.DeleteVideo)
+		VideoGroup.POST("/progress/batch", VideoController.BatchUpdateProgress)
+	}
 	// Question Routes
 	QuestionkQuizController := controllers.NewQuestionController(db)
 	QuestionGroup := router.Group("/questions")
@@ -137,7 +197,10 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 	{
 		QuestionGroup.GET("/all", QuestionkQuizController.GetAllQuestions)
 		QuestionGroup.POST("/get", QuestionkQuizController.GetQuestion)
-		QuestionGroup.POST("/createQuestion", QuestionkQuizController.CreateQuestion)
+		QuestionGroup.POST("/unanswered", QuestionkQuizController.GetUnansweredByCourse)
+		QuestionGroup.POST("/byCourse", QuestionkQuizController.GetQuestionsWithAnswersByCourse)
+		QuestionGroup.POST("/stats", QuestionkQuizController.GetCourseQuestionStats)
+		QuestionGroup.POST("/createQuestion", middlewares.RateLimitPerUser(writeRateLimit, writeRateWindow), QuestionkQuizController.CreateQuestion)
 		QuestionGroup.PUT("/updateQuestion", QuestionkQuizController.UpdateQuestion)
 		QuestionGroup.DELETE("/DeleteQuestion", QuestionkQuizController.DeleteQuestion)
 	}
@@ -148,8 +211,17 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 	StudentCourseGroup.Use(middlewares.AuthMiddleware())
 	{
 		StudentCourseGroup.GET("/all", studentCourseController.GetAllStudentCourses)
+		StudentCourseGroup.GET("/mine", studentCourseController.GetMyCourses)
 		StudentCourseGroup.POST("/get", studentCourseController.GetStudentCourse)
+		StudentCourseGroup.POST("/standing", studentCourseController.GetStudentCourseStanding)
 		StudentCourseGroup.POST("/SubmitExamAnswers", studentCourseController.SubmitExamAnswers)
+		StudentCourseGroup.POST("/resendCertificate", studentCourseController.ResendCertificate)
+		StudentCourseGroup.GET("/certificate", studentCourseController.DownloadCertificate)
+		StudentCourseGroup.POST("/GetExamSubmission", studentCourseController.GetExamSubmission)
+		StudentCourseGroup.POST("/examHistory", studentCourseController.GetExamHistory)
+		StudentCourseGroup.POST("/enrolledStudents", studentCourseController.GetEnrolledStudents)
+		StudentCourseGroup.POST("/enrollmentCount", studentCourseController.GetEnrollmentCount)
+		StudentCourseGroup.GET("/exportEnrollments", middlewares.RequireRole(auth.RoleAdmin), studentCourseController.ExportEnrollmentsCSV)
 		StudentCourseGroup.POST("/createStudentCourse", studentCourseController.CreateStudentCourse)
 		StudentCourseGroup.PUT("/updateStudentCourse", studentCourseController.UpdateStudentCourse)
 		StudentCourseGroup.DELETE("/DeleteStudentCourse", studentCourseController.DeleteStudentCourse)
@@ -157,23 +229,36 @@ func InitRoutes(router *gin.Engine, db *sql.DB) {
 	// Student Routes
 	StudentCourseController := controllers.NewStudentController(db)
 	StudentGroup := router.Group("/students")
+	StudentGroup.POST("/login", loginLimit, controllers.GenerateToken)
 	StudentGroup.POST("/CreateStudent", StudentCourseController.CreateStudent)
 	StudentGroup.Use(middlewares.AuthMiddleware())
 	{
 		StudentGroup.GET("/all", StudentCourseController.GetAllStudents)
-		StudentGroup.POST("/GetStudent/:id", StudentCourseController.GetStudent)
+		StudentGroup.POST("/GetStudent", StudentCourseController.GetStudent)
 		StudentGroup.PUT("/UpdateUser", StudentCourseController.UpdateStudent)
 		StudentGroup.DELETE("/DeleteUser", StudentCourseController.DeleteStudent)
 	}
+	// Admin Routes
+	AdminController := controllers.NewAdminController(db)
+	AdminGroup := router.Group("/admin")
+	AdminGroup.Use(middlewares.AuthMiddleware(), middlewares.RequireRole(auth.RoleAdmin))
+	{
+		AdminGroup.POST("/recomputeRatings", AdminController.RecomputeRatings)
+		AdminGroup.GET("/schema", AdminController.GetSchema)
+		AdminGroup.GET("/contentFreeze", AdminController.GetContentFreeze)
+		AdminGroup.PUT("/contentFreeze", AdminController.SetContentFreeze)
+		AdminGroup.GET("/studentActivity", AdminController.GetStudentActivity)
+	}
 	// Teacher Routes
 	TeacherCourseController := controllers.NewTeacherController(db)
 	TeacherGroup := router.Group("/teachers")
-	TeacherGroup.POST("/login", controllers.GenerateToken)
+	TeacherGroup.POST("/login", loginLimit, controllers.GenerateToken)
 	TeacherGroup.POST("/CreateTeacher", TeacherCourseController.CreateTeacher)
 	TeacherGroup.Use(middlewares.AuthMiddleware())
 	{
 		TeacherGroup.GET("/all", TeacherCourseController.GetAllTeachers)
 		TeacherGroup.POST("/GetTeacher", TeacherCourseController.GetTeacher)
+		TeacherGroup.POST("/byCategory", TeacherCourseController.GetTeachersByCategory)
 		TeacherGroup.PUT("/UpdateTeacher", TeacherCourseController.UpdateTeacher)
 		TeacherGroup.DELETE("/DeleteTeacher", TeacherCourseController.DeleteTeacher)
 	}
@@ -0,0 +1,110 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cuddest/dz-skills/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// routeHandlers maps "METHOD path" to the name of the handler serving it
+func routeHandlers(t *testing.T) map[string]string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	InitRoutes(router, nil)
+
+	handlers := make(map[string]string)
+	for _, route := range router.Routes() {
+		handlers[route.Method+" "+route.Path] = route.Handler
+	}
+	return handlers
+}
+
+func TestRoutesServeTheirHandler(t *testing.T) {
+	tests := []struct {
+		route   string
+		handler string
+	}{
+		{"POST /cratings/GetCratingByCourseAndStudent", "GetCratingByCourseAndStudent"},
+		{"POST /cratings/GetCratingsByStudent", "GetCratingsByStudent"},
+		{"POST /coursequizzs/GetQuizzesByCourse", "GetQuizzesByCourse"},
+		{"POST /students/GetStudent", "GetStudent"},
+		{"POST /students/login", "GenerateToken"},
+		{"POST /teachers/login", "GenerateToken"},
+	}
+
+	handlers := routeHandlers(t)
+	for _, tt := range tests {
+		got, ok := handlers[tt.route]
+		if !ok {
+			t.Errorf("%s is not registered", tt.route)
+			continue
+		}
+		// Methods are named with a -fm suffix, plain functions without
+		if !strings.HasSuffix(strings.TrimSuffix(got, "-fm"), "."+tt.handler) {
+			t.Errorf("%s is served by %s, want %s", tt.route, got, tt.handler)
+		}
+	}
+}
+
+// Login must be reachable without a token, a bad body is rejected by the
+// handler rather than by the auth middleware
+func TestLoginRoutesArePublic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	InitRoutes(router, nil)
+
+	for _, path := range []string{"/students/login", "/teachers/login"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("{"))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("POST %s without a token = %d, want 400", path, w.Code)
+		}
+	}
+}
+
+// GetStudent takes the ID in the body like every other lookup, behind the
+// auth middleware
+func TestGetStudentRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	InitRoutes(router, nil)
+
+	token, err := auth.GenerateJWT("root@example.com", "root", auth.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"without a token", "", http.StatusUnauthorized},
+		// An ID-less body is rejected by the handler before any lookup
+		{"with a token", "Bearer " + token, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/students/GetStudent", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		if tt.token != "" {
+			req.Header.Set("Authorization", tt.token)
+		}
+		router.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("POST /students/GetStudent %s = %d, want %d: %s", tt.name, w.Code, tt.status, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/students/GetStudent/5", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("the old path form answered %d, want 404", w.Code)
+	}
+}